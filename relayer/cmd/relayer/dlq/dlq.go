@@ -0,0 +1,166 @@
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/spf13/cobra"
+
+	"github.com/henriquemarlon/city.fun/relayer/configs"
+	kafkaconfig "github.com/henriquemarlon/city.fun/relayer/configs/kafka"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
+)
+
+var (
+	kafkaBroker     string
+	deadLetterTopic string
+	limit           int
+	replayTopic     string
+	confirmPurge    bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Inspect, replay or purge dead-lettered Kafka messages",
+	Long: "Inspect, replay or purge dead-lettered Kafka messages. It reads the dead-letter topic " +
+		"of a kafka:// sink: the dead letters of other sinks are read with the tools of their " +
+		"transport.",
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Print dead-lettered messages as JSON lines, without consuming them",
+	RunE:  inspect,
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Publish dead-lettered messages back to their original topic",
+	Long: "Publish dead-lettered messages back to their original topic. Progress is committed " +
+		"on a dedicated consumer group, so every message is replayed only once.",
+	RunE: replay,
+}
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete every message currently stored in the dead-letter topic",
+	RunE:  purge,
+}
+
+func init() {
+	Cmd.PersistentFlags().StringVar(&kafkaBroker, "kafka-broker", "", "Kafka broker URL (defaults to RELAYER_KAFKA_BROKER)")
+	Cmd.PersistentFlags().StringVar(&deadLetterTopic, "topic", "", "Dead-letter topic (defaults to RELAYER_KAFKA_DEAD_LETTER_TOPIC)")
+
+	inspectCmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of messages to print, 0 for all")
+	replayCmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of messages to replay, 0 for all")
+	replayCmd.Flags().StringVar(&replayTopic, "to", "", "Publish to this topic instead of the original one")
+	purgeCmd.Flags().BoolVar(&confirmPurge, "yes", false, "Confirm the deletion")
+
+	Cmd.AddCommand(inspectCmd, replayCmd, purgeCmd)
+}
+
+func newDeadLetterQueue() (*kafka.DeadLetterQueue, *ckafka.ConfigMap, error) {
+	configs.SetDefaults()
+
+	if deadLetterTopic == "" {
+		topic, err := configs.GetKafkaDeadLetterTopic()
+		if err != nil {
+			return nil, nil, err
+		}
+		deadLetterTopic = topic
+	}
+
+	configMap, err := kafkaconfig.GetClientConfigMap()
+	if err != nil {
+		return nil, nil, err
+	}
+	if kafkaBroker != "" {
+		(*configMap)["bootstrap.servers"] = kafkaBroker
+	}
+	return kafka.NewDeadLetterQueue(configMap, deadLetterTopic), configMap, nil
+}
+
+func inspect(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	queue, _, err := newDeadLetterQueue()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	count, err := queue.Inspect(ctx, limit, func(msg *ckafka.Message, deadLetter *kafka.DeadLetter) error {
+		return encoder.Encode(struct {
+			Partition int32 `json:"dlq_partition"`
+			Offset    int64 `json:"dlq_offset"`
+			*kafka.DeadLetter
+		}{msg.TopicPartition.Partition, int64(msg.TopicPartition.Offset), deadLetter})
+	})
+	fmt.Fprintf(cmd.ErrOrStderr(), "%d dead letters in %s\n", count, deadLetterTopic)
+	return err
+}
+
+func replay(cmd *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	queue, configMap, err := newDeadLetterQueue()
+	if err != nil {
+		return err
+	}
+
+	producer, err := kafka.NewKafkaProducer(configMap)
+	if err != nil {
+		return err
+	}
+	defer producer.Close(5000)
+
+	count, err := queue.Replay(ctx, limit, func(deadLetter *kafka.DeadLetter) error {
+		topic := deadLetter.Topic
+		if replayTopic != "" {
+			topic = replayTopic
+		}
+		if topic == "" {
+			return fmt.Errorf("dead letter at offset %d has no original topic, use --to", deadLetter.Offset)
+		}
+
+		var key []byte
+		if deadLetter.Key != "" {
+			key = []byte(deadLetter.Key)
+		}
+		if err := producer.Publish(ctx, topic, key, []byte(deadLetter.Payload), nil); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "replayed %s[%d]@%d to %s\n",
+			deadLetter.Topic, deadLetter.Partition, deadLetter.Offset, topic)
+		return nil
+	})
+	fmt.Fprintf(cmd.ErrOrStderr(), "%d dead letters replayed from %s\n", count, deadLetterTopic)
+	return err
+}
+
+func purge(cmd *cobra.Command, args []string) error {
+	if !confirmPurge {
+		return fmt.Errorf("purge deletes every dead letter, run again with --yes to confirm")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	queue, _, err := newDeadLetterQueue()
+	if err != nil {
+		return err
+	}
+
+	count, err := queue.Purge(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%d dead letters purged from %s\n", count, deadLetterTopic)
+	return nil
+}
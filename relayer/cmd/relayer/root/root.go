@@ -2,18 +2,27 @@ package root
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"time"
 
-	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/hashicorp/go-retryablehttp"
 
+	"github.com/henriquemarlon/city.fun/relayer/cmd/relayer/dlq"
 	"github.com/henriquemarlon/city.fun/relayer/configs"
+	kafkaconfig "github.com/henriquemarlon/city.fun/relayer/configs/kafka"
 	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository/factory"
 	"github.com/henriquemarlon/city.fun/relayer/internal/infra/service/relayer"
 	"github.com/henriquemarlon/city.fun/relayer/internal/infra/version"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/alert"
 	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
 	"github.com/henriquemarlon/city.fun/relayer/pkg/service"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/sink"
+	sinkfactory "github.com/henriquemarlon/city.fun/relayer/pkg/sink/factory"
+	sourcefactory "github.com/henriquemarlon/city.fun/relayer/pkg/source/factory"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/tracing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -21,6 +30,7 @@ import (
 const serviceName = "relayer"
 
 var (
+	configFile             string
 	logColor               bool
 	logLevel               string
 	authKind               string
@@ -31,6 +41,7 @@ var (
 	authMnemonicIndex      uint32
 	kafkaBroker            string
 	kafkaTopics            string
+	kafkaSeek              string
 	databaseUrl            string
 	databaseUrlFile        string
 	databaseName           string
@@ -53,6 +64,8 @@ var Cmd = &cobra.Command{
 
 func init() {
 	// Logging flags
+	Cmd.Flags().StringVar(&configFile, "config", "", "Config file with the same keys as the environment variables, read again on SIGHUP. Environment variables take precedence over it")
+	cobra.CheckErr(viper.BindPFlag("config", Cmd.Flags().Lookup("config")))
 	Cmd.Flags().BoolVar(&logColor, "log-color", true, "Tint the logs (colored output)")
 	cobra.CheckErr(viper.BindPFlag(configs.LOG_COLOR, Cmd.Flags().Lookup("log-color")))
 	Cmd.Flags().StringVar(&logLevel, "log-level", "info", "Log level: debug, info, warn or error")
@@ -65,7 +78,7 @@ func init() {
 	cobra.CheckErr(viper.BindPFlag(configs.BLOCKCHAIN_HTTP_ENDPOINT, Cmd.Flags().Lookup("blockchain-http-endpoint")))
 
 	// Auth flags
-	Cmd.Flags().StringVar(&authKind, "auth-kind", "private_key", "Auth kind: private_key, private_key_file, mnemonic, mnemonic_file")
+	Cmd.Flags().StringVar(&authKind, "auth-kind", "private_key", "Auth kind: private_key, private_key_file, mnemonic, mnemonic_file, aws, web3signer, clef")
 	cobra.CheckErr(viper.BindPFlag(configs.AUTH_KIND, Cmd.Flags().Lookup("auth-kind")))
 	Cmd.Flags().StringVar(&authPrivateKey, "auth-private-key", "", "Private key for signing transactions")
 	cobra.CheckErr(viper.BindPFlag(configs.AUTH_PRIVATE_KEY, Cmd.Flags().Lookup("auth-private-key")))
@@ -83,6 +96,8 @@ func init() {
 	cobra.CheckErr(viper.BindPFlag(configs.KAFKA_BROKER, Cmd.Flags().Lookup("kafka-broker")))
 	Cmd.Flags().StringVar(&kafkaTopics, "kafka-topics", "reward_granted", "Comma-separated list of Kafka topics to consume")
 	cobra.CheckErr(viper.BindPFlag(configs.KAFKA_TOPICS, Cmd.Flags().Lookup("kafka-topics")))
+	Cmd.Flags().StringVar(&kafkaSeek, "kafka-seek", "", "Move the consumer group on startup: earliest, latest, offset:<offset> or timestamp:<RFC 3339 time>")
+	cobra.CheckErr(viper.BindPFlag(configs.KAFKA_SEEK, Cmd.Flags().Lookup("kafka-seek")))
 
 	// Database flags
 	Cmd.Flags().StringVar(&databaseUrl, "database-url", "", "MongoDB connection URL")
@@ -104,6 +119,9 @@ func init() {
 	Cmd.Flags().StringVar(&rewardToken, "reward-token-address", "", "Reward token address")
 	cobra.CheckErr(viper.BindPFlag(configs.REWARD_TOKEN_ADDRESS, Cmd.Flags().Lookup("reward-token-address")))
 
+	// Subcommands
+	Cmd.AddCommand(dlq.Cmd)
+
 	Cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		var err error
 		cfg, err = configs.LoadRelayerConfig()
@@ -118,10 +136,15 @@ func run(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Shared by every logger, so that a reload changes the level of all of them.
+	logLevelVar := new(slog.LevelVar)
+	logLevelVar.Set(cfg.LogLevel)
+
 	createInfo := relayer.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 serviceName,
 			LogLevel:             cfg.LogLevel,
+			LogLevelVar:          logLevelVar,
 			LogColor:             cfg.LogColor,
 			EnableSignalHandling: true,
 			TelemetryCreate:      true,
@@ -131,8 +154,22 @@ func run(cmd *cobra.Command, args []string) {
 		Config: *cfg,
 	}
 
+	tracingEndpoint, _ := configs.GetTracingEndpoint()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName:    serviceName,
+		ServiceVersion: version.BuildVersion,
+		Endpoint:       tracingEndpoint,
+		SampleRatio:    cfg.TracingSampleRatio,
+	})
+	cobra.CheckErr(err)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
+
 	rclient := retryablehttp.NewClient()
-	rclient.Logger = service.NewLogger(cfg.LogLevel, cfg.LogColor).With("service", serviceName)
+	rclient.Logger = service.NewLogger(logLevelVar, cfg.LogColor).With("service", serviceName)
 	rclient.RetryMax = int(cfg.BlockchainHttpMaxRetries)
 	rclient.RetryWaitMin = cfg.BlockchainHttpRetryMinWait
 	rclient.RetryWaitMax = cfg.BlockchainHttpRetryMaxWait
@@ -141,7 +178,6 @@ func run(cmd *cobra.Command, args []string) {
 		rpc.WithHTTPClient(rclient.StandardClient()),
 	}
 
-	var err error
 	rpcClient, err := rpc.DialOptions(ctx, cfg.BlockchainHttpEndpoint.String(), clientOptions...)
 	cobra.CheckErr(err)
 	createInfo.EthClient = ethclient.NewClient(rpcClient)
@@ -156,17 +192,82 @@ func run(cmd *cobra.Command, args []string) {
 
 	defer createInfo.Repository.Close()
 
-	configMap := &ckafka.ConfigMap{
-		"bootstrap.servers":  cfg.KafkaBroker.String(),
-		"group.id":           "city-fun-" + serviceName,
-		"auto.offset.reset":  "latest",
-		"enable.auto.commit": false,
+	consumerConfig, err := kafkaconfig.GetConsumerConfigMap()
+	cobra.CheckErr(err)
+
+	sourceOptions := sourcefactory.Options{
+		Topics:         cfg.KafkaTopics,
+		Name:           "city-fun-" + serviceName,
+		Logger:         service.NewLogger(logLevelVar, cfg.LogColor).With("service", serviceName),
+		DrainTimeout:   cfg.KafkaDrainTimeout,
+		KafkaConfigMap: consumerConfig,
+	}
+
+	seek, err := configs.GetKafkaSeek()
+	if err != nil && !errors.Is(err, configs.ErrNotDefined) {
+		cobra.CheckErr(err)
+	} else if err == nil {
+		sourceOptions.KafkaStartAt, err = kafka.ParseStartPosition(seek)
+		cobra.CheckErr(err)
+	}
+
+	createInfo.Source, err = sourcefactory.NewSourceFromConnectionString(ctx, cfg.SourceUrl.Value, sourceOptions)
+	cobra.CheckErr(err)
+
+	producerConfig, err := kafkaconfig.GetClientConfigMap()
+	cobra.CheckErr(err)
+
+	sinkOptions := sinkfactory.Options{
+		Name:           "city-fun-" + serviceName + "-sink",
+		KafkaConfigMap: producerConfig,
 	}
 
-	createInfo.KafkaConsumer = kafka.NewKafkaConsumer(configMap, cfg.KafkaTopics)
+	createInfo.Sink, err = sinkfactory.NewSinkFromConnectionString(ctx, cfg.SinkUrl.Value, sinkOptions)
+	cobra.CheckErr(err)
+
+	createInfo.AlertRules, createInfo.AlertPublishers, err = alerts(ctx, createInfo.Sink)
+	cobra.CheckErr(err)
 
 	relayer, err := relayer.Create(ctx, &createInfo)
 	cobra.CheckErr(err)
 
 	cobra.CheckErr(relayer.Serve())
 }
+
+// alerts reads the optional alert rules and the destinations their events are published to.
+func alerts(ctx context.Context, sink sink.Sink) ([]alert.Rule, []alert.Publisher, error) {
+	value, err := configs.GetAlertRules()
+	if errors.Is(err, configs.ErrNotDefined) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	rules, err := alert.ParseRules(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var publishers []alert.Publisher
+	if topic, err := configs.GetAlertKafkaTopic(); err == nil {
+		publishers = append(publishers, alert.NewSinkPublisher(sink, topic))
+	} else if !errors.Is(err, configs.ErrNotDefined) {
+		return nil, nil, err
+	}
+	if conn, err := configs.GetAlertMqttUrl(); err == nil {
+		publisher, err := alert.NewMQTTPublisher(ctx, conn.Value, cfg.AlertMqttTopic)
+		if err != nil {
+			return nil, nil, err
+		}
+		publishers = append(publishers, publisher)
+	} else if !errors.Is(err, configs.ErrNotDefined) {
+		return nil, nil, err
+	}
+	if urls, err := configs.GetAlertWebhookUrls(); err == nil {
+		for _, url := range urls {
+			publishers = append(publishers, alert.NewWebhookPublisher(url))
+		}
+	} else if !errors.Is(err, configs.ErrNotDefined) {
+		return nil, nil, err
+	}
+	return rules, publishers, nil
+}
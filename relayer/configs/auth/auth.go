@@ -1,17 +1,24 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	. "github.com/henriquemarlon/city.fun/relayer/configs"
 	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/signer"
 )
 
-func GetTransactOpts(chainId *big.Int) (*bind.TransactOpts, error) {
+func GetTransactOpts(ctx context.Context, chainId *big.Int) (*bind.TransactOpts, error) {
 	authKind, err := GetAuthKind()
 	if err != nil {
 		return nil, err
@@ -41,8 +48,73 @@ func GetTransactOpts(chainId *big.Int) (*bind.TransactOpts, error) {
 			return nil, err
 		}
 		return bind.NewKeyedTransactorWithChainID(key, chainId)
+	case AuthKindAWS:
+		kmsSigner, err := newKMSSigner(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return transactOpts(kmsSigner, chainId)
+	case AuthKindWeb3Signer, AuthKindClef:
+		flavor := signer.Web3Signer
+		if authKind == AuthKindClef {
+			flavor = signer.Clef
+		}
+		rpcSigner, err := newRPCSigner(ctx, flavor)
+		if err != nil {
+			return nil, err
+		}
+		return transactOpts(rpcSigner, chainId)
 
 	default:
 		return nil, fmt.Errorf("no valid authentication method found")
 	}
 }
+
+func transactOpts(s signer.Signer, chainId *big.Int) (*bind.TransactOpts, error) {
+	timeout, err := GetAuthSignerTimeout()
+	if err != nil {
+		return nil, err
+	}
+	return signer.NewTransactOpts(s, chainId, timeout), nil
+}
+
+func newKMSSigner(ctx context.Context) (*signer.KMS, error) {
+	keyId, err := GetAuthAwsKmsKeyId()
+	if err != nil {
+		return nil, err
+	}
+	var loadOptions []func(*awsconfig.LoadOptions) error
+	if region, err := GetAuthAwsRegion(); err == nil {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(region))
+	} else if !errors.Is(err, ErrNotDefined) {
+		return nil, err
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	var kmsOptions []func(*kms.Options)
+	if endpoint, err := GetAuthAwsEndpointUrl(); err == nil {
+		kmsOptions = append(kmsOptions, func(o *kms.Options) { o.BaseEndpoint = aws.String(endpoint) })
+	} else if !errors.Is(err, ErrNotDefined) {
+		return nil, err
+	}
+	return signer.NewKMS(ctx, kms.NewFromConfig(awsConfig, kmsOptions...), keyId)
+}
+
+func newRPCSigner(ctx context.Context, flavor signer.Flavor) (*signer.RPC, error) {
+	url, err := GetAuthSignerUrl()
+	if err != nil {
+		return nil, err
+	}
+	var address common.Address
+	if value, err := GetAuthSignerAddress(); err == nil {
+		if address, err = ethutil.ParseAddress(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", AUTH_SIGNER_ADDRESS, err)
+		}
+	} else if !errors.Is(err, ErrNotDefined) {
+		return nil, err
+	}
+	return signer.NewRPC(ctx, url, flavor, address)
+}
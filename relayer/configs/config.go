@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"net/url"
 	"strconv"
 	"strings"
@@ -30,6 +31,8 @@ type (
 	RedactedString = Redacted[string]
 	RedactedUint   = Redacted[uint32]
 	Address        = common.Address
+	Wei            = *big.Int
+	Percentile     = uint64
 )
 
 // ------------------------------------------------------------------------------------------------
@@ -44,8 +47,30 @@ const (
 	AuthKindMnemonicVar
 	AuthKindMnemonicFile
 	AuthKindAWS
+	AuthKindWeb3Signer
+	AuthKindClef
 )
 
+// ------------------------------------------------------------------------------------------------
+// API tokens
+// ------------------------------------------------------------------------------------------------
+
+// placeholderTokens are the example values of the API tokens, which must be replaced.
+var placeholderTokens = []string{"change-me", "changeme", "change_me", "secret", "token", "your_admin_token_here"}
+
+// CheckToken rejects an API token that is unset or still one of the placeholders.
+func CheckToken(token string) error {
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("token is empty")
+	}
+	for _, placeholder := range placeholderTokens {
+		if strings.EqualFold(strings.TrimSpace(token), placeholder) {
+			return fmt.Errorf("token is the placeholder %q", placeholder)
+		}
+	}
+	return nil
+}
+
 // ------------------------------------------------------------------------------------------------
 // Parsing functions
 // ------------------------------------------------------------------------------------------------
@@ -62,6 +87,10 @@ func ToUint64FromDecimalOrHexString(s string) (uint64, error) {
 	return ToUint64FromString(s)
 }
 
+func ToFloat64FromString(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
 func ToStringFromString(s string) (string, error) {
 	return s, nil
 }
@@ -82,6 +111,22 @@ func ToAddressFromString(s string) (Address, error) {
 	return common.BytesToAddress(b), nil
 }
 
+func ToWeiFromString(s string) (Wei, error) {
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid wei amount '%s'", s)
+	}
+	return value, nil
+}
+
+func ToPercentileFromString(s string) (Percentile, error) {
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || value > 100 {
+		return 0, fmt.Errorf("invalid percentile '%s', expected 0 to 100", s)
+	}
+	return value, nil
+}
+
 func ToLogLevelFromString(s string) (LogLevel, error) {
 	var m = map[string]LogLevel{
 		"debug": slog.LevelDebug,
@@ -134,6 +179,8 @@ func ToAuthKindFromString(s string) (AuthKind, error) {
 		"mnemonic":         AuthKindMnemonicVar,
 		"mnemonic_file":    AuthKindMnemonicFile,
 		"aws":              AuthKindAWS,
+		"web3signer":       AuthKindWeb3Signer,
+		"clef":             AuthKindClef,
 	}
 	if v, ok := m[s]; ok {
 		return v, nil
@@ -148,6 +195,7 @@ var (
 	toBool           = strconv.ParseBool
 	toUint64         = ToUint64FromString
 	toString         = ToStringFromString
+	toFloat64        = ToFloat64FromString
 	toDuration       = ToDurationFromSeconds
 	toLogLevel       = ToLogLevelFromString
 	toRedactedUint   = ToRedactedUint32FromString
@@ -156,12 +204,15 @@ var (
 	toSliceString    = ToSliceStringFromString
 	toAddress        = ToAddressFromString
 	toAuthKind       = ToAuthKindFromString
+	toWei            = ToWeiFromString
+	toPercentile     = ToPercentileFromString
 )
 
 var (
 	notDefinedBool           = func() bool { return false }
 	notDefinedUint64         = func() uint64 { return 0 }
 	notDefinedString         = func() string { return "" }
+	notDefinedFloat64        = func() float64 { return 0 }
 	notDefinedDuration       = func() time.Duration { return 0 }
 	notDefinedLogLevel       = func() slog.Level { return slog.LevelInfo }
 	notDefinedRedactedString = func() RedactedString { return RedactedString{""} }
@@ -170,4 +221,6 @@ var (
 	notDefinedSliceString    = func() []string { return []string{} }
 	notDefinedAddress        = func() Address { return common.Address{} }
 	notDefinedAuthKind       = func() AuthKind { return AuthKindPrivateKeyVar }
+	notDefinedWei            = func() Wei { return new(big.Int) }
+	notDefinedPercentile     = func() Percentile { return 0 }
 )
@@ -19,39 +19,138 @@ func init() {
 }
 
 const (
-	AUTH_KIND                      = "RELAYER_AUTH_KIND"
-	AUTH_MNEMONIC                  = "RELAYER_AUTH_MNEMONIC"
-	AUTH_MNEMONIC_ACCOUNT_INDEX    = "RELAYER_AUTH_MNEMONIC_ACCOUNT_INDEX"
-	AUTH_PRIVATE_KEY               = "RELAYER_AUTH_PRIVATE_KEY"
-	BLOCKCHAIN_HTTP_ENDPOINT       = "RELAYER_BLOCKCHAIN_HTTP_ENDPOINT"
-	BLOCKCHAIN_HTTP_MAX_RETRIES    = "RELAYER_BLOCKCHAIN_HTTP_MAX_RETRIES"
-	BLOCKCHAIN_HTTP_RETRY_MAX_WAIT = "RELAYER_BLOCKCHAIN_HTTP_RETRY_MAX_WAIT"
-	BLOCKCHAIN_HTTP_RETRY_MIN_WAIT = "RELAYER_BLOCKCHAIN_HTTP_RETRY_MIN_WAIT"
-	BLOCKCHAIN_ID                  = "RELAYER_BLOCKCHAIN_ID"
-	REWARD_TOKEN_ADDRESS           = "RELAYER_REWARD_TOKEN_ADDRESS"
-	DATABASE_COLLECTION            = "RELAYER_DATABASE_COLLECTION"
-	DATABASE_NAME                  = "RELAYER_DATABASE_NAME"
-	DATABASE_URL                   = "RELAYER_DATABASE_URL"
-	KAFKA_BROKER                   = "RELAYER_KAFKA_BROKER"
-	KAFKA_TOPICS                   = "RELAYER_KAFKA_TOPICS"
-	LOG_COLOR                      = "RELAYER_LOG_COLOR"
-	LOG_LEVEL                      = "RELAYER_LOG_LEVEL"
-	MAX_STARTUP_TIME               = "RELAYER_MAX_STARTUP_TIME"
-	TELEMETRY_ADDRESS              = "RELAYER_TELEMETRY_ADDRESS"
+	ALERT_KAFKA_TOPIC                 = "RELAYER_ALERT_KAFKA_TOPIC"
+	ALERT_MQTT_TOPIC                  = "RELAYER_ALERT_MQTT_TOPIC"
+	ALERT_MQTT_URL                    = "RELAYER_ALERT_MQTT_URL"
+	ALERT_RULES                       = "RELAYER_ALERT_RULES"
+	ALERT_WEBHOOK_URLS                = "RELAYER_ALERT_WEBHOOK_URLS"
+	ALERT_ZONE_SIZE                   = "RELAYER_ALERT_ZONE_SIZE"
+	AQI_STANDARD                      = "RELAYER_AQI_STANDARD"
+	AUTH_AWS_ENDPOINT_URL             = "RELAYER_AUTH_AWS_ENDPOINT_URL"
+	AUTH_AWS_KMS_KEY_ID               = "RELAYER_AUTH_AWS_KMS_KEY_ID"
+	AUTH_AWS_REGION                   = "RELAYER_AUTH_AWS_REGION"
+	AUTH_KIND                         = "RELAYER_AUTH_KIND"
+	AUTH_MNEMONIC                     = "RELAYER_AUTH_MNEMONIC"
+	AUTH_MNEMONIC_ACCOUNT_INDEX       = "RELAYER_AUTH_MNEMONIC_ACCOUNT_INDEX"
+	AUTH_PRIVATE_KEY                  = "RELAYER_AUTH_PRIVATE_KEY"
+	AUTH_SIGNER_ADDRESS               = "RELAYER_AUTH_SIGNER_ADDRESS"
+	AUTH_SIGNER_TIMEOUT               = "RELAYER_AUTH_SIGNER_TIMEOUT"
+	AUTH_SIGNER_URL                   = "RELAYER_AUTH_SIGNER_URL"
+	BLOCKCHAIN_DAILY_BUDGET           = "RELAYER_BLOCKCHAIN_DAILY_BUDGET"
+	BLOCKCHAIN_FEE_HISTORY_BLOCKS     = "RELAYER_BLOCKCHAIN_FEE_HISTORY_BLOCKS"
+	BLOCKCHAIN_FEE_HISTORY_PERCENTILE = "RELAYER_BLOCKCHAIN_FEE_HISTORY_PERCENTILE"
+	BLOCKCHAIN_GAS_LIMIT              = "RELAYER_BLOCKCHAIN_GAS_LIMIT"
+	BLOCKCHAIN_GAS_PAUSE_INTERVAL     = "RELAYER_BLOCKCHAIN_GAS_PAUSE_INTERVAL"
+	BLOCKCHAIN_HTTP_ENDPOINT          = "RELAYER_BLOCKCHAIN_HTTP_ENDPOINT"
+	BLOCKCHAIN_HTTP_MAX_RETRIES       = "RELAYER_BLOCKCHAIN_HTTP_MAX_RETRIES"
+	BLOCKCHAIN_HTTP_RETRY_MAX_WAIT    = "RELAYER_BLOCKCHAIN_HTTP_RETRY_MAX_WAIT"
+	BLOCKCHAIN_HTTP_RETRY_MIN_WAIT    = "RELAYER_BLOCKCHAIN_HTTP_RETRY_MIN_WAIT"
+	BLOCKCHAIN_ID                     = "RELAYER_BLOCKCHAIN_ID"
+	BLOCKCHAIN_MAX_BASE_FEE           = "RELAYER_BLOCKCHAIN_MAX_BASE_FEE"
+	BLOCKCHAIN_MAX_PRIORITY_FEE       = "RELAYER_BLOCKCHAIN_MAX_PRIORITY_FEE"
+	REWARD_TOKEN_ADDRESS              = "RELAYER_REWARD_TOKEN_ADDRESS"
+	DATABASE_COLLECTION               = "RELAYER_DATABASE_COLLECTION"
+	DATABASE_NAME                     = "RELAYER_DATABASE_NAME"
+	DATABASE_URL                      = "RELAYER_DATABASE_URL"
+	ADMIN_TOKEN                       = "RELAYER_ADMIN_TOKEN"
+	DEVICE_SIGNATURES                 = "RELAYER_DEVICE_SIGNATURES"
+	ENROLLMENT_TOKEN                  = "RELAYER_ENROLLMENT_TOKEN"
+	PAYOUTS                           = "RELAYER_PAYOUTS"
+	REPLAY_WINDOW                     = "RELAYER_REPLAY_WINDOW"
+	HEALTH_CHECK_TIMEOUT              = "RELAYER_HEALTH_CHECK_TIMEOUT"
+	HEALTH_MAX_BLOCK_AGE              = "RELAYER_HEALTH_MAX_BLOCK_AGE"
+	HEALTH_MIN_BALANCE                = "RELAYER_HEALTH_MIN_BALANCE"
+	HISTORY_RETENTION                 = "RELAYER_HISTORY_RETENTION"
+	KAFKA_AUTO_OFFSET_RESET           = "RELAYER_KAFKA_AUTO_OFFSET_RESET"
+	KAFKA_BROKER                      = "RELAYER_KAFKA_BROKER"
+	KAFKA_DEAD_LETTER_TOPIC           = "RELAYER_KAFKA_DEAD_LETTER_TOPIC"
+	KAFKA_DRAIN_TIMEOUT               = "RELAYER_KAFKA_DRAIN_TIMEOUT"
+	KAFKA_EVENTS_MAX_FAILURES         = "RELAYER_KAFKA_EVENTS_MAX_FAILURES"
+	KAFKA_EVENTS_TOPIC                = "RELAYER_KAFKA_EVENTS_TOPIC"
+	KAFKA_GROUP_ID                    = "RELAYER_KAFKA_GROUP_ID"
+	KAFKA_HEARTBEAT_INTERVAL          = "RELAYER_KAFKA_HEARTBEAT_INTERVAL"
+	KAFKA_MAX_POLL_INTERVAL           = "RELAYER_KAFKA_MAX_POLL_INTERVAL"
+	KAFKA_PROPERTIES                  = "RELAYER_KAFKA_PROPERTIES"
+	KAFKA_RETRY_MAX_ATTEMPTS          = "RELAYER_KAFKA_RETRY_MAX_ATTEMPTS"
+	KAFKA_RETRY_MAX_WAIT              = "RELAYER_KAFKA_RETRY_MAX_WAIT"
+	KAFKA_RETRY_MIN_WAIT              = "RELAYER_KAFKA_RETRY_MIN_WAIT"
+	KAFKA_SASL_MECHANISM              = "RELAYER_KAFKA_SASL_MECHANISM"
+	KAFKA_SASL_PASSWORD               = "RELAYER_KAFKA_SASL_PASSWORD"
+	KAFKA_SASL_USERNAME               = "RELAYER_KAFKA_SASL_USERNAME"
+	KAFKA_SECURITY_PROTOCOL           = "RELAYER_KAFKA_SECURITY_PROTOCOL"
+	KAFKA_SEEK                        = "RELAYER_KAFKA_SEEK"
+	KAFKA_SESSION_TIMEOUT             = "RELAYER_KAFKA_SESSION_TIMEOUT"
+	KAFKA_STATS_INTERVAL              = "RELAYER_KAFKA_STATS_INTERVAL"
+	KAFKA_TLS_CA_FILE                 = "RELAYER_KAFKA_TLS_CA_FILE"
+	KAFKA_TLS_CERT_FILE               = "RELAYER_KAFKA_TLS_CERT_FILE"
+	KAFKA_TLS_KEY_FILE                = "RELAYER_KAFKA_TLS_KEY_FILE"
+	KAFKA_TLS_KEY_PASSWORD            = "RELAYER_KAFKA_TLS_KEY_PASSWORD"
+	KAFKA_TLS_SKIP_VERIFY             = "RELAYER_KAFKA_TLS_SKIP_VERIFY"
+	KAFKA_TOPICS                      = "RELAYER_KAFKA_TOPICS"
+	OUTBOX_CLAIM_TIMEOUT              = "RELAYER_OUTBOX_CLAIM_TIMEOUT"
+	OUTBOX_MAX_ATTEMPTS               = "RELAYER_OUTBOX_MAX_ATTEMPTS"
+	OUTBOX_POLL_INTERVAL              = "RELAYER_OUTBOX_POLL_INTERVAL"
+	OUTBOX_REPLACE_AFTER              = "RELAYER_OUTBOX_REPLACE_AFTER"
+	OUTBOX_RETENTION                  = "RELAYER_OUTBOX_RETENTION"
+	OUTBOX_RETRY_MAX_WAIT             = "RELAYER_OUTBOX_RETRY_MAX_WAIT"
+	OUTBOX_RETRY_MIN_WAIT             = "RELAYER_OUTBOX_RETRY_MIN_WAIT"
+	QUALITY_MIN_INTERVAL              = "RELAYER_QUALITY_MIN_INTERVAL"
+	QUALITY_NEIGHBOR_RADIUS           = "RELAYER_QUALITY_NEIGHBOR_RADIUS"
+	QUALITY_NEIGHBOR_TOLERANCE        = "RELAYER_QUALITY_NEIGHBOR_TOLERANCE"
+	QUALITY_NEIGHBOR_WINDOW           = "RELAYER_QUALITY_NEIGHBOR_WINDOW"
+	QUALITY_RANGES                    = "RELAYER_QUALITY_RANGES"
+	QUALITY_SPIKE_FACTOR              = "RELAYER_QUALITY_SPIKE_FACTOR"
+	QUALITY_STUCK_READINGS            = "RELAYER_QUALITY_STUCK_READINGS"
+	QUALITY_WITHHOLD_SCORE            = "RELAYER_QUALITY_WITHHOLD_SCORE"
+	RECEIVERS                         = "RELAYER_RECEIVERS"
+	RECEIVER_DAILY_CAP                = "RELAYER_RECEIVER_DAILY_CAP"
+	RECEIVER_DENYLIST                 = "RELAYER_RECEIVER_DENYLIST"
+	LOG_COLOR                         = "RELAYER_LOG_COLOR"
+	LOG_LEVEL                         = "RELAYER_LOG_LEVEL"
+	MAX_STARTUP_TIME                  = "RELAYER_MAX_STARTUP_TIME"
+	TELEMETRY_ADDRESS                 = "RELAYER_TELEMETRY_ADDRESS"
+	WORKER_COUNT                      = "RELAYER_WORKER_COUNT"
+	SINK_URL                          = "RELAYER_SINK_URL"
+	SOURCE_URL                        = "RELAYER_SOURCE_URL"
+	TRACING_ENDPOINT                  = "RELAYER_TRACING_ENDPOINT"
+	TRACING_SAMPLE_RATIO              = "RELAYER_TRACING_SAMPLE_RATIO"
 
 	// File variants
 
 	AUTH_MNEMONIC_FILE = "RELAYER_AUTH_MNEMONIC_FILE"
 
-	AUTH_PRIVATE_KEY_FILE         = "RELAYER_AUTH_PRIVATE_KEY_FILE"
+	AUTH_PRIVATE_KEY_FILE = "RELAYER_AUTH_PRIVATE_KEY_FILE"
+
 	BLOCKCHAIN_HTTP_ENDPOINT_FILE = "RELAYER_BLOCKCHAIN_HTTP_ENDPOINT_FILE"
 
 	DATABASE_URL_FILE = "RELAYER_DATABASE_URL_FILE"
+
+	KAFKA_SASL_PASSWORD_FILE = "RELAYER_KAFKA_SASL_PASSWORD_FILE"
 )
 
 func SetDefaults() {
 	// Set defaults based on the TOML definitions.
 
+	// no default for RELAYER_ALERT_KAFKA_TOPIC
+
+	viper.SetDefault(ALERT_MQTT_TOPIC, "city/alerts")
+
+	// no default for RELAYER_ALERT_MQTT_URL
+
+	// no default for RELAYER_ALERT_RULES
+
+	// no default for RELAYER_ALERT_WEBHOOK_URLS
+
+	viper.SetDefault(ALERT_ZONE_SIZE, "0.01")
+
+	viper.SetDefault(AQI_STANDARD, "us-epa")
+
+	// no default for RELAYER_AUTH_AWS_ENDPOINT_URL
+
+	// no default for RELAYER_AUTH_AWS_KMS_KEY_ID
+
+	// no default for RELAYER_AUTH_AWS_REGION
+
 	viper.SetDefault(AUTH_KIND, "AuthKindPrivateKeyVar")
 
 	// no default for RELAYER_AUTH_MNEMONIC
@@ -60,6 +159,22 @@ func SetDefaults() {
 
 	// no default for RELAYER_AUTH_PRIVATE_KEY
 
+	// no default for RELAYER_AUTH_SIGNER_ADDRESS
+
+	viper.SetDefault(AUTH_SIGNER_TIMEOUT, "10")
+
+	// no default for RELAYER_AUTH_SIGNER_URL
+
+	viper.SetDefault(BLOCKCHAIN_DAILY_BUDGET, "50000000000000000")
+
+	viper.SetDefault(BLOCKCHAIN_FEE_HISTORY_BLOCKS, "20")
+
+	viper.SetDefault(BLOCKCHAIN_FEE_HISTORY_PERCENTILE, "50")
+
+	viper.SetDefault(BLOCKCHAIN_GAS_LIMIT, "200000")
+
+	viper.SetDefault(BLOCKCHAIN_GAS_PAUSE_INTERVAL, "60")
+
 	// no default for RELAYER_BLOCKCHAIN_HTTP_ENDPOINT
 
 	viper.SetDefault(BLOCKCHAIN_HTTP_MAX_RETRIES, "4")
@@ -70,6 +185,10 @@ func SetDefaults() {
 
 	// no default for RELAYER_BLOCKCHAIN_ID
 
+	viper.SetDefault(BLOCKCHAIN_MAX_BASE_FEE, "100000000000")
+
+	viper.SetDefault(BLOCKCHAIN_MAX_PRIORITY_FEE, "5000000000")
+
 	// no default for RELAYER_REWARD_TOKEN_ADDRESS
 
 	// no default for RELAYER_DATABASE_COLLECTION
@@ -78,10 +197,112 @@ func SetDefaults() {
 
 	// no default for RELAYER_DATABASE_URL
 
+	// no default for RELAYER_ADMIN_TOKEN
+
+	viper.SetDefault(DEVICE_SIGNATURES, "quarantine")
+
+	// no default for RELAYER_ENROLLMENT_TOKEN
+
+	viper.SetDefault(PAYOUTS, "registry")
+
+	viper.SetDefault(REPLAY_WINDOW, "300")
+
+	viper.SetDefault(HEALTH_CHECK_TIMEOUT, "5")
+
+	viper.SetDefault(HEALTH_MAX_BLOCK_AGE, "300")
+
+	viper.SetDefault(HEALTH_MIN_BALANCE, "1000000000000000")
+
+	viper.SetDefault(HISTORY_RETENTION, "7776000")
+
+	viper.SetDefault(KAFKA_AUTO_OFFSET_RESET, "latest")
+
 	viper.SetDefault(KAFKA_BROKER, "localhost:9092")
 
+	viper.SetDefault(KAFKA_DEAD_LETTER_TOPIC, "rewards-dlq")
+
+	viper.SetDefault(KAFKA_DRAIN_TIMEOUT, "30")
+
+	viper.SetDefault(KAFKA_EVENTS_MAX_FAILURES, "10")
+
+	viper.SetDefault(KAFKA_EVENTS_TOPIC, "reward-events")
+
+	viper.SetDefault(KAFKA_GROUP_ID, "city-fun-relayer")
+
+	viper.SetDefault(KAFKA_HEARTBEAT_INTERVAL, "3")
+
+	viper.SetDefault(KAFKA_MAX_POLL_INTERVAL, "300")
+
+	// no default for RELAYER_KAFKA_PROPERTIES
+
+	viper.SetDefault(KAFKA_RETRY_MAX_ATTEMPTS, "5")
+
+	viper.SetDefault(KAFKA_RETRY_MAX_WAIT, "30")
+
+	viper.SetDefault(KAFKA_RETRY_MIN_WAIT, "1")
+
+	viper.SetDefault(KAFKA_SASL_MECHANISM, "SCRAM-SHA-512")
+
+	// no default for RELAYER_KAFKA_SASL_PASSWORD
+
+	// no default for RELAYER_KAFKA_SASL_USERNAME
+
+	viper.SetDefault(KAFKA_SECURITY_PROTOCOL, "plaintext")
+
+	// no default for RELAYER_KAFKA_SEEK
+
+	viper.SetDefault(KAFKA_SESSION_TIMEOUT, "45")
+
+	viper.SetDefault(KAFKA_STATS_INTERVAL, "15")
+
+	// no default for RELAYER_KAFKA_TLS_CA_FILE
+
+	// no default for RELAYER_KAFKA_TLS_CERT_FILE
+
+	// no default for RELAYER_KAFKA_TLS_KEY_FILE
+
+	// no default for RELAYER_KAFKA_TLS_KEY_PASSWORD
+
+	viper.SetDefault(KAFKA_TLS_SKIP_VERIFY, "false")
+
 	// no default for RELAYER_KAFKA_TOPICS
 
+	viper.SetDefault(OUTBOX_CLAIM_TIMEOUT, "120")
+
+	viper.SetDefault(OUTBOX_MAX_ATTEMPTS, "10")
+
+	viper.SetDefault(OUTBOX_POLL_INTERVAL, "5")
+
+	viper.SetDefault(OUTBOX_REPLACE_AFTER, "180")
+
+	viper.SetDefault(OUTBOX_RETENTION, "604800")
+
+	viper.SetDefault(OUTBOX_RETRY_MAX_WAIT, "300")
+
+	viper.SetDefault(OUTBOX_RETRY_MIN_WAIT, "5")
+
+	viper.SetDefault(QUALITY_MIN_INTERVAL, "5")
+
+	viper.SetDefault(QUALITY_NEIGHBOR_RADIUS, "1000")
+
+	viper.SetDefault(QUALITY_NEIGHBOR_TOLERANCE, "4")
+
+	viper.SetDefault(QUALITY_NEIGHBOR_WINDOW, "300")
+
+	// no default for RELAYER_QUALITY_RANGES
+
+	viper.SetDefault(QUALITY_SPIKE_FACTOR, "6")
+
+	viper.SetDefault(QUALITY_STUCK_READINGS, "6")
+
+	viper.SetDefault(QUALITY_WITHHOLD_SCORE, "0.5")
+
+	viper.SetDefault(RECEIVERS, "open")
+
+	viper.SetDefault(RECEIVER_DAILY_CAP, "0")
+
+	// no default for RELAYER_RECEIVER_DENYLIST
+
 	viper.SetDefault(LOG_COLOR, "true")
 
 	viper.SetDefault(LOG_LEVEL, "info")
@@ -90,11 +311,45 @@ func SetDefaults() {
 
 	// no default for RELAYER_TELEMETRY_ADDRESS
 
+	viper.SetDefault(WORKER_COUNT, "5")
+
+	viper.SetDefault(SINK_URL, "kafka://")
+
+	viper.SetDefault(SOURCE_URL, "kafka://")
+
+	// no default for RELAYER_TRACING_ENDPOINT
+
+	viper.SetDefault(TRACING_SAMPLE_RATIO, "1")
+
 }
 
 // RelayerConfig holds configuration values for the relayer service.
 type RelayerConfig struct {
 
+	// MQTT topic the alert events are published to
+	AlertMqttTopic string `mapstructure:"RELAYER_ALERT_MQTT_TOPIC"`
+
+	// Size in degrees of the cells of the grid the alert rules are evaluated on. The index of a zone is the highest of the last indexes of its sensors in the past hour
+	AlertZoneSize float64 `mapstructure:"RELAYER_ALERT_ZONE_SIZE"`
+
+	// Scale of the air quality index computed for every reading and served by the reading API: us-epa, the US EPA AQI from 0 to 500, or eu, the European index from 1 to 6 that Argentine cities also report (ar is accepted as an alias)
+	AqiStandard string `mapstructure:"RELAYER_AQI_STANDARD"`
+
+	// Maximum amount of wei committed to gas per UTC day, shared by every relayer through MongoDB. Each transaction reserves gas limit times fee cap, and is settled at gas used times effective gas price once it has a receipt. Minting is paused once it is reached. Set to 0 to disable the cap.
+	BlockchainDailyBudget Wei `mapstructure:"RELAYER_BLOCKCHAIN_DAILY_BUDGET"`
+
+	// Number of recent blocks sampled through eth_feeHistory to estimate the priority fee.
+	BlockchainFeeHistoryBlocks uint64 `mapstructure:"RELAYER_BLOCKCHAIN_FEE_HISTORY_BLOCKS"`
+
+	// Priority fee percentile (0 to 100) sampled on each block of the fee history. The median across blocks is used as the tip. Other values are rejected when the configuration is loaded.
+	BlockchainFeeHistoryPercentile Percentile `mapstructure:"RELAYER_BLOCKCHAIN_FEE_HISTORY_PERCENTILE"`
+
+	// Gas limit set on every mint transaction. It also bounds the worst case cost charged against the daily budget.
+	BlockchainGasLimit uint64 `mapstructure:"RELAYER_BLOCKCHAIN_GAS_LIMIT"`
+
+	// Time in seconds to wait before retrying a mint that was paused by one of the gas limits.
+	BlockchainGasPauseInterval Duration `mapstructure:"RELAYER_BLOCKCHAIN_GAS_PAUSE_INTERVAL"`
+
 	// HTTP endpoint for the blockchain RPC provider.
 	BlockchainHttpEndpoint URL `mapstructure:"RELAYER_BLOCKCHAIN_HTTP_ENDPOINT"`
 
@@ -110,6 +365,12 @@ type RelayerConfig struct {
 	// An unique identifier representing a blockchain network.
 	BlockchainId uint64 `mapstructure:"RELAYER_BLOCKCHAIN_ID"`
 
+	// Maximum base fee in wei. Minting is paused while the next block base fee is above this value. Set to 0 to disable the cap.
+	BlockchainMaxBaseFee Wei `mapstructure:"RELAYER_BLOCKCHAIN_MAX_BASE_FEE"`
+
+	// Maximum priority fee (tip) in wei. The tip estimated from the fee history is clamped to this value. Set to 0 to disable the cap.
+	BlockchainMaxPriorityFee Wei `mapstructure:"RELAYER_BLOCKCHAIN_MAX_PRIORITY_FEE"`
+
 	// Address of the RewardToken contract.
 	RewardToken Address `mapstructure:"RELAYER_REWARD_TOKEN_ADDRESS"`
 
@@ -122,12 +383,129 @@ type RelayerConfig struct {
 	// MongoDB URL for the database (supports file-based secrets via RELAYER_DATABASE_URL_FILE)
 	DatabaseUrl URL `mapstructure:"RELAYER_DATABASE_URL"`
 
+	// What to do with readings not signed by the registered key of their sensor: quarantine stores them for inspection without rewarding them, reject sends them to the dead-letter topic and off rewards them unchecked
+	DeviceSignatures string `mapstructure:"RELAYER_DEVICE_SIGNATURES"`
+
+	// Where the receiver and amount of a reward come from: registry pays the receiver and rate registered for the sensor through the admin API, check does the same but only if the reading claims them too, and payload pays what the reading claims. Outside of payload, readings of unregistered sensors or, with check, claiming another payout are quarantined
+	Payouts string `mapstructure:"RELAYER_PAYOUTS"`
+
+	// How far in seconds the emission time of a reading may be from when it is received. Readings outside of it, without a sequence number or with one not above the last of their sensor are sent to the dead-letter topic as replays. 0 disables the check
+	ReplayWindow Duration `mapstructure:"RELAYER_REPLAY_WINDOW"`
+
+	// Time in seconds each readiness check may take before it fails
+	HealthCheckTimeout Duration `mapstructure:"RELAYER_HEALTH_CHECK_TIMEOUT"`
+
+	// Maximum age in seconds of the latest block of the RPC node. An older block means the node is out of sync and the relayer reports not ready
+	HealthMaxBlockAge Duration `mapstructure:"RELAYER_HEALTH_MAX_BLOCK_AGE"`
+
+	// Minimum balance in wei of the signer wallet. The relayer reports not ready below it, since it cannot pay for mints. Set to 0 to disable the check
+	HealthMinBalance Wei `mapstructure:"RELAYER_HEALTH_MIN_BALANCE"`
+
+	// Time in seconds every reading and reward is kept in the history collection. Older records are deleted by MongoDB, and only their hourly rollups are kept. Set to 0 to keep them forever
+	HistoryRetention Duration `mapstructure:"RELAYER_HISTORY_RETENTION"`
+
+	// Where the consumer group starts reading a partition without a committed offset: earliest, latest or error
+	KafkaAutoOffsetReset string `mapstructure:"RELAYER_KAFKA_AUTO_OFFSET_RESET"`
+
 	// Kafka brokers for the service
 	KafkaBroker URL `mapstructure:"RELAYER_KAFKA_BROKER"`
 
-	// Kafka topics for the service
+	// Topic of the sink (RELAYER_SINK_URL) that receives messages which could not be processed, together with the error and the attempt count
+	KafkaDeadLetterTopic string `mapstructure:"RELAYER_KAFKA_DEAD_LETTER_TOPIC"`
+
+	// Maximum wait time in seconds for the messages of revoked partitions to finish processing before the partitions are handed over on a rebalance
+	KafkaDrainTimeout Duration `mapstructure:"RELAYER_KAFKA_DRAIN_TIMEOUT"`
+
+	// Number of times the sink may refuse a reward lifecycle event before it is sent to the dead-letter topic instead, so that it stops holding back the events after it. Set to 0 to keep retrying it
+	KafkaEventsMaxFailures uint64 `mapstructure:"RELAYER_KAFKA_EVENTS_MAX_FAILURES"`
+
+	// Topic of the sink (RELAYER_SINK_URL) that receives the reward lifecycle events (reward.created, reward.minted, reward.confirmed and reward.failed), keyed by reward id
+	KafkaEventsTopic string `mapstructure:"RELAYER_KAFKA_EVENTS_TOPIC"`
+
+	// Kafka consumer group of the relayer
+	KafkaGroupId string `mapstructure:"RELAYER_KAFKA_GROUP_ID"`
+
+	// Interval in seconds between heartbeats of the consumer to the group coordinator
+	KafkaHeartbeatInterval Duration `mapstructure:"RELAYER_KAFKA_HEARTBEAT_INTERVAL"`
+
+	// Maximum time in seconds between polls before the consumer leaves the group
+	KafkaMaxPollInterval Duration `mapstructure:"RELAYER_KAFKA_MAX_POLL_INTERVAL"`
+
+	// Maximum number of processing attempts for a Kafka message before it is sent to the dead-letter topic
+	KafkaRetryMaxAttempts uint64 `mapstructure:"RELAYER_KAFKA_RETRY_MAX_ATTEMPTS"`
+
+	// Maximum wait time in seconds between processing attempts of a Kafka message
+	KafkaRetryMaxWait Duration `mapstructure:"RELAYER_KAFKA_RETRY_MAX_WAIT"`
+
+	// Minimum wait time in seconds between processing attempts of a Kafka message. The wait doubles on every attempt
+	KafkaRetryMinWait Duration `mapstructure:"RELAYER_KAFKA_RETRY_MIN_WAIT"`
+
+	// SASL mechanism used with the sasl_plaintext and sasl_ssl protocols: SCRAM-SHA-256, SCRAM-SHA-512 or PLAIN
+	KafkaSaslMechanism string `mapstructure:"RELAYER_KAFKA_SASL_MECHANISM"`
+
+	// Protocol used to reach the brokers: plaintext, ssl, sasl_plaintext or sasl_ssl
+	KafkaSecurityProtocol string `mapstructure:"RELAYER_KAFKA_SECURITY_PROTOCOL"`
+
+	// Time in seconds after which the consumer is removed from the group if the broker receives no heartbeat
+	KafkaSessionTimeout Duration `mapstructure:"RELAYER_KAFKA_SESSION_TIMEOUT"`
+
+	// Interval in seconds between Kafka client statistics reports, served on the telemetry address at /kafka/stats. Zero disables them
+	KafkaStatsInterval Duration `mapstructure:"RELAYER_KAFKA_STATS_INTERVAL"`
+
+	// Skip the verification of the broker certificates. Only meant for development
+	KafkaTlsSkipVerify bool `mapstructure:"RELAYER_KAFKA_TLS_SKIP_VERIFY"`
+
+	// Kafka topics for the service, or the MQTT topic filters or NATS subjects of the message source
 	KafkaTopics []string `mapstructure:"RELAYER_KAFKA_TOPICS"`
 
+	// Time in seconds an outbox entry stays claimed by a dispatcher before another one may take it over
+	OutboxClaimTimeout Duration `mapstructure:"RELAYER_OUTBOX_CLAIM_TIMEOUT"`
+
+	// Maximum number of mint attempts for an outbox entry before it is marked as failed
+	OutboxMaxAttempts uint64 `mapstructure:"RELAYER_OUTBOX_MAX_ATTEMPTS"`
+
+	// Interval in seconds between outbox scans for pending mints and unconfirmed transactions
+	OutboxPollInterval Duration `mapstructure:"RELAYER_OUTBOX_POLL_INTERVAL"`
+
+	// Time in seconds a mint transaction may stay unconfirmed before it is signed again with the same nonce and a higher fee, within the gas caps. Set to 0 to only rebroadcast it
+	OutboxReplaceAfter Duration `mapstructure:"RELAYER_OUTBOX_REPLACE_AFTER"`
+
+	// Time in seconds confirmed and failed outbox entries keep their signed transaction and trace context. Older entries are compacted on every tick, but never deleted, since they prevent a reading from being paid twice. Set to 0 to disable compaction
+	OutboxRetention Duration `mapstructure:"RELAYER_OUTBOX_RETENTION"`
+
+	// Maximum time in seconds an outbox entry waits before it is minted again after a failed attempt
+	OutboxRetryMaxWait Duration `mapstructure:"RELAYER_OUTBOX_RETRY_MAX_WAIT"`
+
+	// Time in seconds an outbox entry waits before it is minted again after its first failed attempt. The wait doubles on every following failure
+	OutboxRetryMinWait Duration `mapstructure:"RELAYER_OUTBOX_RETRY_MIN_WAIT"`
+
+	// Shortest time in seconds between two readings of a sensor. Readings that come sooner are rejected
+	QualityMinInterval Duration `mapstructure:"RELAYER_QUALITY_MIN_INTERVAL"`
+
+	// Distance in meters within which the readings of other sensors are compared. Set to 0 to disable the check
+	QualityNeighborRadius float64 `mapstructure:"RELAYER_QUALITY_NEIGHBOR_RADIUS"`
+
+	// Ratio to the median of the nearby sensors beyond which a value is flagged, either above or below it
+	QualityNeighborTolerance float64 `mapstructure:"RELAYER_QUALITY_NEIGHBOR_TOLERANCE"`
+
+	// How old in seconds the last reading of a nearby sensor may be to be compared
+	QualityNeighborWindow Duration `mapstructure:"RELAYER_QUALITY_NEIGHBOR_WINDOW"`
+
+	// Standard deviations from the moving mean of a parameter after which a value is flagged as a spike. Set to 0 to disable the check
+	QualitySpikeFactor float64 `mapstructure:"RELAYER_QUALITY_SPIKE_FACTOR"`
+
+	// Identical readings in a row after which a sensor is flagged as stuck. Set to 0 to disable the check
+	QualityStuckReadings uint64 `mapstructure:"RELAYER_QUALITY_STUCK_READINGS"`
+
+	// Reputation score, from 0 to 1, under which the rewards of a sensor are withheld. Above it, rewards are scaled by the score
+	QualityWithholdScore float64 `mapstructure:"RELAYER_QUALITY_WITHHOLD_SCORE"`
+
+	// Which receivers rewards are paid to: open pays any receiver not denied, and allowlist only the receivers registered as allowed through the admin API. Rewards to other receivers are quarantined instead of minted
+	Receivers string `mapstructure:"RELAYER_RECEIVERS"`
+
+	// Most a receiver is rewarded per UTC day, in the smallest unit of the token, unless it is registered with its own cap. Rewards over it are quarantined instead of minted. Set to 0 to disable the cap
+	ReceiverDailyCap Wei `mapstructure:"RELAYER_RECEIVER_DAILY_CAP"`
+
 	// Log color for the service
 	LogColor bool `mapstructure:"RELAYER_LOG_COLOR"`
 
@@ -139,6 +517,18 @@ type RelayerConfig struct {
 
 	// Telemetry address for the service
 	TelemetryAddress string `mapstructure:"RELAYER_TELEMETRY_ADDRESS"`
+
+	// Number of workers saving rewards. Messages of a partition are always handled by one worker at a time
+	WorkerCount uint64 `mapstructure:"RELAYER_WORKER_COUNT"`
+
+	// Connection string of the sink the dead letters, reward events and alerts of RELAYER_ALERT_KAFKA_TOPIC are published to: kafka://[brokers], mqtt://[user:password@]host:port[?qos=1&client_id=id] (mqtts:// for TLS), nats://[user:password@]host:port or memory://. A kafka:// sink without brokers uses RELAYER_KAFKA_BROKER. MQTT sinks drop the keys and headers, NATS subjects must belong to a stream and a memory:// sink loses what it holds on restart, so it is only meant for development
+	SinkUrl RedactedString `mapstructure:"RELAYER_SINK_URL"`
+
+	// Connection string of the message source: kafka://[brokers], mqtt://[user:password@]host:port[?qos=1&client_id=id] (mqtts:// for TLS), nats://[user:password@]host:port?stream=name[&consumer=name&ack_wait=seconds] or memory://. A kafka:// source without brokers uses RELAYER_KAFKA_BROKER and every source consumes RELAYER_KAFKA_TOPICS
+	SourceUrl RedactedString `mapstructure:"RELAYER_SOURCE_URL"`
+
+	// Fraction of new traces that are recorded, from 0 to 1. Traces continued from a message follow the decision of its producer
+	TracingSampleRatio float64 `mapstructure:"RELAYER_TRACING_SAMPLE_RATIO"`
 }
 
 // LoadRelayerConfig reads configuration from environment variables, a config file, and defaults.
@@ -157,6 +547,62 @@ func LoadRelayerConfig() (*RelayerConfig, error) {
 	var cfg RelayerConfig
 	var err error
 
+	cfg.AlertMqttTopic, err = GetAlertMqttTopic()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_ALERT_MQTT_TOPIC: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_ALERT_MQTT_TOPIC is required for the relayer service: %w", err)
+	}
+
+	cfg.AlertZoneSize, err = GetAlertZoneSize()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_ALERT_ZONE_SIZE: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_ALERT_ZONE_SIZE is required for the relayer service: %w", err)
+	}
+
+	cfg.AqiStandard, err = GetAqiStandard()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_AQI_STANDARD: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_AQI_STANDARD is required for the relayer service: %w", err)
+	}
+
+	cfg.BlockchainDailyBudget, err = GetBlockchainDailyBudget()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_DAILY_BUDGET: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_DAILY_BUDGET is required for the relayer service: %w", err)
+	}
+
+	cfg.BlockchainFeeHistoryBlocks, err = GetBlockchainFeeHistoryBlocks()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_FEE_HISTORY_BLOCKS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_FEE_HISTORY_BLOCKS is required for the relayer service: %w", err)
+	}
+
+	cfg.BlockchainFeeHistoryPercentile, err = GetBlockchainFeeHistoryPercentile()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_FEE_HISTORY_PERCENTILE: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_FEE_HISTORY_PERCENTILE is required for the relayer service: %w", err)
+	}
+
+	cfg.BlockchainGasLimit, err = GetBlockchainGasLimit()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_GAS_LIMIT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_GAS_LIMIT is required for the relayer service: %w", err)
+	}
+
+	cfg.BlockchainGasPauseInterval, err = GetBlockchainGasPauseInterval()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_GAS_PAUSE_INTERVAL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_GAS_PAUSE_INTERVAL is required for the relayer service: %w", err)
+	}
+
 	cfg.BlockchainHttpEndpoint, err = GetBlockchainHttpEndpoint()
 	if err != nil && err != ErrNotDefined {
 		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_HTTP_ENDPOINT: %w", err)
@@ -192,6 +638,20 @@ func LoadRelayerConfig() (*RelayerConfig, error) {
 		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_ID is required for the relayer service: %w", err)
 	}
 
+	cfg.BlockchainMaxBaseFee, err = GetBlockchainMaxBaseFee()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_MAX_BASE_FEE: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_MAX_BASE_FEE is required for the relayer service: %w", err)
+	}
+
+	cfg.BlockchainMaxPriorityFee, err = GetBlockchainMaxPriorityFee()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_BLOCKCHAIN_MAX_PRIORITY_FEE: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_BLOCKCHAIN_MAX_PRIORITY_FEE is required for the relayer service: %w", err)
+	}
+
 	cfg.RewardToken, err = GetRewardTokenAddress()
 	if err != nil && err != ErrNotDefined {
 		return nil, fmt.Errorf("failed to get RELAYER_REWARD_TOKEN_ADDRESS: %w", err)
@@ -220,276 +680,1508 @@ func LoadRelayerConfig() (*RelayerConfig, error) {
 		return nil, fmt.Errorf("RELAYER_DATABASE_URL is required for the relayer service: %w", err)
 	}
 
-	cfg.KafkaBroker, err = GetKafkaBroker()
+	cfg.DeviceSignatures, err = GetDeviceSignatures()
 	if err != nil && err != ErrNotDefined {
-		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_BROKER: %w", err)
+		return nil, fmt.Errorf("failed to get RELAYER_DEVICE_SIGNATURES: %w", err)
 	} else if err == ErrNotDefined {
-		return nil, fmt.Errorf("RELAYER_KAFKA_BROKER is required for the relayer service: %w", err)
+		return nil, fmt.Errorf("RELAYER_DEVICE_SIGNATURES is required for the relayer service: %w", err)
 	}
 
-	cfg.KafkaTopics, err = GetKafkaTopics()
+	cfg.Payouts, err = GetPayouts()
 	if err != nil && err != ErrNotDefined {
-		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_TOPICS: %w", err)
+		return nil, fmt.Errorf("failed to get RELAYER_PAYOUTS: %w", err)
 	} else if err == ErrNotDefined {
-		return nil, fmt.Errorf("RELAYER_KAFKA_TOPICS is required for the relayer service: %w", err)
+		return nil, fmt.Errorf("RELAYER_PAYOUTS is required for the relayer service: %w", err)
 	}
 
-	cfg.LogColor, err = GetLogColor()
+	cfg.ReplayWindow, err = GetReplayWindow()
 	if err != nil && err != ErrNotDefined {
-		return nil, fmt.Errorf("failed to get RELAYER_LOG_COLOR: %w", err)
+		return nil, fmt.Errorf("failed to get RELAYER_REPLAY_WINDOW: %w", err)
 	} else if err == ErrNotDefined {
-		return nil, fmt.Errorf("RELAYER_LOG_COLOR is required for the relayer service: %w", err)
+		return nil, fmt.Errorf("RELAYER_REPLAY_WINDOW is required for the relayer service: %w", err)
 	}
 
-	cfg.LogLevel, err = GetLogLevel()
+	cfg.HealthCheckTimeout, err = GetHealthCheckTimeout()
 	if err != nil && err != ErrNotDefined {
-		return nil, fmt.Errorf("failed to get RELAYER_LOG_LEVEL: %w", err)
+		return nil, fmt.Errorf("failed to get RELAYER_HEALTH_CHECK_TIMEOUT: %w", err)
 	} else if err == ErrNotDefined {
-		return nil, fmt.Errorf("RELAYER_LOG_LEVEL is required for the relayer service: %w", err)
+		return nil, fmt.Errorf("RELAYER_HEALTH_CHECK_TIMEOUT is required for the relayer service: %w", err)
 	}
 
-	cfg.MaxStartupTime, err = GetMaxStartupTime()
+	cfg.HealthMaxBlockAge, err = GetHealthMaxBlockAge()
 	if err != nil && err != ErrNotDefined {
-		return nil, fmt.Errorf("failed to get RELAYER_MAX_STARTUP_TIME: %w", err)
+		return nil, fmt.Errorf("failed to get RELAYER_HEALTH_MAX_BLOCK_AGE: %w", err)
 	} else if err == ErrNotDefined {
-		return nil, fmt.Errorf("RELAYER_MAX_STARTUP_TIME is required for the relayer service: %w", err)
+		return nil, fmt.Errorf("RELAYER_HEALTH_MAX_BLOCK_AGE is required for the relayer service: %w", err)
 	}
 
-	cfg.TelemetryAddress, err = GetTelemetryAddress()
+	cfg.HealthMinBalance, err = GetHealthMinBalance()
 	if err != nil && err != ErrNotDefined {
-		return nil, fmt.Errorf("failed to get RELAYER_TELEMETRY_ADDRESS: %w", err)
+		return nil, fmt.Errorf("failed to get RELAYER_HEALTH_MIN_BALANCE: %w", err)
 	} else if err == ErrNotDefined {
-		return nil, fmt.Errorf("RELAYER_TELEMETRY_ADDRESS is required for the relayer service: %w", err)
+		return nil, fmt.Errorf("RELAYER_HEALTH_MIN_BALANCE is required for the relayer service: %w", err)
 	}
 
-	return &cfg, nil
-}
-
-// GetAuthKind returns the value for the environment variable RELAYER_AUTH_KIND.
-func GetAuthKind() (AuthKind, error) {
-	s := viper.GetString(AUTH_KIND)
-	if s != "" {
-		v, err := toAuthKind(s)
-		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", AUTH_KIND, err)
-		}
-		return v, nil
+	cfg.HistoryRetention, err = GetHistoryRetention()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_HISTORY_RETENTION: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_HISTORY_RETENTION is required for the relayer service: %w", err)
 	}
-	return notDefinedAuthKind(), fmt.Errorf("%s: %w", AUTH_KIND, ErrNotDefined)
-}
 
-// GetAuthMnemonic returns the value for the environment variable RELAYER_AUTH_MNEMONIC.
-func GetAuthMnemonic() (RedactedString, error) {
-	s := viper.GetString(AUTH_MNEMONIC)
-	if s == "" {
-		filename := viper.GetString(AUTH_MNEMONIC_FILE)
-		contents, err := os.ReadFile(filename)
-		if err != nil {
-			return notDefinedRedactedString(), fmt.Errorf("failed to parse %s: %w", AUTH_MNEMONIC_FILE, err)
-		}
-		s = strings.TrimSpace(string(contents))
+	cfg.KafkaAutoOffsetReset, err = GetKafkaAutoOffsetReset()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_AUTO_OFFSET_RESET: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_AUTO_OFFSET_RESET is required for the relayer service: %w", err)
 	}
-	if s != "" {
-		v, err := toRedactedString(s)
-		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", AUTH_MNEMONIC, err)
-		}
-		return v, nil
+
+	cfg.KafkaBroker, err = GetKafkaBroker()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_BROKER: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_BROKER is required for the relayer service: %w", err)
 	}
-	return notDefinedRedactedString(), fmt.Errorf("%s: %w", AUTH_MNEMONIC, ErrNotDefined)
-}
 
-// GetAuthMnemonicAccountIndex returns the value for the environment variable RELAYER_AUTH_MNEMONIC_ACCOUNT_INDEX.
-func GetAuthMnemonicAccountIndex() (RedactedUint, error) {
-	s := viper.GetString(AUTH_MNEMONIC_ACCOUNT_INDEX)
-	if s != "" {
-		v, err := toRedactedUint(s)
-		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", AUTH_MNEMONIC_ACCOUNT_INDEX, err)
-		}
-		return v, nil
+	cfg.KafkaDeadLetterTopic, err = GetKafkaDeadLetterTopic()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_DEAD_LETTER_TOPIC: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_DEAD_LETTER_TOPIC is required for the relayer service: %w", err)
 	}
-	return notDefinedRedactedUint(), fmt.Errorf("%s: %w", AUTH_MNEMONIC_ACCOUNT_INDEX, ErrNotDefined)
-}
 
-// GetAuthPrivateKey returns the value for the environment variable RELAYER_AUTH_PRIVATE_KEY.
-func GetAuthPrivateKey() (RedactedString, error) {
-	s := viper.GetString(AUTH_PRIVATE_KEY)
-	if s == "" {
-		filename := viper.GetString(AUTH_PRIVATE_KEY_FILE)
-		contents, err := os.ReadFile(filename)
-		if err != nil {
-			return notDefinedRedactedString(), fmt.Errorf("failed to parse %s: %w", AUTH_PRIVATE_KEY_FILE, err)
-		}
-		s = strings.TrimSpace(string(contents))
+	cfg.KafkaDrainTimeout, err = GetKafkaDrainTimeout()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_DRAIN_TIMEOUT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_DRAIN_TIMEOUT is required for the relayer service: %w", err)
 	}
+
+	cfg.KafkaEventsMaxFailures, err = GetKafkaEventsMaxFailures()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_EVENTS_MAX_FAILURES: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_EVENTS_MAX_FAILURES is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaEventsTopic, err = GetKafkaEventsTopic()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_EVENTS_TOPIC: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_EVENTS_TOPIC is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaGroupId, err = GetKafkaGroupId()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_GROUP_ID: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_GROUP_ID is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaHeartbeatInterval, err = GetKafkaHeartbeatInterval()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_HEARTBEAT_INTERVAL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_HEARTBEAT_INTERVAL is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaMaxPollInterval, err = GetKafkaMaxPollInterval()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_MAX_POLL_INTERVAL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_MAX_POLL_INTERVAL is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaRetryMaxAttempts, err = GetKafkaRetryMaxAttempts()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_RETRY_MAX_ATTEMPTS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_RETRY_MAX_ATTEMPTS is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaRetryMaxWait, err = GetKafkaRetryMaxWait()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_RETRY_MAX_WAIT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_RETRY_MAX_WAIT is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaRetryMinWait, err = GetKafkaRetryMinWait()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_RETRY_MIN_WAIT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_RETRY_MIN_WAIT is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaSaslMechanism, err = GetKafkaSaslMechanism()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_SASL_MECHANISM: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_SASL_MECHANISM is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaSecurityProtocol, err = GetKafkaSecurityProtocol()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_SECURITY_PROTOCOL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_SECURITY_PROTOCOL is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaSessionTimeout, err = GetKafkaSessionTimeout()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_SESSION_TIMEOUT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_SESSION_TIMEOUT is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaStatsInterval, err = GetKafkaStatsInterval()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_STATS_INTERVAL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_STATS_INTERVAL is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaTlsSkipVerify, err = GetKafkaTlsSkipVerify()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_TLS_SKIP_VERIFY: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_TLS_SKIP_VERIFY is required for the relayer service: %w", err)
+	}
+
+	cfg.KafkaTopics, err = GetKafkaTopics()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_KAFKA_TOPICS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_KAFKA_TOPICS is required for the relayer service: %w", err)
+	}
+
+	cfg.OutboxClaimTimeout, err = GetOutboxClaimTimeout()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_OUTBOX_CLAIM_TIMEOUT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_OUTBOX_CLAIM_TIMEOUT is required for the relayer service: %w", err)
+	}
+
+	cfg.OutboxMaxAttempts, err = GetOutboxMaxAttempts()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_OUTBOX_MAX_ATTEMPTS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_OUTBOX_MAX_ATTEMPTS is required for the relayer service: %w", err)
+	}
+
+	cfg.OutboxPollInterval, err = GetOutboxPollInterval()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_OUTBOX_POLL_INTERVAL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_OUTBOX_POLL_INTERVAL is required for the relayer service: %w", err)
+	}
+
+	cfg.OutboxReplaceAfter, err = GetOutboxReplaceAfter()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_OUTBOX_REPLACE_AFTER: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_OUTBOX_REPLACE_AFTER is required for the relayer service: %w", err)
+	}
+
+	cfg.OutboxRetention, err = GetOutboxRetention()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_OUTBOX_RETENTION: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_OUTBOX_RETENTION is required for the relayer service: %w", err)
+	}
+
+	cfg.OutboxRetryMaxWait, err = GetOutboxRetryMaxWait()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_OUTBOX_RETRY_MAX_WAIT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_OUTBOX_RETRY_MAX_WAIT is required for the relayer service: %w", err)
+	}
+
+	cfg.OutboxRetryMinWait, err = GetOutboxRetryMinWait()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_OUTBOX_RETRY_MIN_WAIT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_OUTBOX_RETRY_MIN_WAIT is required for the relayer service: %w", err)
+	}
+
+	cfg.QualityMinInterval, err = GetQualityMinInterval()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_QUALITY_MIN_INTERVAL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_QUALITY_MIN_INTERVAL is required for the relayer service: %w", err)
+	}
+
+	cfg.QualityNeighborRadius, err = GetQualityNeighborRadius()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_QUALITY_NEIGHBOR_RADIUS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_QUALITY_NEIGHBOR_RADIUS is required for the relayer service: %w", err)
+	}
+
+	cfg.QualityNeighborTolerance, err = GetQualityNeighborTolerance()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_QUALITY_NEIGHBOR_TOLERANCE: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_QUALITY_NEIGHBOR_TOLERANCE is required for the relayer service: %w", err)
+	}
+
+	cfg.QualityNeighborWindow, err = GetQualityNeighborWindow()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_QUALITY_NEIGHBOR_WINDOW: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_QUALITY_NEIGHBOR_WINDOW is required for the relayer service: %w", err)
+	}
+
+	cfg.QualitySpikeFactor, err = GetQualitySpikeFactor()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_QUALITY_SPIKE_FACTOR: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_QUALITY_SPIKE_FACTOR is required for the relayer service: %w", err)
+	}
+
+	cfg.QualityStuckReadings, err = GetQualityStuckReadings()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_QUALITY_STUCK_READINGS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_QUALITY_STUCK_READINGS is required for the relayer service: %w", err)
+	}
+
+	cfg.QualityWithholdScore, err = GetQualityWithholdScore()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_QUALITY_WITHHOLD_SCORE: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_QUALITY_WITHHOLD_SCORE is required for the relayer service: %w", err)
+	}
+
+	cfg.Receivers, err = GetReceivers()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_RECEIVERS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_RECEIVERS is required for the relayer service: %w", err)
+	}
+
+	cfg.ReceiverDailyCap, err = GetReceiverDailyCap()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_RECEIVER_DAILY_CAP: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_RECEIVER_DAILY_CAP is required for the relayer service: %w", err)
+	}
+
+	cfg.LogColor, err = GetLogColor()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_LOG_COLOR: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_LOG_COLOR is required for the relayer service: %w", err)
+	}
+
+	cfg.LogLevel, err = GetLogLevel()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_LOG_LEVEL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_LOG_LEVEL is required for the relayer service: %w", err)
+	}
+
+	cfg.MaxStartupTime, err = GetMaxStartupTime()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_MAX_STARTUP_TIME: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_MAX_STARTUP_TIME is required for the relayer service: %w", err)
+	}
+
+	cfg.TelemetryAddress, err = GetTelemetryAddress()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_TELEMETRY_ADDRESS: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_TELEMETRY_ADDRESS is required for the relayer service: %w", err)
+	}
+
+	cfg.WorkerCount, err = GetWorkerCount()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_WORKER_COUNT: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_WORKER_COUNT is required for the relayer service: %w", err)
+	}
+
+	cfg.SinkUrl, err = GetSinkUrl()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_SINK_URL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_SINK_URL is required for the relayer service: %w", err)
+	}
+
+	cfg.SourceUrl, err = GetSourceUrl()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_SOURCE_URL: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_SOURCE_URL is required for the relayer service: %w", err)
+	}
+
+	cfg.TracingSampleRatio, err = GetTracingSampleRatio()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get RELAYER_TRACING_SAMPLE_RATIO: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("RELAYER_TRACING_SAMPLE_RATIO is required for the relayer service: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// GetAlertKafkaTopic returns the value for the environment variable RELAYER_ALERT_KAFKA_TOPIC.
+func GetAlertKafkaTopic() (string, error) {
+	s := viper.GetString(ALERT_KAFKA_TOPIC)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ALERT_KAFKA_TOPIC, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", ALERT_KAFKA_TOPIC, ErrNotDefined)
+}
+
+// GetAlertMqttTopic returns the value for the environment variable RELAYER_ALERT_MQTT_TOPIC.
+func GetAlertMqttTopic() (string, error) {
+	s := viper.GetString(ALERT_MQTT_TOPIC)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ALERT_MQTT_TOPIC, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", ALERT_MQTT_TOPIC, ErrNotDefined)
+}
+
+// GetAlertMqttUrl returns the value for the environment variable RELAYER_ALERT_MQTT_URL.
+func GetAlertMqttUrl() (RedactedString, error) {
+	s := viper.GetString(ALERT_MQTT_URL)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ALERT_MQTT_URL, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", ALERT_MQTT_URL, ErrNotDefined)
+}
+
+// GetAlertRules returns the value for the environment variable RELAYER_ALERT_RULES.
+func GetAlertRules() (string, error) {
+	s := viper.GetString(ALERT_RULES)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ALERT_RULES, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", ALERT_RULES, ErrNotDefined)
+}
+
+// GetAlertWebhookUrls returns the value for the environment variable RELAYER_ALERT_WEBHOOK_URLS.
+func GetAlertWebhookUrls() ([]string, error) {
+	s := viper.GetString(ALERT_WEBHOOK_URLS)
+	if s != "" {
+		v, err := toSliceString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ALERT_WEBHOOK_URLS, err)
+		}
+		return v, nil
+	}
+	return notDefinedSliceString(), fmt.Errorf("%s: %w", ALERT_WEBHOOK_URLS, ErrNotDefined)
+}
+
+// GetAlertZoneSize returns the value for the environment variable RELAYER_ALERT_ZONE_SIZE.
+func GetAlertZoneSize() (float64, error) {
+	s := viper.GetString(ALERT_ZONE_SIZE)
+	if s != "" {
+		v, err := toFloat64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ALERT_ZONE_SIZE, err)
+		}
+		return v, nil
+	}
+	return notDefinedFloat64(), fmt.Errorf("%s: %w", ALERT_ZONE_SIZE, ErrNotDefined)
+}
+
+// GetAqiStandard returns the value for the environment variable RELAYER_AQI_STANDARD.
+func GetAqiStandard() (string, error) {
+	s := viper.GetString(AQI_STANDARD)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AQI_STANDARD, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", AQI_STANDARD, ErrNotDefined)
+}
+
+// GetAuthAwsEndpointUrl returns the value for the environment variable RELAYER_AUTH_AWS_ENDPOINT_URL.
+func GetAuthAwsEndpointUrl() (string, error) {
+	s := viper.GetString(AUTH_AWS_ENDPOINT_URL)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_AWS_ENDPOINT_URL, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", AUTH_AWS_ENDPOINT_URL, ErrNotDefined)
+}
+
+// GetAuthAwsKmsKeyId returns the value for the environment variable RELAYER_AUTH_AWS_KMS_KEY_ID.
+func GetAuthAwsKmsKeyId() (string, error) {
+	s := viper.GetString(AUTH_AWS_KMS_KEY_ID)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_AWS_KMS_KEY_ID, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", AUTH_AWS_KMS_KEY_ID, ErrNotDefined)
+}
+
+// GetAuthAwsRegion returns the value for the environment variable RELAYER_AUTH_AWS_REGION.
+func GetAuthAwsRegion() (string, error) {
+	s := viper.GetString(AUTH_AWS_REGION)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_AWS_REGION, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", AUTH_AWS_REGION, ErrNotDefined)
+}
+
+// GetAuthKind returns the value for the environment variable RELAYER_AUTH_KIND.
+func GetAuthKind() (AuthKind, error) {
+	s := viper.GetString(AUTH_KIND)
+	if s != "" {
+		v, err := toAuthKind(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_KIND, err)
+		}
+		return v, nil
+	}
+	return notDefinedAuthKind(), fmt.Errorf("%s: %w", AUTH_KIND, ErrNotDefined)
+}
+
+// GetAuthMnemonic returns the value for the environment variable RELAYER_AUTH_MNEMONIC.
+func GetAuthMnemonic() (RedactedString, error) {
+	s := viper.GetString(AUTH_MNEMONIC)
+	if s == "" {
+		filename := viper.GetString(AUTH_MNEMONIC_FILE)
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return notDefinedRedactedString(), fmt.Errorf("failed to parse %s: %w", AUTH_MNEMONIC_FILE, err)
+		}
+		s = strings.TrimSpace(string(contents))
+	}
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_MNEMONIC, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", AUTH_MNEMONIC, ErrNotDefined)
+}
+
+// GetAuthMnemonicAccountIndex returns the value for the environment variable RELAYER_AUTH_MNEMONIC_ACCOUNT_INDEX.
+func GetAuthMnemonicAccountIndex() (RedactedUint, error) {
+	s := viper.GetString(AUTH_MNEMONIC_ACCOUNT_INDEX)
+	if s != "" {
+		v, err := toRedactedUint(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_MNEMONIC_ACCOUNT_INDEX, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedUint(), fmt.Errorf("%s: %w", AUTH_MNEMONIC_ACCOUNT_INDEX, ErrNotDefined)
+}
+
+// GetAuthPrivateKey returns the value for the environment variable RELAYER_AUTH_PRIVATE_KEY.
+func GetAuthPrivateKey() (RedactedString, error) {
+	s := viper.GetString(AUTH_PRIVATE_KEY)
+	if s == "" {
+		filename := viper.GetString(AUTH_PRIVATE_KEY_FILE)
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return notDefinedRedactedString(), fmt.Errorf("failed to parse %s: %w", AUTH_PRIVATE_KEY_FILE, err)
+		}
+		s = strings.TrimSpace(string(contents))
+	}
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_PRIVATE_KEY, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", AUTH_PRIVATE_KEY, ErrNotDefined)
+}
+
+// GetAuthSignerAddress returns the value for the environment variable RELAYER_AUTH_SIGNER_ADDRESS.
+func GetAuthSignerAddress() (string, error) {
+	s := viper.GetString(AUTH_SIGNER_ADDRESS)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_SIGNER_ADDRESS, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", AUTH_SIGNER_ADDRESS, ErrNotDefined)
+}
+
+// GetAuthSignerTimeout returns the value for the environment variable RELAYER_AUTH_SIGNER_TIMEOUT.
+func GetAuthSignerTimeout() (Duration, error) {
+	s := viper.GetString(AUTH_SIGNER_TIMEOUT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_SIGNER_TIMEOUT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", AUTH_SIGNER_TIMEOUT, ErrNotDefined)
+}
+
+// GetAuthSignerUrl returns the value for the environment variable RELAYER_AUTH_SIGNER_URL.
+func GetAuthSignerUrl() (string, error) {
+	s := viper.GetString(AUTH_SIGNER_URL)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", AUTH_SIGNER_URL, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", AUTH_SIGNER_URL, ErrNotDefined)
+}
+
+// GetBlockchainDailyBudget returns the value for the environment variable RELAYER_BLOCKCHAIN_DAILY_BUDGET.
+func GetBlockchainDailyBudget() (Wei, error) {
+	s := viper.GetString(BLOCKCHAIN_DAILY_BUDGET)
+	if s != "" {
+		v, err := toWei(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_DAILY_BUDGET, err)
+		}
+		return v, nil
+	}
+	return notDefinedWei(), fmt.Errorf("%s: %w", BLOCKCHAIN_DAILY_BUDGET, ErrNotDefined)
+}
+
+// GetBlockchainFeeHistoryBlocks returns the value for the environment variable RELAYER_BLOCKCHAIN_FEE_HISTORY_BLOCKS.
+func GetBlockchainFeeHistoryBlocks() (uint64, error) {
+	s := viper.GetString(BLOCKCHAIN_FEE_HISTORY_BLOCKS)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_FEE_HISTORY_BLOCKS, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", BLOCKCHAIN_FEE_HISTORY_BLOCKS, ErrNotDefined)
+}
+
+// GetBlockchainFeeHistoryPercentile returns the value for the environment variable RELAYER_BLOCKCHAIN_FEE_HISTORY_PERCENTILE.
+func GetBlockchainFeeHistoryPercentile() (Percentile, error) {
+	s := viper.GetString(BLOCKCHAIN_FEE_HISTORY_PERCENTILE)
+	if s != "" {
+		v, err := toPercentile(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_FEE_HISTORY_PERCENTILE, err)
+		}
+		return v, nil
+	}
+	return notDefinedPercentile(), fmt.Errorf("%s: %w", BLOCKCHAIN_FEE_HISTORY_PERCENTILE, ErrNotDefined)
+}
+
+// GetBlockchainGasLimit returns the value for the environment variable RELAYER_BLOCKCHAIN_GAS_LIMIT.
+func GetBlockchainGasLimit() (uint64, error) {
+	s := viper.GetString(BLOCKCHAIN_GAS_LIMIT)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_GAS_LIMIT, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", BLOCKCHAIN_GAS_LIMIT, ErrNotDefined)
+}
+
+// GetBlockchainGasPauseInterval returns the value for the environment variable RELAYER_BLOCKCHAIN_GAS_PAUSE_INTERVAL.
+func GetBlockchainGasPauseInterval() (Duration, error) {
+	s := viper.GetString(BLOCKCHAIN_GAS_PAUSE_INTERVAL)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_GAS_PAUSE_INTERVAL, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", BLOCKCHAIN_GAS_PAUSE_INTERVAL, ErrNotDefined)
+}
+
+// GetBlockchainHttpEndpoint returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_ENDPOINT.
+func GetBlockchainHttpEndpoint() (URL, error) {
+	s := viper.GetString(BLOCKCHAIN_HTTP_ENDPOINT)
+	if s == "" {
+		filename := viper.GetString(BLOCKCHAIN_HTTP_ENDPOINT_FILE)
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return notDefinedURL(), fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_ENDPOINT_FILE, err)
+		}
+		s = strings.TrimSpace(string(contents))
+	}
+	if s != "" {
+		v, err := toURL(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_ENDPOINT, err)
+		}
+		return v, nil
+	}
+	return notDefinedURL(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_ENDPOINT, ErrNotDefined)
+}
+
+// GetBlockchainHttpMaxRetries returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_MAX_RETRIES.
+func GetBlockchainHttpMaxRetries() (uint64, error) {
+	s := viper.GetString(BLOCKCHAIN_HTTP_MAX_RETRIES)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_MAX_RETRIES, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_MAX_RETRIES, ErrNotDefined)
+}
+
+// GetBlockchainHttpRetryMaxWait returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_RETRY_MAX_WAIT.
+func GetBlockchainHttpRetryMaxWait() (Duration, error) {
+	s := viper.GetString(BLOCKCHAIN_HTTP_RETRY_MAX_WAIT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_RETRY_MAX_WAIT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_RETRY_MAX_WAIT, ErrNotDefined)
+}
+
+// GetBlockchainHttpRetryMinWait returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_RETRY_MIN_WAIT.
+func GetBlockchainHttpRetryMinWait() (Duration, error) {
+	s := viper.GetString(BLOCKCHAIN_HTTP_RETRY_MIN_WAIT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_RETRY_MIN_WAIT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_RETRY_MIN_WAIT, ErrNotDefined)
+}
+
+// GetBlockchainId returns the value for the environment variable RELAYER_BLOCKCHAIN_ID.
+func GetBlockchainId() (uint64, error) {
+	s := viper.GetString(BLOCKCHAIN_ID)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_ID, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", BLOCKCHAIN_ID, ErrNotDefined)
+}
+
+// GetBlockchainMaxBaseFee returns the value for the environment variable RELAYER_BLOCKCHAIN_MAX_BASE_FEE.
+func GetBlockchainMaxBaseFee() (Wei, error) {
+	s := viper.GetString(BLOCKCHAIN_MAX_BASE_FEE)
+	if s != "" {
+		v, err := toWei(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_MAX_BASE_FEE, err)
+		}
+		return v, nil
+	}
+	return notDefinedWei(), fmt.Errorf("%s: %w", BLOCKCHAIN_MAX_BASE_FEE, ErrNotDefined)
+}
+
+// GetBlockchainMaxPriorityFee returns the value for the environment variable RELAYER_BLOCKCHAIN_MAX_PRIORITY_FEE.
+func GetBlockchainMaxPriorityFee() (Wei, error) {
+	s := viper.GetString(BLOCKCHAIN_MAX_PRIORITY_FEE)
+	if s != "" {
+		v, err := toWei(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_MAX_PRIORITY_FEE, err)
+		}
+		return v, nil
+	}
+	return notDefinedWei(), fmt.Errorf("%s: %w", BLOCKCHAIN_MAX_PRIORITY_FEE, ErrNotDefined)
+}
+
+// GetRewardTokenAddress returns the value for the environment variable RELAYER_REWARD_TOKEN_ADDRESS.
+func GetRewardTokenAddress() (Address, error) {
+	s := viper.GetString(REWARD_TOKEN_ADDRESS)
+	if s != "" {
+		v, err := toAddress(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", REWARD_TOKEN_ADDRESS, err)
+		}
+		return v, nil
+	}
+	return notDefinedAddress(), fmt.Errorf("%s: %w", REWARD_TOKEN_ADDRESS, ErrNotDefined)
+}
+
+// GetDatabaseCollection returns the value for the environment variable RELAYER_DATABASE_COLLECTION.
+func GetDatabaseCollection() (RedactedString, error) {
+	s := viper.GetString(DATABASE_COLLECTION)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", DATABASE_COLLECTION, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", DATABASE_COLLECTION, ErrNotDefined)
+}
+
+// GetDatabaseName returns the value for the environment variable RELAYER_DATABASE_NAME.
+func GetDatabaseName() (RedactedString, error) {
+	s := viper.GetString(DATABASE_NAME)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", DATABASE_NAME, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", DATABASE_NAME, ErrNotDefined)
+}
+
+// GetDatabaseUrl returns the value for the environment variable RELAYER_DATABASE_URL.
+func GetDatabaseUrl() (URL, error) {
+	s := viper.GetString(DATABASE_URL)
+	if s == "" {
+		filename := viper.GetString(DATABASE_URL_FILE)
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return notDefinedURL(), fmt.Errorf("failed to parse %s: %w", DATABASE_URL_FILE, err)
+		}
+		s = strings.TrimSpace(string(contents))
+	}
+	if s != "" {
+		v, err := toURL(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", DATABASE_URL, err)
+		}
+		return v, nil
+	}
+	return notDefinedURL(), fmt.Errorf("%s: %w", DATABASE_URL, ErrNotDefined)
+}
+
+// GetAdminToken returns the value for the environment variable RELAYER_ADMIN_TOKEN.
+func GetAdminToken() (RedactedString, error) {
+	s := viper.GetString(ADMIN_TOKEN)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ADMIN_TOKEN, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", ADMIN_TOKEN, ErrNotDefined)
+}
+
+// GetDeviceSignatures returns the value for the environment variable RELAYER_DEVICE_SIGNATURES.
+func GetDeviceSignatures() (string, error) {
+	s := viper.GetString(DEVICE_SIGNATURES)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", DEVICE_SIGNATURES, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", DEVICE_SIGNATURES, ErrNotDefined)
+}
+
+// GetEnrollmentToken returns the value for the environment variable RELAYER_ENROLLMENT_TOKEN.
+func GetEnrollmentToken() (RedactedString, error) {
+	s := viper.GetString(ENROLLMENT_TOKEN)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", ENROLLMENT_TOKEN, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", ENROLLMENT_TOKEN, ErrNotDefined)
+}
+
+// GetPayouts returns the value for the environment variable RELAYER_PAYOUTS.
+func GetPayouts() (string, error) {
+	s := viper.GetString(PAYOUTS)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", PAYOUTS, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", PAYOUTS, ErrNotDefined)
+}
+
+// GetReplayWindow returns the value for the environment variable RELAYER_REPLAY_WINDOW.
+func GetReplayWindow() (Duration, error) {
+	s := viper.GetString(REPLAY_WINDOW)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", REPLAY_WINDOW, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", REPLAY_WINDOW, ErrNotDefined)
+}
+
+// GetHealthCheckTimeout returns the value for the environment variable RELAYER_HEALTH_CHECK_TIMEOUT.
+func GetHealthCheckTimeout() (Duration, error) {
+	s := viper.GetString(HEALTH_CHECK_TIMEOUT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", HEALTH_CHECK_TIMEOUT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", HEALTH_CHECK_TIMEOUT, ErrNotDefined)
+}
+
+// GetHealthMaxBlockAge returns the value for the environment variable RELAYER_HEALTH_MAX_BLOCK_AGE.
+func GetHealthMaxBlockAge() (Duration, error) {
+	s := viper.GetString(HEALTH_MAX_BLOCK_AGE)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", HEALTH_MAX_BLOCK_AGE, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", HEALTH_MAX_BLOCK_AGE, ErrNotDefined)
+}
+
+// GetHealthMinBalance returns the value for the environment variable RELAYER_HEALTH_MIN_BALANCE.
+func GetHealthMinBalance() (Wei, error) {
+	s := viper.GetString(HEALTH_MIN_BALANCE)
+	if s != "" {
+		v, err := toWei(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", HEALTH_MIN_BALANCE, err)
+		}
+		return v, nil
+	}
+	return notDefinedWei(), fmt.Errorf("%s: %w", HEALTH_MIN_BALANCE, ErrNotDefined)
+}
+
+// GetHistoryRetention returns the value for the environment variable RELAYER_HISTORY_RETENTION.
+func GetHistoryRetention() (Duration, error) {
+	s := viper.GetString(HISTORY_RETENTION)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", HISTORY_RETENTION, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", HISTORY_RETENTION, ErrNotDefined)
+}
+
+// GetKafkaAutoOffsetReset returns the value for the environment variable RELAYER_KAFKA_AUTO_OFFSET_RESET.
+func GetKafkaAutoOffsetReset() (string, error) {
+	s := viper.GetString(KAFKA_AUTO_OFFSET_RESET)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_AUTO_OFFSET_RESET, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_AUTO_OFFSET_RESET, ErrNotDefined)
+}
+
+// GetKafkaBroker returns the value for the environment variable RELAYER_KAFKA_BROKER.
+func GetKafkaBroker() (URL, error) {
+	s := viper.GetString(KAFKA_BROKER)
+	if s != "" {
+		v, err := toURL(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_BROKER, err)
+		}
+		return v, nil
+	}
+	return notDefinedURL(), fmt.Errorf("%s: %w", KAFKA_BROKER, ErrNotDefined)
+}
+
+// GetKafkaDeadLetterTopic returns the value for the environment variable RELAYER_KAFKA_DEAD_LETTER_TOPIC.
+func GetKafkaDeadLetterTopic() (string, error) {
+	s := viper.GetString(KAFKA_DEAD_LETTER_TOPIC)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_DEAD_LETTER_TOPIC, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_DEAD_LETTER_TOPIC, ErrNotDefined)
+}
+
+// GetKafkaDrainTimeout returns the value for the environment variable RELAYER_KAFKA_DRAIN_TIMEOUT.
+func GetKafkaDrainTimeout() (Duration, error) {
+	s := viper.GetString(KAFKA_DRAIN_TIMEOUT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_DRAIN_TIMEOUT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", KAFKA_DRAIN_TIMEOUT, ErrNotDefined)
+}
+
+// GetKafkaEventsMaxFailures returns the value for the environment variable RELAYER_KAFKA_EVENTS_MAX_FAILURES.
+func GetKafkaEventsMaxFailures() (uint64, error) {
+	s := viper.GetString(KAFKA_EVENTS_MAX_FAILURES)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_EVENTS_MAX_FAILURES, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", KAFKA_EVENTS_MAX_FAILURES, ErrNotDefined)
+}
+
+// GetKafkaEventsTopic returns the value for the environment variable RELAYER_KAFKA_EVENTS_TOPIC.
+func GetKafkaEventsTopic() (string, error) {
+	s := viper.GetString(KAFKA_EVENTS_TOPIC)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_EVENTS_TOPIC, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_EVENTS_TOPIC, ErrNotDefined)
+}
+
+// GetKafkaGroupId returns the value for the environment variable RELAYER_KAFKA_GROUP_ID.
+func GetKafkaGroupId() (string, error) {
+	s := viper.GetString(KAFKA_GROUP_ID)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_GROUP_ID, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_GROUP_ID, ErrNotDefined)
+}
+
+// GetKafkaHeartbeatInterval returns the value for the environment variable RELAYER_KAFKA_HEARTBEAT_INTERVAL.
+func GetKafkaHeartbeatInterval() (Duration, error) {
+	s := viper.GetString(KAFKA_HEARTBEAT_INTERVAL)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_HEARTBEAT_INTERVAL, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", KAFKA_HEARTBEAT_INTERVAL, ErrNotDefined)
+}
+
+// GetKafkaMaxPollInterval returns the value for the environment variable RELAYER_KAFKA_MAX_POLL_INTERVAL.
+func GetKafkaMaxPollInterval() (Duration, error) {
+	s := viper.GetString(KAFKA_MAX_POLL_INTERVAL)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_MAX_POLL_INTERVAL, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", KAFKA_MAX_POLL_INTERVAL, ErrNotDefined)
+}
+
+// GetKafkaProperties returns the value for the environment variable RELAYER_KAFKA_PROPERTIES.
+func GetKafkaProperties() ([]string, error) {
+	s := viper.GetString(KAFKA_PROPERTIES)
+	if s != "" {
+		v, err := toSliceString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_PROPERTIES, err)
+		}
+		return v, nil
+	}
+	return notDefinedSliceString(), fmt.Errorf("%s: %w", KAFKA_PROPERTIES, ErrNotDefined)
+}
+
+// GetKafkaRetryMaxAttempts returns the value for the environment variable RELAYER_KAFKA_RETRY_MAX_ATTEMPTS.
+func GetKafkaRetryMaxAttempts() (uint64, error) {
+	s := viper.GetString(KAFKA_RETRY_MAX_ATTEMPTS)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_RETRY_MAX_ATTEMPTS, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", KAFKA_RETRY_MAX_ATTEMPTS, ErrNotDefined)
+}
+
+// GetKafkaRetryMaxWait returns the value for the environment variable RELAYER_KAFKA_RETRY_MAX_WAIT.
+func GetKafkaRetryMaxWait() (Duration, error) {
+	s := viper.GetString(KAFKA_RETRY_MAX_WAIT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_RETRY_MAX_WAIT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", KAFKA_RETRY_MAX_WAIT, ErrNotDefined)
+}
+
+// GetKafkaRetryMinWait returns the value for the environment variable RELAYER_KAFKA_RETRY_MIN_WAIT.
+func GetKafkaRetryMinWait() (Duration, error) {
+	s := viper.GetString(KAFKA_RETRY_MIN_WAIT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_RETRY_MIN_WAIT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", KAFKA_RETRY_MIN_WAIT, ErrNotDefined)
+}
+
+// GetKafkaSaslMechanism returns the value for the environment variable RELAYER_KAFKA_SASL_MECHANISM.
+func GetKafkaSaslMechanism() (string, error) {
+	s := viper.GetString(KAFKA_SASL_MECHANISM)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_SASL_MECHANISM, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_SASL_MECHANISM, ErrNotDefined)
+}
+
+// GetKafkaSaslPassword returns the value for the environment variable RELAYER_KAFKA_SASL_PASSWORD.
+func GetKafkaSaslPassword() (RedactedString, error) {
+	s := viper.GetString(KAFKA_SASL_PASSWORD)
+	if s == "" {
+		filename := viper.GetString(KAFKA_SASL_PASSWORD_FILE)
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return notDefinedRedactedString(), fmt.Errorf("failed to parse %s: %w", KAFKA_SASL_PASSWORD_FILE, err)
+		}
+		s = strings.TrimSpace(string(contents))
+	}
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_SASL_PASSWORD, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", KAFKA_SASL_PASSWORD, ErrNotDefined)
+}
+
+// GetKafkaSaslUsername returns the value for the environment variable RELAYER_KAFKA_SASL_USERNAME.
+func GetKafkaSaslUsername() (string, error) {
+	s := viper.GetString(KAFKA_SASL_USERNAME)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_SASL_USERNAME, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_SASL_USERNAME, ErrNotDefined)
+}
+
+// GetKafkaSecurityProtocol returns the value for the environment variable RELAYER_KAFKA_SECURITY_PROTOCOL.
+func GetKafkaSecurityProtocol() (string, error) {
+	s := viper.GetString(KAFKA_SECURITY_PROTOCOL)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_SECURITY_PROTOCOL, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_SECURITY_PROTOCOL, ErrNotDefined)
+}
+
+// GetKafkaSeek returns the value for the environment variable RELAYER_KAFKA_SEEK.
+func GetKafkaSeek() (string, error) {
+	s := viper.GetString(KAFKA_SEEK)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_SEEK, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_SEEK, ErrNotDefined)
+}
+
+// GetKafkaSessionTimeout returns the value for the environment variable RELAYER_KAFKA_SESSION_TIMEOUT.
+func GetKafkaSessionTimeout() (Duration, error) {
+	s := viper.GetString(KAFKA_SESSION_TIMEOUT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_SESSION_TIMEOUT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", KAFKA_SESSION_TIMEOUT, ErrNotDefined)
+}
+
+// GetKafkaStatsInterval returns the value for the environment variable RELAYER_KAFKA_STATS_INTERVAL.
+func GetKafkaStatsInterval() (Duration, error) {
+	s := viper.GetString(KAFKA_STATS_INTERVAL)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_STATS_INTERVAL, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", KAFKA_STATS_INTERVAL, ErrNotDefined)
+}
+
+// GetKafkaTlsCaFile returns the value for the environment variable RELAYER_KAFKA_TLS_CA_FILE.
+func GetKafkaTlsCaFile() (string, error) {
+	s := viper.GetString(KAFKA_TLS_CA_FILE)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_TLS_CA_FILE, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_TLS_CA_FILE, ErrNotDefined)
+}
+
+// GetKafkaTlsCertFile returns the value for the environment variable RELAYER_KAFKA_TLS_CERT_FILE.
+func GetKafkaTlsCertFile() (string, error) {
+	s := viper.GetString(KAFKA_TLS_CERT_FILE)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_TLS_CERT_FILE, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_TLS_CERT_FILE, ErrNotDefined)
+}
+
+// GetKafkaTlsKeyFile returns the value for the environment variable RELAYER_KAFKA_TLS_KEY_FILE.
+func GetKafkaTlsKeyFile() (string, error) {
+	s := viper.GetString(KAFKA_TLS_KEY_FILE)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_TLS_KEY_FILE, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", KAFKA_TLS_KEY_FILE, ErrNotDefined)
+}
+
+// GetKafkaTlsKeyPassword returns the value for the environment variable RELAYER_KAFKA_TLS_KEY_PASSWORD.
+func GetKafkaTlsKeyPassword() (RedactedString, error) {
+	s := viper.GetString(KAFKA_TLS_KEY_PASSWORD)
 	if s != "" {
 		v, err := toRedactedString(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", AUTH_PRIVATE_KEY, err)
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_TLS_KEY_PASSWORD, err)
 		}
 		return v, nil
 	}
-	return notDefinedRedactedString(), fmt.Errorf("%s: %w", AUTH_PRIVATE_KEY, ErrNotDefined)
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", KAFKA_TLS_KEY_PASSWORD, ErrNotDefined)
 }
 
-// GetBlockchainHttpEndpoint returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_ENDPOINT.
-func GetBlockchainHttpEndpoint() (URL, error) {
-	s := viper.GetString(BLOCKCHAIN_HTTP_ENDPOINT)
-	if s == "" {
-		filename := viper.GetString(BLOCKCHAIN_HTTP_ENDPOINT_FILE)
-		contents, err := os.ReadFile(filename)
+// GetKafkaTlsSkipVerify returns the value for the environment variable RELAYER_KAFKA_TLS_SKIP_VERIFY.
+func GetKafkaTlsSkipVerify() (bool, error) {
+	s := viper.GetString(KAFKA_TLS_SKIP_VERIFY)
+	if s != "" {
+		v, err := toBool(s)
 		if err != nil {
-			return notDefinedURL(), fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_ENDPOINT_FILE, err)
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_TLS_SKIP_VERIFY, err)
 		}
-		s = strings.TrimSpace(string(contents))
+		return v, nil
 	}
+	return notDefinedBool(), fmt.Errorf("%s: %w", KAFKA_TLS_SKIP_VERIFY, ErrNotDefined)
+}
+
+// GetKafkaTopics returns the value for the environment variable RELAYER_KAFKA_TOPICS.
+func GetKafkaTopics() ([]string, error) {
+	s := viper.GetString(KAFKA_TOPICS)
 	if s != "" {
-		v, err := toURL(s)
+		v, err := toSliceString(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_ENDPOINT, err)
+			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_TOPICS, err)
 		}
 		return v, nil
 	}
-	return notDefinedURL(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_ENDPOINT, ErrNotDefined)
+	return notDefinedSliceString(), fmt.Errorf("%s: %w", KAFKA_TOPICS, ErrNotDefined)
 }
 
-// GetBlockchainHttpMaxRetries returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_MAX_RETRIES.
-func GetBlockchainHttpMaxRetries() (uint64, error) {
-	s := viper.GetString(BLOCKCHAIN_HTTP_MAX_RETRIES)
+// GetOutboxClaimTimeout returns the value for the environment variable RELAYER_OUTBOX_CLAIM_TIMEOUT.
+func GetOutboxClaimTimeout() (Duration, error) {
+	s := viper.GetString(OUTBOX_CLAIM_TIMEOUT)
+	if s != "" {
+		v, err := toDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", OUTBOX_CLAIM_TIMEOUT, err)
+		}
+		return v, nil
+	}
+	return notDefinedDuration(), fmt.Errorf("%s: %w", OUTBOX_CLAIM_TIMEOUT, ErrNotDefined)
+}
+
+// GetOutboxMaxAttempts returns the value for the environment variable RELAYER_OUTBOX_MAX_ATTEMPTS.
+func GetOutboxMaxAttempts() (uint64, error) {
+	s := viper.GetString(OUTBOX_MAX_ATTEMPTS)
 	if s != "" {
 		v, err := toUint64(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_MAX_RETRIES, err)
+			return v, fmt.Errorf("failed to parse %s: %w", OUTBOX_MAX_ATTEMPTS, err)
 		}
 		return v, nil
 	}
-	return notDefinedUint64(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_MAX_RETRIES, ErrNotDefined)
+	return notDefinedUint64(), fmt.Errorf("%s: %w", OUTBOX_MAX_ATTEMPTS, ErrNotDefined)
 }
 
-// GetBlockchainHttpRetryMaxWait returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_RETRY_MAX_WAIT.
-func GetBlockchainHttpRetryMaxWait() (Duration, error) {
-	s := viper.GetString(BLOCKCHAIN_HTTP_RETRY_MAX_WAIT)
+// GetOutboxPollInterval returns the value for the environment variable RELAYER_OUTBOX_POLL_INTERVAL.
+func GetOutboxPollInterval() (Duration, error) {
+	s := viper.GetString(OUTBOX_POLL_INTERVAL)
 	if s != "" {
 		v, err := toDuration(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_RETRY_MAX_WAIT, err)
+			return v, fmt.Errorf("failed to parse %s: %w", OUTBOX_POLL_INTERVAL, err)
 		}
 		return v, nil
 	}
-	return notDefinedDuration(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_RETRY_MAX_WAIT, ErrNotDefined)
+	return notDefinedDuration(), fmt.Errorf("%s: %w", OUTBOX_POLL_INTERVAL, ErrNotDefined)
 }
 
-// GetBlockchainHttpRetryMinWait returns the value for the environment variable RELAYER_BLOCKCHAIN_HTTP_RETRY_MIN_WAIT.
-func GetBlockchainHttpRetryMinWait() (Duration, error) {
-	s := viper.GetString(BLOCKCHAIN_HTTP_RETRY_MIN_WAIT)
+// GetOutboxReplaceAfter returns the value for the environment variable RELAYER_OUTBOX_REPLACE_AFTER.
+func GetOutboxReplaceAfter() (Duration, error) {
+	s := viper.GetString(OUTBOX_REPLACE_AFTER)
 	if s != "" {
 		v, err := toDuration(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_HTTP_RETRY_MIN_WAIT, err)
+			return v, fmt.Errorf("failed to parse %s: %w", OUTBOX_REPLACE_AFTER, err)
 		}
 		return v, nil
 	}
-	return notDefinedDuration(), fmt.Errorf("%s: %w", BLOCKCHAIN_HTTP_RETRY_MIN_WAIT, ErrNotDefined)
+	return notDefinedDuration(), fmt.Errorf("%s: %w", OUTBOX_REPLACE_AFTER, ErrNotDefined)
 }
 
-// GetBlockchainId returns the value for the environment variable RELAYER_BLOCKCHAIN_ID.
-func GetBlockchainId() (uint64, error) {
-	s := viper.GetString(BLOCKCHAIN_ID)
+// GetOutboxRetention returns the value for the environment variable RELAYER_OUTBOX_RETENTION.
+func GetOutboxRetention() (Duration, error) {
+	s := viper.GetString(OUTBOX_RETENTION)
 	if s != "" {
-		v, err := toUint64(s)
+		v, err := toDuration(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", BLOCKCHAIN_ID, err)
+			return v, fmt.Errorf("failed to parse %s: %w", OUTBOX_RETENTION, err)
 		}
 		return v, nil
 	}
-	return notDefinedUint64(), fmt.Errorf("%s: %w", BLOCKCHAIN_ID, ErrNotDefined)
+	return notDefinedDuration(), fmt.Errorf("%s: %w", OUTBOX_RETENTION, ErrNotDefined)
 }
 
-// GetRewardTokenAddress returns the value for the environment variable RELAYER_REWARD_TOKEN_ADDRESS.
-func GetRewardTokenAddress() (Address, error) {
-	s := viper.GetString(REWARD_TOKEN_ADDRESS)
+// GetOutboxRetryMaxWait returns the value for the environment variable RELAYER_OUTBOX_RETRY_MAX_WAIT.
+func GetOutboxRetryMaxWait() (Duration, error) {
+	s := viper.GetString(OUTBOX_RETRY_MAX_WAIT)
 	if s != "" {
-		v, err := toAddress(s)
+		v, err := toDuration(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", REWARD_TOKEN_ADDRESS, err)
+			return v, fmt.Errorf("failed to parse %s: %w", OUTBOX_RETRY_MAX_WAIT, err)
 		}
 		return v, nil
 	}
-	return notDefinedAddress(), fmt.Errorf("%s: %w", REWARD_TOKEN_ADDRESS, ErrNotDefined)
+	return notDefinedDuration(), fmt.Errorf("%s: %w", OUTBOX_RETRY_MAX_WAIT, ErrNotDefined)
 }
 
-// GetDatabaseCollection returns the value for the environment variable RELAYER_DATABASE_COLLECTION.
-func GetDatabaseCollection() (RedactedString, error) {
-	s := viper.GetString(DATABASE_COLLECTION)
+// GetOutboxRetryMinWait returns the value for the environment variable RELAYER_OUTBOX_RETRY_MIN_WAIT.
+func GetOutboxRetryMinWait() (Duration, error) {
+	s := viper.GetString(OUTBOX_RETRY_MIN_WAIT)
 	if s != "" {
-		v, err := toRedactedString(s)
+		v, err := toDuration(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", DATABASE_COLLECTION, err)
+			return v, fmt.Errorf("failed to parse %s: %w", OUTBOX_RETRY_MIN_WAIT, err)
 		}
 		return v, nil
 	}
-	return notDefinedRedactedString(), fmt.Errorf("%s: %w", DATABASE_COLLECTION, ErrNotDefined)
+	return notDefinedDuration(), fmt.Errorf("%s: %w", OUTBOX_RETRY_MIN_WAIT, ErrNotDefined)
 }
 
-// GetDatabaseName returns the value for the environment variable RELAYER_DATABASE_NAME.
-func GetDatabaseName() (RedactedString, error) {
-	s := viper.GetString(DATABASE_NAME)
+// GetQualityMinInterval returns the value for the environment variable RELAYER_QUALITY_MIN_INTERVAL.
+func GetQualityMinInterval() (Duration, error) {
+	s := viper.GetString(QUALITY_MIN_INTERVAL)
 	if s != "" {
-		v, err := toRedactedString(s)
+		v, err := toDuration(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", DATABASE_NAME, err)
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_MIN_INTERVAL, err)
 		}
 		return v, nil
 	}
-	return notDefinedRedactedString(), fmt.Errorf("%s: %w", DATABASE_NAME, ErrNotDefined)
+	return notDefinedDuration(), fmt.Errorf("%s: %w", QUALITY_MIN_INTERVAL, ErrNotDefined)
 }
 
-// GetDatabaseUrl returns the value for the environment variable RELAYER_DATABASE_URL.
-func GetDatabaseUrl() (URL, error) {
-	s := viper.GetString(DATABASE_URL)
-	if s == "" {
-		filename := viper.GetString(DATABASE_URL_FILE)
-		contents, err := os.ReadFile(filename)
+// GetQualityNeighborRadius returns the value for the environment variable RELAYER_QUALITY_NEIGHBOR_RADIUS.
+func GetQualityNeighborRadius() (float64, error) {
+	s := viper.GetString(QUALITY_NEIGHBOR_RADIUS)
+	if s != "" {
+		v, err := toFloat64(s)
 		if err != nil {
-			return notDefinedURL(), fmt.Errorf("failed to parse %s: %w", DATABASE_URL_FILE, err)
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_NEIGHBOR_RADIUS, err)
 		}
-		s = strings.TrimSpace(string(contents))
+		return v, nil
 	}
+	return notDefinedFloat64(), fmt.Errorf("%s: %w", QUALITY_NEIGHBOR_RADIUS, ErrNotDefined)
+}
+
+// GetQualityNeighborTolerance returns the value for the environment variable RELAYER_QUALITY_NEIGHBOR_TOLERANCE.
+func GetQualityNeighborTolerance() (float64, error) {
+	s := viper.GetString(QUALITY_NEIGHBOR_TOLERANCE)
 	if s != "" {
-		v, err := toURL(s)
+		v, err := toFloat64(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", DATABASE_URL, err)
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_NEIGHBOR_TOLERANCE, err)
 		}
 		return v, nil
 	}
-	return notDefinedURL(), fmt.Errorf("%s: %w", DATABASE_URL, ErrNotDefined)
+	return notDefinedFloat64(), fmt.Errorf("%s: %w", QUALITY_NEIGHBOR_TOLERANCE, ErrNotDefined)
 }
 
-// GetKafkaBroker returns the value for the environment variable RELAYER_KAFKA_BROKER.
-func GetKafkaBroker() (URL, error) {
-	s := viper.GetString(KAFKA_BROKER)
+// GetQualityNeighborWindow returns the value for the environment variable RELAYER_QUALITY_NEIGHBOR_WINDOW.
+func GetQualityNeighborWindow() (Duration, error) {
+	s := viper.GetString(QUALITY_NEIGHBOR_WINDOW)
 	if s != "" {
-		v, err := toURL(s)
+		v, err := toDuration(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_BROKER, err)
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_NEIGHBOR_WINDOW, err)
 		}
 		return v, nil
 	}
-	return notDefinedURL(), fmt.Errorf("%s: %w", KAFKA_BROKER, ErrNotDefined)
+	return notDefinedDuration(), fmt.Errorf("%s: %w", QUALITY_NEIGHBOR_WINDOW, ErrNotDefined)
 }
 
-// GetKafkaTopics returns the value for the environment variable RELAYER_KAFKA_TOPICS.
-func GetKafkaTopics() ([]string, error) {
-	s := viper.GetString(KAFKA_TOPICS)
+// GetQualityRanges returns the value for the environment variable RELAYER_QUALITY_RANGES.
+func GetQualityRanges() (string, error) {
+	s := viper.GetString(QUALITY_RANGES)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_RANGES, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", QUALITY_RANGES, ErrNotDefined)
+}
+
+// GetQualitySpikeFactor returns the value for the environment variable RELAYER_QUALITY_SPIKE_FACTOR.
+func GetQualitySpikeFactor() (float64, error) {
+	s := viper.GetString(QUALITY_SPIKE_FACTOR)
+	if s != "" {
+		v, err := toFloat64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_SPIKE_FACTOR, err)
+		}
+		return v, nil
+	}
+	return notDefinedFloat64(), fmt.Errorf("%s: %w", QUALITY_SPIKE_FACTOR, ErrNotDefined)
+}
+
+// GetQualityStuckReadings returns the value for the environment variable RELAYER_QUALITY_STUCK_READINGS.
+func GetQualityStuckReadings() (uint64, error) {
+	s := viper.GetString(QUALITY_STUCK_READINGS)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_STUCK_READINGS, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", QUALITY_STUCK_READINGS, ErrNotDefined)
+}
+
+// GetQualityWithholdScore returns the value for the environment variable RELAYER_QUALITY_WITHHOLD_SCORE.
+func GetQualityWithholdScore() (float64, error) {
+	s := viper.GetString(QUALITY_WITHHOLD_SCORE)
+	if s != "" {
+		v, err := toFloat64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", QUALITY_WITHHOLD_SCORE, err)
+		}
+		return v, nil
+	}
+	return notDefinedFloat64(), fmt.Errorf("%s: %w", QUALITY_WITHHOLD_SCORE, ErrNotDefined)
+}
+
+// GetReceivers returns the value for the environment variable RELAYER_RECEIVERS.
+func GetReceivers() (string, error) {
+	s := viper.GetString(RECEIVERS)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", RECEIVERS, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", RECEIVERS, ErrNotDefined)
+}
+
+// GetReceiverDailyCap returns the value for the environment variable RELAYER_RECEIVER_DAILY_CAP.
+func GetReceiverDailyCap() (Wei, error) {
+	s := viper.GetString(RECEIVER_DAILY_CAP)
+	if s != "" {
+		v, err := toWei(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", RECEIVER_DAILY_CAP, err)
+		}
+		return v, nil
+	}
+	return notDefinedWei(), fmt.Errorf("%s: %w", RECEIVER_DAILY_CAP, ErrNotDefined)
+}
+
+// GetReceiverDenylist returns the value for the environment variable RELAYER_RECEIVER_DENYLIST.
+func GetReceiverDenylist() ([]string, error) {
+	s := viper.GetString(RECEIVER_DENYLIST)
 	if s != "" {
 		v, err := toSliceString(s)
 		if err != nil {
-			return v, fmt.Errorf("failed to parse %s: %w", KAFKA_TOPICS, err)
+			return v, fmt.Errorf("failed to parse %s: %w", RECEIVER_DENYLIST, err)
 		}
 		return v, nil
 	}
-	return notDefinedSliceString(), fmt.Errorf("%s: %w", KAFKA_TOPICS, ErrNotDefined)
+	return notDefinedSliceString(), fmt.Errorf("%s: %w", RECEIVER_DENYLIST, ErrNotDefined)
 }
 
 // GetLogColor returns the value for the environment variable RELAYER_LOG_COLOR.
@@ -543,3 +2235,68 @@ func GetTelemetryAddress() (string, error) {
 	}
 	return notDefinedString(), fmt.Errorf("%s: %w", TELEMETRY_ADDRESS, ErrNotDefined)
 }
+
+// GetWorkerCount returns the value for the environment variable RELAYER_WORKER_COUNT.
+func GetWorkerCount() (uint64, error) {
+	s := viper.GetString(WORKER_COUNT)
+	if s != "" {
+		v, err := toUint64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", WORKER_COUNT, err)
+		}
+		return v, nil
+	}
+	return notDefinedUint64(), fmt.Errorf("%s: %w", WORKER_COUNT, ErrNotDefined)
+}
+
+// GetSinkUrl returns the value for the environment variable RELAYER_SINK_URL.
+func GetSinkUrl() (RedactedString, error) {
+	s := viper.GetString(SINK_URL)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", SINK_URL, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", SINK_URL, ErrNotDefined)
+}
+
+// GetSourceUrl returns the value for the environment variable RELAYER_SOURCE_URL.
+func GetSourceUrl() (RedactedString, error) {
+	s := viper.GetString(SOURCE_URL)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", SOURCE_URL, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", SOURCE_URL, ErrNotDefined)
+}
+
+// GetTracingEndpoint returns the value for the environment variable RELAYER_TRACING_ENDPOINT.
+func GetTracingEndpoint() (string, error) {
+	s := viper.GetString(TRACING_ENDPOINT)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", TRACING_ENDPOINT, err)
+		}
+		return v, nil
+	}
+	return notDefinedString(), fmt.Errorf("%s: %w", TRACING_ENDPOINT, ErrNotDefined)
+}
+
+// GetTracingSampleRatio returns the value for the environment variable RELAYER_TRACING_SAMPLE_RATIO.
+func GetTracingSampleRatio() (float64, error) {
+	s := viper.GetString(TRACING_SAMPLE_RATIO)
+	if s != "" {
+		v, err := toFloat64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", TRACING_SAMPLE_RATIO, err)
+		}
+		return v, nil
+	}
+	return notDefinedFloat64(), fmt.Errorf("%s: %w", TRACING_SAMPLE_RATIO, ErrNotDefined)
+}
@@ -0,0 +1,188 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	. "github.com/henriquemarlon/city.fun/relayer/configs"
+)
+
+// GetClientConfigMap returns the settings shared by every Kafka client of the relayer: the
+// brokers, the security settings and the pass-through librdkafka properties.
+func GetClientConfigMap() (*ckafka.ConfigMap, error) {
+	configMap := &ckafka.ConfigMap{}
+	if err := setClientConfig(configMap); err != nil {
+		return nil, err
+	}
+	if err := setProperties(configMap); err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
+
+// GetConsumerConfigMap returns the settings of the relayer consumer group. Offsets are always
+// committed by the relayer, so auto commit is disabled.
+func GetConsumerConfigMap() (*ckafka.ConfigMap, error) {
+	configMap := &ckafka.ConfigMap{}
+	if err := setClientConfig(configMap); err != nil {
+		return nil, err
+	}
+
+	groupId, err := GetKafkaGroupId()
+	if err != nil {
+		return nil, err
+	}
+	offsetReset, err := GetKafkaAutoOffsetReset()
+	if err != nil {
+		return nil, err
+	}
+	sessionTimeout, err := GetKafkaSessionTimeout()
+	if err != nil {
+		return nil, err
+	}
+	heartbeatInterval, err := GetKafkaHeartbeatInterval()
+	if err != nil {
+		return nil, err
+	}
+	maxPollInterval, err := GetKafkaMaxPollInterval()
+	if err != nil {
+		return nil, err
+	}
+	statsInterval, err := GetKafkaStatsInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	(*configMap)["group.id"] = groupId
+	(*configMap)["auto.offset.reset"] = offsetReset
+	(*configMap)["enable.auto.commit"] = false
+	(*configMap)["session.timeout.ms"] = int(sessionTimeout.Milliseconds())
+	(*configMap)["heartbeat.interval.ms"] = int(heartbeatInterval.Milliseconds())
+	(*configMap)["max.poll.interval.ms"] = int(maxPollInterval.Milliseconds())
+	(*configMap)["statistics.interval.ms"] = int(statsInterval.Milliseconds())
+
+	if err := setProperties(configMap); err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
+
+func setClientConfig(configMap *ckafka.ConfigMap) error {
+	broker, err := GetKafkaBroker()
+	if err != nil {
+		return err
+	}
+	protocol, err := GetKafkaSecurityProtocol()
+	if err != nil {
+		return err
+	}
+	protocol = strings.ToLower(protocol)
+
+	(*configMap)["bootstrap.servers"] = broker.String()
+	(*configMap)["security.protocol"] = protocol
+
+	switch protocol {
+	case "plaintext":
+	case "ssl":
+		return setTLSConfig(configMap)
+	case "sasl_plaintext":
+		return setSASLConfig(configMap)
+	case "sasl_ssl":
+		if err := setSASLConfig(configMap); err != nil {
+			return err
+		}
+		return setTLSConfig(configMap)
+	default:
+		return fmt.Errorf("invalid kafka security protocol '%s'", protocol)
+	}
+	return nil
+}
+
+func setSASLConfig(configMap *ckafka.ConfigMap) error {
+	mechanism, err := GetKafkaSaslMechanism()
+	if err != nil {
+		return err
+	}
+	username, err := GetKafkaSaslUsername()
+	if err != nil {
+		return err
+	}
+	password, err := GetKafkaSaslPassword()
+	if err != nil {
+		return err
+	}
+
+	(*configMap)["sasl.mechanism"] = strings.ToUpper(mechanism)
+	(*configMap)["sasl.username"] = username
+	(*configMap)["sasl.password"] = password.Value
+	return nil
+}
+
+func setTLSConfig(configMap *ckafka.ConfigMap) error {
+	caFile, err := optional(GetKafkaTlsCaFile())
+	if err != nil {
+		return err
+	}
+	certFile, err := optional(GetKafkaTlsCertFile())
+	if err != nil {
+		return err
+	}
+	keyFile, err := optional(GetKafkaTlsKeyFile())
+	if err != nil {
+		return err
+	}
+	keyPassword, err := optional(GetKafkaTlsKeyPassword())
+	if err != nil {
+		return err
+	}
+	skipVerify, err := GetKafkaTlsSkipVerify()
+	if err != nil {
+		return err
+	}
+
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("%s and %s must be set together", KAFKA_TLS_CERT_FILE, KAFKA_TLS_KEY_FILE)
+	}
+
+	if caFile != "" {
+		(*configMap)["ssl.ca.location"] = caFile
+	}
+	if certFile != "" {
+		(*configMap)["ssl.certificate.location"] = certFile
+		(*configMap)["ssl.key.location"] = keyFile
+	}
+	if keyPassword.Value != "" {
+		(*configMap)["ssl.key.password"] = keyPassword.Value
+	}
+	if skipVerify {
+		(*configMap)["enable.ssl.certificate.verification"] = false
+		(*configMap)["ssl.endpoint.identification.algorithm"] = "none"
+	}
+	return nil
+}
+
+// setProperties applies the pass-through librdkafka properties, which override any setting
+// derived from the other entries.
+func setProperties(configMap *ckafka.ConfigMap) error {
+	properties, err := optional(GetKafkaProperties())
+	if err != nil {
+		return err
+	}
+	for _, property := range properties {
+		if err := configMap.Set(property); err != nil {
+			return fmt.Errorf("invalid %s entry '%s': %w", KAFKA_PROPERTIES, property, err)
+		}
+	}
+	return nil
+}
+
+// optional turns an undefined entry into its zero value.
+func optional[T any](value T, err error) (T, error) {
+	if errors.Is(err, ErrNotDefined) {
+		return value, nil
+	}
+	return value, err
+}
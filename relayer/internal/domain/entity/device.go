@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/device"
+)
+
+var (
+	ErrDeviceNotFound = errors.New("device not found")
+	ErrInvalidDevice  = errors.New("invalid device")
+	// ErrDeviceRegistered is returned for a device that already has another public key.
+	ErrDeviceRegistered = errors.New("device registered with another key")
+)
+
+// Device is the identity of a sensor: the public key its readings must be signed with.
+type Device struct {
+	SensorId  string    `bson:"_id" json:"sensor_id"`
+	PublicKey string    `bson:"public_key" json:"public_key"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func NewDevice(sensorId, publicKey string, at time.Time) (*Device, error) {
+	d := &Device{
+		SensorId:  sensorId,
+		PublicKey: publicKey,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Device) Validate() error {
+	if d.SensorId == "" {
+		return fmt.Errorf("%w: sensor id is required", ErrInvalidDevice)
+	}
+	if _, err := device.ParsePublicKey(d.PublicKey); err != nil {
+		return errors.Join(ErrInvalidDevice, err)
+	}
+	return nil
+}
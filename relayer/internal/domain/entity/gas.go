@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrGasBudgetExceeded is returned for a gas reservation that does not fit in what is left
+// of the daily gas budget.
+var ErrGasBudgetExceeded = errors.New("daily gas budget exceeded")
+
+// GasReservation is an amount of wei reserved on the gas budget of a UTC day for the mint
+// transaction of an outbox entry, or for one of its replacements.
+type GasReservation struct {
+	Day    time.Time `bson:"day" json:"day"`
+	Amount string    `bson:"amount" json:"amount"`
+}
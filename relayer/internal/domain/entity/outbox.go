@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrOutboxEntryNotFound = errors.New("outbox entry not found")
+	ErrInvalidOutboxEntry  = errors.New("invalid outbox entry")
+	ErrDuplicateReading    = errors.New("reading already rewarded")
+	ErrOutboxEntryChanged  = errors.New("outbox entry changed by another dispatcher")
+)
+
+type OutboxState string
+
+const (
+	// The reward was saved and the mint transaction was not sent yet.
+	OutboxStatePendingMint OutboxState = "pending_mint"
+	// The mint transaction was signed, stored and broadcast, but has no receipt yet.
+	OutboxStateSubmitted OutboxState = "submitted"
+	// The mint transaction was included in a block and succeeded.
+	OutboxStateConfirmed OutboxState = "confirmed"
+	// The mint transaction reverted or the entry ran out of attempts.
+	OutboxStateFailed OutboxState = "failed"
+)
+
+type OutboxEntry struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RewardId     primitive.ObjectID `bson:"reward_id" json:"reward_id"`
+	ReadingId    string             `bson:"reading_id,omitempty" json:"reading_id"`
+	SensorId     string             `bson:"sensor_id,omitempty" json:"sensor_id,omitempty"`
+	Token        string             `bson:"token" json:"token"`
+	Amount       string             `bson:"amount" json:"amount"`
+	Receiver     string             `bson:"receiver" json:"receiver"`
+	Latitude     float64            `bson:"latitude" json:"latitude"`
+	Longitude    float64            `bson:"longitude" json:"longitude"`
+	State        OutboxState        `bson:"state" json:"state"`
+	Attempts     int                `bson:"attempts" json:"attempts"`
+	LastError    string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	TxHash       string             `bson:"tx_hash,omitempty" json:"tx_hash,omitempty"`
+	RawTx        string             `bson:"raw_tx,omitempty" json:"-"`
+	ClaimedBy    string             `bson:"claimed_by,omitempty" json:"-"`
+	ClaimedUntil time.Time          `bson:"claimed_until" json:"-"`
+	Events       []RewardEvent      `bson:"events" json:"events"`
+	// SubmittedAt is when TxHash was signed. ReplacedTxs are the transactions with the same
+	// nonce that TxHash replaced, since any of them may still be mined instead.
+	SubmittedAt time.Time `bson:"submitted_at,omitempty" json:"submitted_at,omitempty"`
+	ReplacedTxs []string  `bson:"replaced_txs,omitempty" json:"replaced_txs,omitempty"`
+	// CapDay is the UTC day the amount was counted against the daily cap of the receiver,
+	// and zero when it has no cap. A failed mint gives the amount back.
+	CapDay time.Time `bson:"cap_day,omitempty" json:"-"`
+	// GasReserved is what the mint transaction and its replacements reserved on the daily
+	// gas budget, settled at the real cost once the transaction has a receipt.
+	GasReserved []GasReservation `bson:"gas_reserved,omitempty" json:"-"`
+	// Trace is the trace context of the message that created the entry, so that minting
+	// continues the trace of the reading.
+	Trace     map[string]string `bson:"trace,omitempty" json:"-"`
+	CreatedAt time.Time         `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time         `bson:"updated_at" json:"updated_at"`
+}
+
+// OutboxGuard is what an outbox entry held when a dispatcher loaded it. An update only
+// applies while the stored entry still holds it, so that a dispatcher whose claim ran out
+// never overwrites the work of the one that took the entry over.
+type OutboxGuard struct {
+	State OutboxState
+	// ClaimedBy and ClaimedUntil identify the claim of a pending entry.
+	ClaimedBy    string
+	ClaimedUntil time.Time
+	// TxHash is the transaction of a submitted entry.
+	TxHash string
+}
+
+// Guard returns what the entry holds now, to update it later.
+func (e *OutboxEntry) Guard() OutboxGuard {
+	return OutboxGuard{
+		State:        e.State,
+		ClaimedBy:    e.ClaimedBy,
+		ClaimedUntil: e.ClaimedUntil,
+		TxHash:       e.TxHash,
+	}
+}
+
+func NewOutboxEntry(reward *Reward, readingId string) (*OutboxEntry, error) {
+	entry := &OutboxEntry{
+		RewardId:  reward.Id,
+		ReadingId: readingId,
+		SensorId:  reward.SensorId,
+		Token:     reward.Token,
+		Amount:    reward.Amount,
+		Receiver:  reward.Receiver,
+		Latitude:  reward.Latitude,
+		Longitude: reward.Longitude,
+		State:     OutboxStatePendingMint,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	entry.Events = []RewardEvent{NewRewardEvent(entry, RewardEventCreated)}
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (e *OutboxEntry) Validate() error {
+	if e.RewardId.IsZero() || e.ReadingId == "" {
+		return ErrInvalidOutboxEntry
+	}
+	if e.Token == "" || e.Amount == "" || e.Receiver == "" {
+		return ErrInvalidOutboxEntry
+	}
+	if e.CreatedAt.IsZero() {
+		return ErrInvalidOutboxEntry
+	}
+	return nil
+}
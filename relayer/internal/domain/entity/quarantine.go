@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrQuarantinedReadingNotFound = errors.New("quarantined reading not found")
+	// ErrNotReleasable is returned for a quarantined reading that cannot be processed again,
+	// because it was never verified to come from its device.
+	ErrNotReleasable = errors.New("quarantined reading cannot be released")
+)
+
+// QuarantineCheck is the check that held a reading back.
+type QuarantineCheck string
+
+const (
+	// QuarantineSignature holds readings not signed by the registered key of their device.
+	QuarantineSignature QuarantineCheck = "signature"
+	// QuarantinePayout holds readings of sensors that are not registered or claim another
+	// payout.
+	QuarantinePayout QuarantineCheck = "payout"
+	// QuarantineReceiver holds rewards that the receiver policy does not let be minted: to a
+	// denied or unlisted receiver, or over its daily cap.
+	QuarantineReceiver QuarantineCheck = "receiver"
+)
+
+// QuarantinedReading is a reading held back instead of rewarded, because it could not be
+// verified to come from its device, its payout disagrees with the registry, or the receiver
+// policy holds its reward. It is kept as received, to be inspected, and the ones that passed
+// verification may be released to be processed again from the check that held them.
+type QuarantinedReading struct {
+	ReadingId  string          `bson:"_id" json:"reading_id"`
+	SensorId   string          `bson:"sensor_id" json:"sensor_id"`
+	Check      QuarantineCheck `bson:"check,omitempty" json:"check,omitempty"`
+	Reason     string          `bson:"reason" json:"reason"`
+	Topic      string          `bson:"topic" json:"topic"`
+	Payload    string          `bson:"payload" json:"payload"`
+	ReceivedAt time.Time       `bson:"received_at" json:"received_at"`
+	// Receiver and Amount are the payout decided for a reward held by the receiver policy,
+	// which it is paid when released.
+	Receiver string `bson:"receiver,omitempty" json:"receiver,omitempty"`
+	Amount   string `bson:"amount,omitempty" json:"amount,omitempty"`
+	// ReleaseAt is when the reading is due to be processed again: when an operator releases
+	// it, or the next UTC day for a reward over the daily cap. It is zero while held.
+	ReleaseAt time.Time `bson:"release_at,omitempty" json:"release_at,omitempty"`
+}
+
+// Releasable reports whether the reading passed verification, and may be processed again.
+func (q *QuarantinedReading) Releasable() bool {
+	return q.Check == QuarantinePayout || q.Check == QuarantineReceiver
+}
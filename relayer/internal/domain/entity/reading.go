@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/aqi"
+)
+
+var (
+	ErrReadingNotFound = errors.New("reading not found")
+	ErrInvalidReading  = errors.New("invalid reading")
+)
+
+// Reading holds the values of the parameters a sensor measured, such as co2 or mp25, parsed
+// from the data of a verified reading, rewarded or not. AQI is the index of the reading alone, nil when it
+// measures none of the pollutants of the index.
+type Reading struct {
+	ReadingId  string             `json:"reading_id"`
+	SensorId   string             `json:"sensor_id,omitempty"`
+	Latitude   float64            `json:"latitude"`
+	Longitude  float64            `json:"longitude"`
+	Values     map[string]float64 `json:"values"`
+	AQI        *aqi.Result        `json:"aqi,omitempty"`
+	RecordedAt time.Time          `json:"recorded_at"`
+}
+
+// NewReading parses the data of a reading, a JSON object of numbers by parameter.
+func NewReading(readingId, sensorId string, latitude, longitude float64, data string, recordedAt time.Time) (*Reading, error) {
+	var values map[string]float64
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, errors.Join(ErrInvalidReading, err)
+	}
+	if len(values) == 0 {
+		return nil, ErrInvalidReading
+	}
+	return &Reading{
+		ReadingId:  readingId,
+		SensorId:   sensorId,
+		Latitude:   latitude,
+		Longitude:  longitude,
+		Values:     values,
+		RecordedAt: recordedAt,
+	}, nil
+}
+
+type ParameterStats struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Avg   float64 `json:"avg"`
+	Count int64   `json:"count"`
+}
+
+// ReadingWindow summarizes the readings of a sensor in the window starting at Start.
+type ReadingWindow struct {
+	SensorId   string                    `json:"sensor_id,omitempty"`
+	Latitude   float64                   `json:"latitude"`
+	Longitude  float64                   `json:"longitude"`
+	Start      time.Time                 `json:"start"`
+	Readings   int64                     `json:"readings"`
+	Parameters map[string]ParameterStats `json:"parameters"`
+}
+
+// HeatmapCell summarizes a parameter over the sensors in a cell of a grid. Latitude and
+// Longitude are the south-west corner of the cell.
+type HeatmapCell struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Avg       float64 `json:"avg"`
+	Readings  int64   `json:"readings"`
+	Sensors   int     `json:"sensors"`
+}
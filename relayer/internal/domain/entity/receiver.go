@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+)
+
+var (
+	ErrReceiverNotFound = errors.New("receiver not registered")
+	ErrInvalidReceiver  = errors.New("invalid receiver")
+	// ErrDailyCapReached is returned for an amount that does not fit in what is left of the
+	// daily cap of a receiver.
+	ErrDailyCapReached = errors.New("daily cap reached")
+)
+
+// ReceiverStatus is whether rewards may be paid to a receiver.
+type ReceiverStatus string
+
+const (
+	ReceiverAllowed ReceiverStatus = "allowed"
+	ReceiverDenied  ReceiverStatus = "denied"
+)
+
+// Receiver is a wallet the relayer registers, to allow or deny rewards to it and cap them.
+type Receiver struct {
+	Address   string         `bson:"_id" json:"address"` // checksummed
+	Status    ReceiverStatus `bson:"status" json:"status"`
+	ENSName   string         `bson:"ens_name,omitempty" json:"ens_name,omitempty"`
+	DailyCap  string         `bson:"daily_cap,omitempty" json:"daily_cap,omitempty"` // most rewarded per UTC day, in the smallest unit of the token, the default when empty
+	Reason    string         `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreatedAt time.Time      `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `bson:"updated_at" json:"updated_at"`
+}
+
+func NewReceiver(address string, status ReceiverStatus, ensName, dailyCap, reason string, at time.Time) (*Receiver, error) {
+	r := &Receiver{
+		Address:   address,
+		Status:    status,
+		ENSName:   strings.TrimSuffix(ensName, "."),
+		DailyCap:  dailyCap,
+		Reason:    reason,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	r.Address = common.HexToAddress(address).Hex()
+	return r, nil
+}
+
+func (r *Receiver) Validate() error {
+	if _, err := ethutil.ParseAddress(r.Address); err != nil {
+		return errors.Join(ErrInvalidReceiver, err)
+	}
+	if r.Status != ReceiverAllowed && r.Status != ReceiverDenied {
+		return fmt.Errorf("%w: status must be allowed or denied", ErrInvalidReceiver)
+	}
+	if r.ENSName != "" && !validENSName(r.ENSName) {
+		return fmt.Errorf("%w: invalid ENS name %q", ErrInvalidReceiver, r.ENSName)
+	}
+	if r.DailyCap != "" {
+		if _, err := r.Cap(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cap returns the daily cap of the receiver, or nil when it has none of its own.
+func (r *Receiver) Cap() (*big.Int, error) {
+	if r.DailyCap == "" {
+		return nil, nil
+	}
+	limit, ok := new(big.Int).SetString(r.DailyCap, 10)
+	if !ok || limit.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: daily cap must be a positive integer", ErrInvalidReceiver)
+	}
+	return limit, nil
+}
+
+// validENSName checks that a name is made of at least two normalized labels, such as
+// alice.eth. It does not resolve it: the name is only stored along with the address.
+func validENSName(name string) bool {
+	if len(name) > 255 || name != strings.ToLower(name) || strings.ContainsAny(name, " \t\r\n/") {
+		return false
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" {
+			return false
+		}
+	}
+	return true
+}
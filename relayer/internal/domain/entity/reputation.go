@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/quality"
+)
+
+var (
+	ErrReputationNotFound = errors.New("sensor reputation not found")
+	ErrReputationConflict = errors.New("sensor reputation changed concurrently")
+)
+
+// maxReputationReasons bounds the findings kept with a reputation, the latest ones.
+const maxReputationReasons = 50
+
+// ReputationReason is a finding of the checks on a reading.
+type ReputationReason struct {
+	ReadingId       string `bson:"reading_id" json:"reading_id"`
+	quality.Finding `bson:",inline"`
+	At              time.Time `bson:"at" json:"at"`
+}
+
+// SensorReputation is the score of a sensor, the state of the checks on its readings and the
+// reasons it lost score. Version guards it against concurrent updates.
+type SensorReputation struct {
+	SensorId      string `bson:"_id" json:"sensor_id"`
+	quality.State `bson:",inline"`
+	Rejected      int64              `bson:"rejected" json:"rejected"`
+	Flagged       int64              `bson:"flagged" json:"flagged"`
+	Withheld      int64              `bson:"withheld" json:"withheld"`
+	LastReadingId string             `bson:"last_reading_id" json:"last_reading_id"`
+	LastVerdict   ReputationVerdict  `bson:"last_verdict" json:"last_verdict"`
+	Reasons       []ReputationReason `bson:"reasons" json:"reasons"`
+	Version       int64              `bson:"version" json:"-"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ReputationVerdict is the outcome of the checks on the last reading, kept to answer a
+// redelivery of it the same way.
+type ReputationVerdict struct {
+	Rejected bool    `bson:"rejected" json:"rejected"`
+	Withheld bool    `bson:"withheld" json:"withheld"`
+	Score    float64 `bson:"score" json:"score"`
+}
+
+func NewSensorReputation(sensorId string) *SensorReputation {
+	return &SensorReputation{SensorId: sensorId}
+}
+
+// Record adds the verdict on a reading to the counters and reasons of the reputation.
+func (r *SensorReputation) Record(readingId string, verdict quality.Verdict, at time.Time) {
+	switch {
+	case verdict.Rejected:
+		r.Rejected++
+	case len(verdict.Findings) > 0:
+		r.Flagged++
+	}
+	if verdict.Withheld {
+		r.Withheld++
+	}
+	for _, finding := range verdict.Findings {
+		r.Reasons = append(r.Reasons, ReputationReason{ReadingId: readingId, Finding: finding, At: at})
+	}
+	if len(r.Reasons) > maxReputationReasons {
+		r.Reasons = r.Reasons[len(r.Reasons)-maxReputationReasons:]
+	}
+	r.LastReadingId = readingId
+	r.LastVerdict = ReputationVerdict{Rejected: verdict.Rejected, Withheld: verdict.Withheld, Score: verdict.Score}
+	r.UpdatedAt = at
+}
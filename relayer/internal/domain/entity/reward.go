@@ -9,13 +9,14 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var (
-	ErrRewardNotFound = errors.New("reward not found")
-	ErrInvalidReward  = errors.New("invalid reward")
-)
+var ErrInvalidReward = errors.New("invalid reward")
 
+// Reward is the payment of one reading. It is stored as the outbox entry of the reading,
+// under RewardId. Rewards used to be stored one per location and updated by every reading,
+// and the entries created back then still point at those documents.
 type Reward struct {
 	Id        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SensorId  string             `bson:"sensor_id,omitempty" json:"sensor_id,omitempty"`
 	Token     string             `bson:"token" json:"token"`
 	Amount    string             `bson:"amount" json:"amount"`
 	Receiver  string             `bson:"receiver" json:"receiver"`
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RewardEventType string
+
+const (
+	// The reward was saved and queued for minting.
+	RewardEventCreated RewardEventType = "reward.created"
+	// The mint transaction was signed, stored and broadcast.
+	RewardEventMinted RewardEventType = "reward.minted"
+	// The mint transaction was included in a block and succeeded.
+	RewardEventConfirmed RewardEventType = "reward.confirmed"
+	// The mint transaction reverted or the reward ran out of attempts.
+	RewardEventFailed RewardEventType = "reward.failed"
+)
+
+// RewardEvent is a change in the lifecycle of a reward. Events are stored in the outbox entry
+// of the reward by the same write that changes its state, so the published events always
+// match the database, and are published later, at least once, in the order they happened.
+type RewardEvent struct {
+	Id          string          `bson:"id" json:"id"`
+	Type        RewardEventType `bson:"type" json:"type"`
+	TxHash      string          `bson:"tx_hash,omitempty" json:"tx_hash,omitempty"`
+	Error       string          `bson:"error,omitempty" json:"error,omitempty"`
+	OccurredAt  time.Time       `bson:"occurred_at" json:"occurred_at"`
+	PublishedAt *time.Time      `bson:"published_at" json:"-"`
+	// PublishFailures counts the times the sink refused the event, and PublishError is the
+	// last reason. An event refused too often is sent to the dead-letter topic.
+	PublishFailures int    `bson:"publish_failures,omitempty" json:"-"`
+	PublishError    string `bson:"publish_error,omitempty" json:"-"`
+}
+
+// NewRewardEvent records the current state of an outbox entry as an event. It does not add
+// the event to the entry: the event is saved along with the next update of the entry.
+func NewRewardEvent(entry *OutboxEntry, eventType RewardEventType) RewardEvent {
+	return RewardEvent{
+		Id:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		TxHash:     entry.TxHash,
+		Error:      entry.LastError,
+		OccurredAt: time.Now(),
+	}
+}
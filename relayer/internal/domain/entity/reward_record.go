@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrRewardRecordNotFound = errors.New("reward record not found")
+
+// RewardRecord is the immutable record of a reading and of the reward paid for it, along with
+// the data of the reading, which the reward does not keep.
+type RewardRecord struct {
+	ReadingId  string             `json:"reading_id"`
+	RewardId   primitive.ObjectID `json:"reward_id"`
+	SensorId   string             `json:"sensor_id,omitempty"`
+	Latitude   float64            `json:"latitude"`
+	Longitude  float64            `json:"longitude"`
+	Token      string             `json:"token"`
+	Amount     string             `json:"amount"`
+	Receiver   string             `json:"receiver"`
+	Data       string             `json:"data"`
+	RecordedAt time.Time          `json:"recorded_at"`
+}
+
+// NewRewardRecord records the reading paid by an outbox entry, taken at the given location
+// with the given data.
+func NewRewardRecord(entry *OutboxEntry, latitude, longitude float64, data string) *RewardRecord {
+	return &RewardRecord{
+		ReadingId:  entry.ReadingId,
+		RewardId:   entry.RewardId,
+		SensorId:   entry.SensorId,
+		Latitude:   latitude,
+		Longitude:  longitude,
+		Token:      entry.Token,
+		Amount:     entry.Amount,
+		Receiver:   entry.Receiver,
+		Data:       data,
+		RecordedAt: entry.CreatedAt,
+	}
+}
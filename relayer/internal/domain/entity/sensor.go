@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+)
+
+var (
+	ErrSensorNotFound = errors.New("sensor not registered")
+	ErrInvalidSensor  = errors.New("invalid sensor")
+)
+
+// Sensor is the payout of a sensor as the relayer registers it: who its readings reward and
+// how much. It takes precedence over what the readings claim.
+type Sensor struct {
+	SensorId  string    `bson:"_id" json:"sensor_id"`
+	Receiver  string    `bson:"receiver" json:"receiver"`
+	Rate      string    `bson:"rate" json:"rate"` // amount paid per reading, in the smallest unit of the token
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func NewSensor(sensorId, receiver, rate string, at time.Time) (*Sensor, error) {
+	s := &Sensor{
+		SensorId:  sensorId,
+		Receiver:  receiver,
+		Rate:      rate,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	s.Receiver = common.HexToAddress(receiver).Hex()
+	return s, nil
+}
+
+func (s *Sensor) Validate() error {
+	if s.SensorId == "" {
+		return fmt.Errorf("%w: sensor id is required", ErrInvalidSensor)
+	}
+	receiver, err := ethutil.ParseAddress(s.Receiver)
+	if err != nil {
+		return errors.Join(ErrInvalidSensor, err)
+	}
+	if receiver == (common.Address{}) {
+		return fmt.Errorf("%w: receiver is the zero address", ErrInvalidSensor)
+	}
+	rate, ok := new(big.Int).SetString(s.Rate, 10)
+	if !ok || rate.Sign() <= 0 {
+		return fmt.Errorf("%w: rate must be a positive integer", ErrInvalidSensor)
+	}
+	return nil
+}
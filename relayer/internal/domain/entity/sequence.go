@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrStaleSequence is returned for a reading whose sequence number is not above the last one
+// seen from its sensor.
+var ErrStaleSequence = errors.New("sequence number not above the last one of the sensor")
+
+// SensorSequence is the last reading seen from a sensor, which later readings must follow.
+type SensorSequence struct {
+	SensorId  string    `bson:"_id" json:"sensor_id"`
+	Sequence  int64     `bson:"sequence" json:"sequence"`
+	ReadingId string    `bson:"reading_id" json:"reading_id"`
+	EmittedAt time.Time `bson:"emitted_at" json:"emitted_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
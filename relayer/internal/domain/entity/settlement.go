@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrSettlementNotFound = errors.New("settlement not found")
+
+// Settlement is a reading paid to a receiver, as read from its outbox entry.
+type Settlement struct {
+	Id        primitive.ObjectID `bson:"_id" json:"id"`
+	RewardId  primitive.ObjectID `bson:"reward_id" json:"reward_id"`
+	ReadingId string             `bson:"reading_id" json:"reading_id"`
+	SensorId  string             `bson:"sensor_id,omitempty" json:"sensor_id,omitempty"`
+	Token     string             `bson:"token" json:"token"`
+	Amount    string             `bson:"amount" json:"amount"`
+	Receiver  string             `bson:"receiver" json:"receiver"`
+	Latitude  float64            `bson:"latitude" json:"latitude"`
+	Longitude float64            `bson:"longitude" json:"longitude"`
+	State     OutboxState        `bson:"state" json:"state"`
+	Attempts  int                `bson:"attempts" json:"attempts"`
+	TxHash    string             `bson:"tx_hash,omitempty" json:"tx_hash,omitempty"`
+	LastError string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	Events    []RewardEvent      `bson:"events,omitempty" json:"events,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveDevice upserts a device, keeping the time it was first registered.
+func (m *MongoDBRepository) SaveDevice(ctx context.Context, device *entity.Device) (*entity.Device, error) {
+	var saved entity.Device
+	err := m.Devices.FindOneAndUpdate(ctx,
+		bson.M{"_id": device.SensorId},
+		bson.M{
+			"$set":         bson.M{"public_key": device.PublicKey, "updated_at": device.UpdatedAt},
+			"$setOnInsert": bson.M{"created_at": device.CreatedAt},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (m *MongoDBRepository) CreateDevice(ctx context.Context, device *entity.Device) error {
+	_, err := m.Devices.InsertOne(ctx, device)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.ErrDeviceRegistered
+	}
+	return err
+}
+
+func (m *MongoDBRepository) FindDevice(ctx context.Context, sensorId string) (*entity.Device, error) {
+	var device entity.Device
+	err := m.Devices.FindOne(ctx, bson.M{"_id": sensorId}).Decode(&device)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (m *MongoDBRepository) DeleteDevice(ctx context.Context, sensorId string) error {
+	res, err := m.Devices.DeleteOne(ctx, bson.M{"_id": sensorId})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return entity.ErrDeviceNotFound
+	}
+	return nil
+}
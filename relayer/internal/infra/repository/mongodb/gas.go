@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type gasDay struct {
+	Spent primitive.Decimal128 `bson:"spent"`
+}
+
+// ReserveGasSpend adds amount to the gas spent on a UTC day, in a single update that only
+// matches while the total plus amount is within a positive limit, and returns the new total.
+// As for the receivers, a full day makes the upsert collide with the existing counter.
+func (m *MongoDBRepository) ReserveGasSpend(ctx context.Context, day time.Time, amount, limit *big.Int) (*big.Int, error) {
+	day = day.UTC().Truncate(24 * time.Hour)
+	filter := bson.M{"_id": day.Format(time.DateOnly)}
+	if limit != nil && limit.Sign() > 0 {
+		room := new(big.Int).Sub(limit, amount)
+		if room.Sign() < 0 {
+			return nil, entity.ErrGasBudgetExceeded
+		}
+		maxSpent, err := primitive.ParseDecimal128(room.String())
+		if err != nil {
+			return nil, err
+		}
+		filter["spent"] = bson.M{"$lte": maxSpent}
+	}
+
+	spent, err := m.incGasSpend(ctx, filter, day, amount)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, entity.ErrGasBudgetExceeded
+	}
+	return spent, err
+}
+
+// AdjustGasSpend adds delta, which may be negative, to the gas spent on a UTC day, and
+// returns the new total.
+func (m *MongoDBRepository) AdjustGasSpend(ctx context.Context, day time.Time, delta *big.Int) (*big.Int, error) {
+	day = day.UTC().Truncate(24 * time.Hour)
+	return m.incGasSpend(ctx, bson.M{"_id": day.Format(time.DateOnly)}, day, delta)
+}
+
+func (m *MongoDBRepository) incGasSpend(ctx context.Context, filter bson.M, day time.Time, amount *big.Int) (*big.Int, error) {
+	inc, err := primitive.ParseDecimal128(amount.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var saved gasDay
+	err = m.GasDays.FindOneAndUpdate(ctx, filter,
+		bson.M{
+			"$inc":         bson.M{"spent": inc},
+			"$set":         bson.M{"updated_at": time.Now()},
+			"$setOnInsert": bson.M{"day": day},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+
+	spent, exp, err := saved.Spent.BigInt()
+	if err != nil {
+		return nil, err
+	}
+	if exp < 0 {
+		return nil, fmt.Errorf("invalid gas spent %s", saved.Spent)
+	}
+	return spent.Mul(spent, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)), nil
+}
@@ -0,0 +1,75 @@
+package mongodb
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func TestReserveGasSpend(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	at := time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC)
+	spent := func(value string) bson.E {
+		dec, err := primitive.ParseDecimal128(value)
+		require.NoError(t, err)
+		return bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "2026-03-14"}, {Key: "spent", Value: dec}}}
+	}
+
+	mt.Run("reserves with a conditional upsert", func(mt *mtest.T) {
+		repo := &MongoDBRepository{GasDays: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(spent("500")))
+
+		total, err := repo.ReserveGasSpend(context.Background(), at, big.NewInt(300), big.NewInt(1000))
+		require.NoError(mt, err)
+		assert.Equal(mt, big.NewInt(500), total)
+
+		command := mt.GetStartedEvent().Command
+		assert.True(mt, command.Lookup("upsert").Boolean())
+		assert.Equal(mt, "2026-03-14", command.Lookup("query", "_id").StringValue())
+		// spent + 300 <= 1000
+		assert.Equal(mt, "700", command.Lookup("query", "spent", "$lte").Decimal128().String())
+		assert.Equal(mt, "300", command.Lookup("update", "$inc", "spent").Decimal128().String())
+	})
+
+	mt.Run("no limit", func(mt *mtest.T) {
+		repo := &MongoDBRepository{GasDays: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(spent("300")))
+
+		_, err := repo.ReserveGasSpend(context.Background(), at, big.NewInt(300), nil)
+		require.NoError(mt, err)
+
+		_, err = mt.GetStartedEvent().Command.Lookup("query").Document().LookupErr("spent")
+		assert.Error(mt, err)
+	})
+
+	mt.Run("budget exceeded when the upsert collides", func(mt *mtest.T) {
+		repo := &MongoDBRepository{GasDays: mt.Coll}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    11000,
+			Message: "E11000 duplicate key error",
+		}))
+
+		_, err := repo.ReserveGasSpend(context.Background(), at, big.NewInt(300), big.NewInt(1000))
+		assert.ErrorIs(mt, err, entity.ErrGasBudgetExceeded)
+	})
+
+	mt.Run("adjust", func(mt *mtest.T) {
+		repo := &MongoDBRepository{GasDays: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(spent("200")))
+
+		total, err := repo.AdjustGasSpend(context.Background(), at, big.NewInt(-300))
+		require.NoError(mt, err)
+		assert.Equal(mt, big.NewInt(200), total)
+		assert.Equal(mt, "-300", mt.GetStartedEvent().Command.Lookup("update", "$inc", "spent").Decimal128().String())
+	})
+}
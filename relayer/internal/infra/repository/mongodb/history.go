@@ -0,0 +1,203 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// codeNamespaceExists is returned when creating a collection or view that already exists.
+const codeNamespaceExists = 48
+
+// The history is a time series of reward records, with the sensor as its metadata. Amounts
+// are stored as decimals, so that they can be summed and charted.
+type rewardRecordSensor struct {
+	Id        string  `bson:"id,omitempty"`
+	Latitude  float64 `bson:"latitude"`
+	Longitude float64 `bson:"longitude"`
+}
+
+type rewardRecordDocument struct {
+	Sensor     rewardRecordSensor   `bson:"sensor"`
+	RecordedAt time.Time            `bson:"recorded_at"`
+	ReadingId  string               `bson:"reading_id"`
+	RewardId   primitive.ObjectID   `bson:"reward_id"`
+	Token      string               `bson:"token"`
+	Amount     primitive.Decimal128 `bson:"amount"`
+	Receiver   string               `bson:"receiver"`
+	Data       string               `bson:"data"`
+}
+
+// createHistory creates the history and readings time series, the view of the latest record
+// of every sensor and the collection of hourly rollups, unless they exist.
+func (m *MongoDBRepository) createHistory(ctx context.Context) error {
+	db := m.History.Database()
+
+	for _, coll := range []*mongo.Collection{m.History, m.Readings} {
+		err := db.CreateCollection(ctx, coll.Name(), options.CreateCollection().
+			SetTimeSeriesOptions(options.TimeSeries().
+				SetTimeField("recorded_at").
+				SetMetaField("sensor").
+				SetGranularity("minutes")))
+		if err != nil && !isNamespaceExists(err) {
+			return err
+		}
+		if _, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "sensor", Value: 1}, {Key: "recorded_at", Value: -1}}},
+			{Keys: bson.D{{Key: "reading_id", Value: 1}}},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// The sort on the metadata and time lets MongoDB answer this from the index below,
+	// instead of reading the whole history.
+	err := db.CreateView(ctx, m.Current.Name(), m.History.Name(), mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "sensor", Value: 1}, {Key: "recorded_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$sensor",
+			"recorded_at": bson.M{"$first": "$recorded_at"},
+			"reading_id":  bson.M{"$first": "$reading_id"},
+			"reward_id":   bson.M{"$first": "$reward_id"},
+			"token":       bson.M{"$first": "$token"},
+			"amount":      bson.M{"$first": "$amount"},
+			"receiver":    bson.M{"$first": "$receiver"},
+			"data":        bson.M{"$first": "$data"},
+		}}},
+	})
+	if err != nil && !isNamespaceExists(err) {
+		return err
+	}
+
+	// Nearby sensors are looked up by location when their readings are cross-checked.
+	if _, err := m.Readings.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sensor.latitude", Value: 1}, {Key: "sensor.longitude", Value: 1}, {Key: "recorded_at", Value: -1}},
+	}); err != nil {
+		return err
+	}
+
+	_, err = m.HistoryHourly.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "hour", Value: -1}},
+	})
+	return err
+}
+
+func (m *MongoDBRepository) CreateRewardRecord(ctx context.Context, record *entity.RewardRecord) error {
+	amount, err := primitive.ParseDecimal128(record.Amount)
+	if err != nil {
+		return err
+	}
+	_, err = m.History.InsertOne(ctx, rewardRecordDocument{
+		Sensor: rewardRecordSensor{
+			Id:        record.SensorId,
+			Latitude:  record.Latitude,
+			Longitude: record.Longitude,
+		},
+		RecordedAt: record.RecordedAt,
+		ReadingId:  record.ReadingId,
+		RewardId:   record.RewardId,
+		Token:      record.Token,
+		Amount:     amount,
+		Receiver:   record.Receiver,
+		Data:       record.Data,
+	})
+	return err
+}
+
+func (m *MongoDBRepository) FindRewardRecordByReadingId(ctx context.Context, readingId string) (*entity.RewardRecord, error) {
+	var doc rewardRecordDocument
+	err := m.History.FindOne(ctx, bson.M{"reading_id": readingId}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrRewardRecordNotFound
+		}
+		return nil, err
+	}
+	return &entity.RewardRecord{
+		ReadingId:  doc.ReadingId,
+		RewardId:   doc.RewardId,
+		SensorId:   doc.Sensor.Id,
+		Latitude:   doc.Sensor.Latitude,
+		Longitude:  doc.Sensor.Longitude,
+		Token:      doc.Token,
+		Amount:     doc.Amount.String(),
+		Receiver:   doc.Receiver,
+		Data:       doc.Data,
+		RecordedAt: doc.RecordedAt,
+	}, nil
+}
+
+func (m *MongoDBRepository) SetHistoryRetention(ctx context.Context, retention time.Duration) error {
+	var expireAfterSeconds any = "off"
+	if retention > 0 {
+		expireAfterSeconds = int64(retention.Seconds())
+	}
+	for _, coll := range []*mongo.Collection{m.History, m.Readings} {
+		err := coll.Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: coll.Name()},
+			{Key: "expireAfterSeconds", Value: expireAfterSeconds},
+		}).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DownsampleRewardRecords aggregates the records from the start of the latest rollup on, and
+// replaces the rollups of those hours. The latest hour is rolled up again on every call until
+// it is over, so calling it often is harmless.
+func (m *MongoDBRepository) DownsampleRewardRecords(ctx context.Context) error {
+	var since time.Time
+	var latest struct {
+		Hour time.Time `bson:"hour"`
+	}
+	err := m.HistoryHourly.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.M{"hour": -1})).Decode(&latest)
+	if err == nil {
+		since = latest.Hour
+	} else if err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"recorded_at": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"sensor": "$sensor",
+				"hour":   bson.M{"$dateTrunc": bson.M{"date": "$recorded_at", "unit": "hour"}},
+			},
+			"readings":          bson.M{"$sum": 1},
+			"amount":            bson.M{"$sum": "$amount"},
+			"receivers":         bson.M{"$addToSet": "$receiver"},
+			"first_recorded_at": bson.M{"$min": "$recorded_at"},
+			"last_recorded_at":  bson.M{"$max": "$recorded_at"},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"sensor": "$_id.sensor",
+			"hour":   "$_id.hour",
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           m.HistoryHourly.Name(),
+			"on":             "_id",
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}}},
+	}
+
+	cursor, err := m.History.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+func isNamespaceExists(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(codeNamespaceExists)
+}
@@ -2,13 +2,29 @@ package mongodb
 
 import (
 	"context"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type MongoDBRepository struct {
-	Collection *mongo.Collection
+	Collection    *mongo.Collection
+	Outbox        *mongo.Collection
+	History       *mongo.Collection // time series of every reward record
+	Current       *mongo.Collection // view of the latest record of every sensor
+	HistoryHourly *mongo.Collection // hourly rollups of the history
+	Readings      *mongo.Collection // time series of the values of every reading
+	Reputation    *mongo.Collection // score and check state of every sensor
+	Devices       *mongo.Collection // public key of every registered sensor
+	Sensors       *mongo.Collection // receiver and rate of every registered sensor
+	Receivers     *mongo.Collection // wallets rewards are allowed or denied to
+	ReceiverDays  *mongo.Collection // amount reserved for every receiver per UTC day
+	GasDays       *mongo.Collection // wei committed to gas per UTC day
+	Sequences     *mongo.Collection // last sequence number seen from every sensor
+	Quarantine    *mongo.Collection // readings held back by the signature, payout or receiver checks
 }
 
 func NewMongoDBRepository(ctx context.Context, conn, database, collection string) (*MongoDBRepository, error) {
@@ -22,13 +38,87 @@ func NewMongoDBRepository(ctx context.Context, conn, database, collection string
 		return nil, err
 	}
 
-	coll := client.Database(database).Collection(collection)
+	db := client.Database(database)
+	repo := &MongoDBRepository{
+		Collection:    db.Collection(collection),
+		Outbox:        db.Collection(collection + "_outbox"),
+		History:       db.Collection(collection + "_history"),
+		Current:       db.Collection(collection + "_current"),
+		HistoryHourly: db.Collection(collection + "_history_hourly"),
+		Readings:      db.Collection(collection + "_readings"),
+		Reputation:    db.Collection(collection + "_reputation"),
+		Devices:       db.Collection(collection + "_devices"),
+		Sensors:       db.Collection(collection + "_sensors"),
+		Receivers:     db.Collection(collection + "_receivers"),
+		ReceiverDays:  db.Collection(collection + "_receiver_days"),
+		GasDays:       db.Collection(collection + "_gas_days"),
+		Sequences:     db.Collection(collection + "_sequences"),
+		Quarantine:    db.Collection(collection + "_quarantine"),
+	}
+
+	if err := repo.createIndexes(ctx); err != nil {
+		return nil, err
+	}
 
-	return &MongoDBRepository{
-		Collection: coll,
-	}, nil
+	if err := repo.createHistory(ctx); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (m *MongoDBRepository) createIndexes(ctx context.Context) error {
+	_, err := m.Outbox.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "claimed_until", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "reward_id", Value: 1}}},
+		{Keys: bson.D{{Key: "events.published_at", Value: 1}}},
+		// Settlement queries, newest first.
+		{Keys: bson.D{{Key: "receiver", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "sensor_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "latitude", Value: 1}, {Key: "longitude", Value: 1}, {Key: "_id", Value: -1}}},
+		// Every reading is paid at most once, whatever the Kafka delivery does.
+		{
+			Keys: bson.D{{Key: "reading_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"reading_id": bson.M{"$type": "string"}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.Quarantine.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sensor_id", Value: 1}, {Key: "received_at", Value: -1}}},
+		// Readings due to be processed again.
+		{Keys: bson.D{{Key: "release_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only the current day counts against the caps. Past days are kept a week, so that a
+	// mint that fails late can still give its amount back.
+	_, err = m.ReceiverDays.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "day", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32((8 * 24 * time.Hour).Seconds())),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The same goes for the gas budget, settled when a receipt arrives.
+	_, err = m.GasDays.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "day", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32((8 * 24 * time.Hour).Seconds())),
+	})
+	return err
+}
+
+func (m *MongoDBRepository) Ping(ctx context.Context) error {
+	return m.Collection.Database().Client().Ping(ctx, readpref.Primary())
 }
 
 func (m *MongoDBRepository) Close() error {
 	return m.Collection.Database().Client().Disconnect(context.Background())
-}
\ No newline at end of file
+}
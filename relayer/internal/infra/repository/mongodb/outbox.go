@@ -0,0 +1,296 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (s *MongoDBRepository) CreateOutboxEntry(ctx context.Context, input *entity.OutboxEntry) (*entity.OutboxEntry, error) {
+	res, err := s.Outbox.InsertOne(ctx, input)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, entity.ErrDuplicateReading
+		}
+		return nil, err
+	}
+
+	id, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, mongo.ErrNilValue
+	}
+
+	entry := *input
+	entry.Id = id
+	return &entry, nil
+}
+
+// ClaimOutboxEntry atomically takes the oldest pending entry whose claim is free or expired.
+func (s *MongoDBRepository) ClaimOutboxEntry(ctx context.Context, owner string, lease time.Duration) (*entity.OutboxEntry, error) {
+	now := time.Now()
+	filter := bson.M{
+		"state":         entity.OutboxStatePendingMint,
+		"claimed_until": bson.M{"$lt": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"claimed_by":    owner,
+			"claimed_until": now.Add(lease),
+			"updated_at":    now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var entry entity.OutboxEntry
+	err := s.Outbox.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrOutboxEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *MongoDBRepository) FindOutboxEntryByReadingId(ctx context.Context, readingId string) (*entity.OutboxEntry, error) {
+	var entry entity.OutboxEntry
+	err := s.Outbox.FindOne(ctx, bson.M{"reading_id": readingId}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrOutboxEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *MongoDBRepository) FindOutboxEntriesByState(ctx context.Context, state entity.OutboxState) ([]*entity.OutboxEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := s.Outbox.Find(ctx, bson.M{"state": state}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*entity.OutboxEntry
+	for cursor.Next(ctx) {
+		var entry entity.OutboxEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// UpdateOutboxEntry saves the state of an entry and appends the given events to it in the
+// same write. Stored events are never rewritten, so their publication marks are kept. It
+// fails with ErrOutboxEntryChanged when the entry no longer holds guard: a pending entry
+// must still be under the same claim, and a submitted one hold the same transaction.
+func (s *MongoDBRepository) UpdateOutboxEntry(ctx context.Context, entry *entity.OutboxEntry, guard entity.OutboxGuard, events ...entity.RewardEvent) error {
+	filter := bson.M{"_id": entry.Id, "state": guard.State}
+	switch guard.State {
+	case entity.OutboxStatePendingMint:
+		filter["claimed_by"] = guard.ClaimedBy
+		filter["claimed_until"] = guard.ClaimedUntil
+	case entity.OutboxStateSubmitted:
+		filter["tx_hash"] = guard.TxHash
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"state":         entry.State,
+			"attempts":      entry.Attempts,
+			"last_error":    entry.LastError,
+			"tx_hash":       entry.TxHash,
+			"raw_tx":        entry.RawTx,
+			"submitted_at":  entry.SubmittedAt,
+			"replaced_txs":  entry.ReplacedTxs,
+			"gas_reserved":  entry.GasReserved,
+			"claimed_by":    entry.ClaimedBy,
+			"claimed_until": entry.ClaimedUntil,
+			"updated_at":    entry.UpdatedAt,
+		},
+	}
+	if len(events) > 0 {
+		update["$push"] = bson.M{"events": bson.M{"$each": events}}
+	}
+
+	result, err := s.Outbox.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return entity.ErrOutboxEntryChanged
+	}
+
+	return nil
+}
+
+// ReplaceOutboxTransaction stores the replacement of the submitted transaction previous,
+// keeping previous among the replaced ones. It fails with ErrOutboxEntryChanged when the
+// entry no longer holds previous, so that only one replacement is ever broadcast.
+func (s *MongoDBRepository) ReplaceOutboxTransaction(ctx context.Context, entry *entity.OutboxEntry, previous string) error {
+	filter := bson.M{
+		"_id":     entry.Id,
+		"state":   entity.OutboxStateSubmitted,
+		"tx_hash": previous,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"tx_hash":      entry.TxHash,
+			"raw_tx":       entry.RawTx,
+			"submitted_at": entry.SubmittedAt,
+			"last_error":   entry.LastError,
+			"gas_reserved": entry.GasReserved,
+			"updated_at":   entry.UpdatedAt,
+		},
+		"$push": bson.M{"replaced_txs": previous},
+	}
+
+	result, err := s.Outbox.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return entity.ErrOutboxEntryChanged
+	}
+
+	return nil
+}
+
+// FindOutboxEntriesWithUnpublishedEvents returns the entries that have at least one event
+// not published yet, least recently updated first.
+func (s *MongoDBRepository) FindOutboxEntriesWithUnpublishedEvents(ctx context.Context, limit int64) ([]*entity.OutboxEntry, error) {
+	filter := bson.M{"events": bson.M{"$elemMatch": bson.M{"published_at": nil}}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}}).
+		SetLimit(limit)
+	cursor, err := s.Outbox.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*entity.OutboxEntry
+	for cursor.Next(ctx) {
+		var entry entity.OutboxEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *MongoDBRepository) MarkRewardEventPublished(ctx context.Context, entryId primitive.ObjectID, eventId string, publishedAt time.Time) error {
+	filter := bson.M{"_id": entryId}
+	update := bson.M{"$set": bson.M{"events.$[event].published_at": publishedAt}}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"event.id": eventId}},
+	})
+
+	result, err := s.Outbox.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return entity.ErrOutboxEntryNotFound
+	}
+
+	return nil
+}
+
+func (s *MongoDBRepository) RecordRewardEventFailure(ctx context.Context, entryId primitive.ObjectID, eventId string, reason string) error {
+	filter := bson.M{"_id": entryId}
+	update := bson.M{
+		"$inc": bson.M{"events.$[event].publish_failures": 1},
+		"$set": bson.M{"events.$[event].publish_error": reason},
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"event.id": eventId}},
+	})
+
+	result, err := s.Outbox.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return entity.ErrOutboxEntryNotFound
+	}
+
+	return nil
+}
+
+func (s *MongoDBRepository) CountOutboxEntriesByState(ctx context.Context) (map[entity.OutboxState]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$state", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := s.Outbox.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := make(map[entity.OutboxState]int64)
+	for cursor.Next(ctx) {
+		var group struct {
+			State entity.OutboxState `bson:"_id"`
+			Count int64              `bson:"count"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		counts[group.State] = group.Count
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CompactOutboxEntries drops the signed transaction and the trace context of the confirmed
+// and failed entries last updated before the given time whose events were all published.
+// The entries themselves are kept, since they are what prevents a reading from being paid
+// twice.
+func (s *MongoDBRepository) CompactOutboxEntries(ctx context.Context, before time.Time) (int64, error) {
+	filter := bson.M{
+		"state":      bson.M{"$in": bson.A{entity.OutboxStateConfirmed, entity.OutboxStateFailed}},
+		"updated_at": bson.M{"$lt": before},
+		"events":     bson.M{"$not": bson.M{"$elemMatch": bson.M{"published_at": nil}}},
+		"$or": bson.A{
+			bson.M{"raw_tx": bson.M{"$exists": true}},
+			bson.M{"trace": bson.M{"$exists": true}},
+		},
+	}
+	update := bson.M{"$unset": bson.M{"raw_tx": "", "trace": "", "claimed_by": ""}}
+
+	result, err := s.Outbox.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,96 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func TestUpdateOutboxEntry(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	claimedUntil := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	entry := &entity.OutboxEntry{
+		Id:           primitive.NewObjectID(),
+		State:        entity.OutboxStatePendingMint,
+		ClaimedBy:    "relayer-1",
+		ClaimedUntil: claimedUntil,
+	}
+
+	mt.Run("a pending entry is saved under its claim", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Outbox: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		guard := entry.Guard()
+		submitted := *entry
+		submitted.State = entity.OutboxStateSubmitted
+		submitted.TxHash = "0x01"
+		require.NoError(mt, repo.UpdateOutboxEntry(context.Background(), &submitted, guard))
+
+		filter := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(mt, string(entity.OutboxStatePendingMint), filter.Lookup("state").StringValue())
+		assert.Equal(mt, "relayer-1", filter.Lookup("claimed_by").StringValue())
+		assert.Equal(mt, claimedUntil.UnixMilli(), filter.Lookup("claimed_until").Time().UnixMilli())
+		_, err := filter.LookupErr("tx_hash")
+		assert.Error(mt, err)
+	})
+
+	mt.Run("a submitted entry is saved while it holds its transaction", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Outbox: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		submitted := *entry
+		submitted.State = entity.OutboxStateSubmitted
+		submitted.TxHash = "0x01"
+		guard := submitted.Guard()
+		submitted.State = entity.OutboxStateConfirmed
+		require.NoError(mt, repo.UpdateOutboxEntry(context.Background(), &submitted, guard))
+
+		filter := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(mt, string(entity.OutboxStateSubmitted), filter.Lookup("state").StringValue())
+		assert.Equal(mt, "0x01", filter.Lookup("tx_hash").StringValue())
+		_, err := filter.LookupErr("claimed_by")
+		assert.Error(mt, err)
+	})
+
+	mt.Run("changed by another dispatcher", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Outbox: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+
+		err := repo.UpdateOutboxEntry(context.Background(), entry, entry.Guard())
+		assert.ErrorIs(mt, err, entity.ErrOutboxEntryChanged)
+	})
+}
+
+func TestRecordRewardEventFailure(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("counts the failure on the event", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Outbox: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		require.NoError(mt, repo.RecordRewardEventFailure(context.Background(), primitive.NewObjectID(), "event-1", "topic refused"))
+
+		command := mt.GetStartedEvent().Command
+		update := command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(mt, int32(1), update.Lookup("u", "$inc", "events.$[event].publish_failures").Int32())
+		assert.Equal(mt, "topic refused", update.Lookup("u", "$set", "events.$[event].publish_error").StringValue())
+		assert.Equal(mt, "event-1", update.Lookup("arrayFilters").Array().Index(0).Value().Document().Lookup("event.id").StringValue())
+	})
+
+	mt.Run("entry not found", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Outbox: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+
+		err := repo.RecordRewardEventFailure(context.Background(), primitive.NewObjectID(), "event-1", "topic refused")
+		assert.ErrorIs(mt, err, entity.ErrOutboxEntryNotFound)
+	})
+}
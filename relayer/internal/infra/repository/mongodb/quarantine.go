@@ -0,0 +1,118 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// QuarantineReading inserts a reading unless one with its id is already quarantined, so that a
+// redelivery neither fails nor overwrites what was first received. The check, reason and
+// release time are replaced, for a released reading that is held again.
+func (m *MongoDBRepository) QuarantineReading(ctx context.Context, reading *entity.QuarantinedReading) error {
+	update := bson.M{
+		"$set": bson.M{
+			"check":    reading.Check,
+			"reason":   reading.Reason,
+			"receiver": reading.Receiver,
+			"amount":   reading.Amount,
+		},
+		"$setOnInsert": bson.M{
+			"sensor_id":   reading.SensorId,
+			"topic":       reading.Topic,
+			"payload":     reading.Payload,
+			"received_at": reading.ReceivedAt,
+		},
+	}
+	if reading.ReleaseAt.IsZero() {
+		update["$unset"] = bson.M{"release_at": ""}
+	} else {
+		update["$set"].(bson.M)["release_at"] = reading.ReleaseAt
+	}
+
+	_, err := m.Quarantine.UpdateOne(ctx,
+		bson.M{"_id": reading.ReadingId},
+		update,
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (m *MongoDBRepository) FindQuarantinedReading(ctx context.Context, readingId string) (*entity.QuarantinedReading, error) {
+	var reading entity.QuarantinedReading
+	err := m.Quarantine.FindOne(ctx, bson.M{"_id": readingId}).Decode(&reading)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrQuarantinedReadingNotFound
+		}
+		return nil, err
+	}
+	return &reading, nil
+}
+
+// FindQuarantinedReadings returns the latest quarantined readings, of every sensor when
+// sensorId is empty.
+func (m *MongoDBRepository) FindQuarantinedReadings(ctx context.Context, sensorId string, limit int64) ([]*entity.QuarantinedReading, error) {
+	filter := bson.M{}
+	if sensorId != "" {
+		filter["sensor_id"] = sensorId
+	}
+
+	cursor, err := m.Quarantine.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "received_at", Value: -1}}).
+		SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	readings := []*entity.QuarantinedReading{}
+	if err := cursor.All(ctx, &readings); err != nil {
+		return nil, err
+	}
+	return readings, nil
+}
+
+func (m *MongoDBRepository) ReleaseQuarantinedReading(ctx context.Context, readingId string, at time.Time) (*entity.QuarantinedReading, error) {
+	var reading entity.QuarantinedReading
+	err := m.Quarantine.FindOneAndUpdate(ctx,
+		bson.M{"_id": readingId},
+		bson.M{"$set": bson.M{"release_at": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&reading)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrQuarantinedReadingNotFound
+		}
+		return nil, err
+	}
+	return &reading, nil
+}
+
+// ClaimQuarantinedReading returns the reading that has been due the longest.
+func (m *MongoDBRepository) ClaimQuarantinedReading(ctx context.Context, now time.Time, lease time.Duration) (*entity.QuarantinedReading, error) {
+	var reading entity.QuarantinedReading
+	err := m.Quarantine.FindOneAndUpdate(ctx,
+		bson.M{"release_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"release_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "release_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&reading)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrQuarantinedReadingNotFound
+		}
+		return nil, err
+	}
+	return &reading, nil
+}
+
+func (m *MongoDBRepository) DeleteQuarantinedReading(ctx context.Context, readingId string) error {
+	_, err := m.Quarantine.DeleteOne(ctx, bson.M{"_id": readingId})
+	return err
+}
@@ -0,0 +1,301 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/aqi"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxHeatmapCells bounds the cells of a heatmap.
+const maxHeatmapCells = 10000
+
+// Readings share the layout of the history: a time series with the sensor as metadata.
+type readingDocument struct {
+	Sensor     rewardRecordSensor  `bson:"sensor"`
+	RecordedAt time.Time           `bson:"recorded_at"`
+	ReadingId  string              `bson:"reading_id"`
+	Values     map[string]float64  `bson:"values"`
+	AQI        *readingAQIDocument `bson:"aqi,omitempty"`
+}
+
+type readingAQIDocument struct {
+	Standard   string                `bson:"standard"`
+	Index      int                   `bson:"index"`
+	Category   string                `bson:"category"`
+	Dominant   aqi.Pollutant         `bson:"dominant"`
+	SubIndexes map[aqi.Pollutant]int `bson:"sub_indexes"`
+}
+
+type readingWindowDocument struct {
+	Sensor     rewardRecordSensor               `bson:"sensor"`
+	Start      time.Time                        `bson:"start"`
+	Readings   int64                            `bson:"readings"`
+	Parameters map[string]entity.ParameterStats `bson:"parameters"`
+}
+
+func (m *MongoDBRepository) CreateReading(ctx context.Context, reading *entity.Reading) error {
+	_, err := m.Readings.InsertOne(ctx, readingDocument{
+		Sensor: rewardRecordSensor{
+			Id:        reading.SensorId,
+			Latitude:  reading.Latitude,
+			Longitude: reading.Longitude,
+		},
+		RecordedAt: reading.RecordedAt,
+		ReadingId:  reading.ReadingId,
+		Values:     reading.Values,
+		AQI:        newReadingAQIDocument(reading.AQI),
+	})
+	return err
+}
+
+func (m *MongoDBRepository) FindReadingByReadingId(ctx context.Context, readingId string) (*entity.Reading, error) {
+	var doc readingDocument
+	err := m.Readings.FindOne(ctx, bson.M{"reading_id": readingId}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrReadingNotFound
+		}
+		return nil, err
+	}
+	return doc.reading(), nil
+}
+
+func (d *readingDocument) reading() *entity.Reading {
+	return &entity.Reading{
+		ReadingId:  d.ReadingId,
+		SensorId:   d.Sensor.Id,
+		Latitude:   d.Sensor.Latitude,
+		Longitude:  d.Sensor.Longitude,
+		Values:     d.Values,
+		AQI:        d.AQI.result(),
+		RecordedAt: d.RecordedAt,
+	}
+}
+
+func newReadingAQIDocument(result *aqi.Result) *readingAQIDocument {
+	if result == nil {
+		return nil
+	}
+	return &readingAQIDocument{
+		Standard:   result.Standard,
+		Index:      result.Index,
+		Category:   result.Category,
+		Dominant:   result.Dominant,
+		SubIndexes: result.SubIndexes,
+	}
+}
+
+func (d *readingAQIDocument) result() *aqi.Result {
+	if d == nil {
+		return nil
+	}
+	return &aqi.Result{
+		Standard:   d.Standard,
+		Index:      d.Index,
+		Category:   d.Category,
+		Dominant:   d.Dominant,
+		SubIndexes: d.SubIndexes,
+	}
+}
+
+func (m *MongoDBRepository) FindLatestReadings(ctx context.Context, area repository.ReadingArea) ([]*entity.Reading, error) {
+	match := bson.M{
+		"sensor.latitude":  bson.M{"$gte": area.South, "$lte": area.North},
+		"sensor.longitude": bson.M{"$gte": area.West, "$lte": area.East},
+		"recorded_at":      bson.M{"$gte": area.Since},
+	}
+	if area.ExcludeSensorId != "" {
+		match["sensor.id"] = bson.M{"$ne": area.ExcludeSensorId}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "sensor", Value: 1}, {Key: "recorded_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$sensor",
+			"reading": bson.M{"$first": "$$ROOT"},
+		}}},
+		{{Key: "$replaceWith", Value: "$reading"}},
+	}
+
+	cursor, err := m.Readings.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var readings []*entity.Reading
+	for cursor.Next(ctx) {
+		var doc readingDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		readings = append(readings, doc.reading())
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return readings, nil
+}
+
+func (m *MongoDBRepository) AggregateReadings(ctx context.Context, filter repository.ReadingFilter, window time.Duration) ([]*entity.ReadingWindow, error) {
+	var start any = filter.From
+	if window > 0 {
+		start = bson.M{"$dateTrunc": bson.M{
+			"date":    "$recorded_at",
+			"unit":    "millisecond",
+			"binSize": window.Milliseconds(),
+		}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: readingFilter(filter)}},
+		{{Key: "$project", Value: bson.M{
+			"sensor": 1,
+			"start":  start,
+			"values": bson.M{"$objectToArray": "$values"},
+		}}},
+		{{Key: "$unwind", Value: "$values"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"sensor": "$sensor", "start": "$start", "parameter": "$values.k"},
+			"min":   bson.M{"$min": "$values.v"},
+			"max":   bson.M{"$max": "$values.v"},
+			"avg":   bson.M{"$avg": "$values.v"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"sensor": "$_id.sensor", "start": "$_id.start"},
+			"readings": bson.M{"$max": "$count"},
+			"parameters": bson.M{"$push": bson.M{
+				"k": "$_id.parameter",
+				"v": bson.M{"min": "$min", "max": "$max", "avg": "$avg", "count": "$count"},
+			}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":        0,
+			"sensor":     "$_id.sensor",
+			"start":      "$_id.start",
+			"readings":   1,
+			"parameters": bson.M{"$arrayToObject": "$parameters"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "start", Value: 1}, {Key: "sensor.id", Value: 1}}}},
+	}
+
+	cursor, err := m.Readings.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var windows []*entity.ReadingWindow
+	for cursor.Next(ctx) {
+		var doc readingWindowDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		windows = append(windows, &entity.ReadingWindow{
+			SensorId:   doc.Sensor.Id,
+			Latitude:   doc.Sensor.Latitude,
+			Longitude:  doc.Sensor.Longitude,
+			Start:      doc.Start,
+			Readings:   doc.Readings,
+			Parameters: doc.Parameters,
+		})
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return windows, nil
+}
+
+// ReadingHeatmap expects a parameter that was validated as a plain field name, since it is
+// used in field paths.
+func (m *MongoDBRepository) ReadingHeatmap(ctx context.Context, filter repository.ReadingFilter, parameter string, cellSize float64) ([]*entity.HeatmapCell, error) {
+	value := "$values." + parameter
+	match := readingFilter(filter)
+	match["values."+parameter] = bson.M{"$type": "number"}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"latitude":  bson.M{"$floor": bson.M{"$divide": bson.A{"$sensor.latitude", cellSize}}},
+				"longitude": bson.M{"$floor": bson.M{"$divide": bson.A{"$sensor.longitude", cellSize}}},
+			},
+			"min":      bson.M{"$min": value},
+			"max":      bson.M{"$max": value},
+			"avg":      bson.M{"$avg": value},
+			"readings": bson.M{"$sum": 1},
+			"sensors":  bson.M{"$addToSet": "$sensor"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"latitude":  bson.M{"$multiply": bson.A{"$_id.latitude", cellSize}},
+			"longitude": bson.M{"$multiply": bson.A{"$_id.longitude", cellSize}},
+			"min":       1,
+			"max":       1,
+			"avg":       1,
+			"readings":  1,
+			"sensors":   bson.M{"$size": "$sensors"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "latitude", Value: 1}, {Key: "longitude", Value: 1}}}},
+		{{Key: "$limit", Value: maxHeatmapCells}},
+	}
+
+	cursor, err := m.Readings.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cells []*entity.HeatmapCell
+	for cursor.Next(ctx) {
+		var cell struct {
+			Latitude  float64 `bson:"latitude"`
+			Longitude float64 `bson:"longitude"`
+			Min       float64 `bson:"min"`
+			Max       float64 `bson:"max"`
+			Avg       float64 `bson:"avg"`
+			Readings  int64   `bson:"readings"`
+			Sensors   int     `bson:"sensors"`
+		}
+		if err := cursor.Decode(&cell); err != nil {
+			return nil, err
+		}
+		c := entity.HeatmapCell(cell)
+		cells = append(cells, &c)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return cells, nil
+}
+
+func readingFilter(filter repository.ReadingFilter) bson.M {
+	match := bson.M{}
+	if filter.SensorId != "" {
+		match["sensor.id"] = filter.SensorId
+	}
+	recordedAt := bson.M{}
+	if !filter.From.IsZero() {
+		recordedAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		recordedAt["$lt"] = filter.To
+	}
+	if len(recordedAt) > 0 {
+		match["recorded_at"] = recordedAt
+	}
+	return match
+}
@@ -0,0 +1,111 @@
+package mongodb
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveReceiver upserts a receiver, keeping the time it was first registered.
+func (m *MongoDBRepository) SaveReceiver(ctx context.Context, receiver *entity.Receiver) (*entity.Receiver, error) {
+	var saved entity.Receiver
+	err := m.Receivers.FindOneAndUpdate(ctx,
+		bson.M{"_id": receiver.Address},
+		bson.M{
+			"$set": bson.M{
+				"status":     receiver.Status,
+				"ens_name":   receiver.ENSName,
+				"daily_cap":  receiver.DailyCap,
+				"reason":     receiver.Reason,
+				"updated_at": receiver.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{"created_at": receiver.CreatedAt},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (m *MongoDBRepository) FindReceiver(ctx context.Context, address string) (*entity.Receiver, error) {
+	var receiver entity.Receiver
+	err := m.Receivers.FindOne(ctx, bson.M{"_id": address}).Decode(&receiver)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrReceiverNotFound
+		}
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (m *MongoDBRepository) DeleteReceiver(ctx context.Context, address string) error {
+	res, err := m.Receivers.DeleteOne(ctx, bson.M{"_id": address})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return entity.ErrReceiverNotFound
+	}
+	return nil
+}
+
+// ReserveReceiverAmount adds amount to the counter of a receiver for a UTC day, in a single
+// update that only matches while the total plus amount is within limit. The first reservation
+// of a day inserts the counter; once the counter is full, the upsert collides with it instead.
+func (m *MongoDBRepository) ReserveReceiverAmount(ctx context.Context, receiver string, day time.Time, amount, limit *big.Int) error {
+	room := new(big.Int).Sub(limit, amount)
+	if room.Sign() < 0 {
+		return entity.ErrDailyCapReached
+	}
+	inc, err := primitive.ParseDecimal128(amount.String())
+	if err != nil {
+		return err
+	}
+	maxTotal, err := primitive.ParseDecimal128(room.String())
+	if err != nil {
+		return err
+	}
+
+	day = day.UTC().Truncate(24 * time.Hour)
+	_, err = m.ReceiverDays.UpdateOne(ctx,
+		bson.M{"_id": receiverDayId(receiver, day), "total": bson.M{"$lte": maxTotal}},
+		bson.M{
+			"$inc":         bson.M{"total": inc},
+			"$set":         bson.M{"updated_at": time.Now()},
+			"$setOnInsert": bson.M{"receiver": receiver, "day": day},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.ErrDailyCapReached
+	}
+	return err
+}
+
+func (m *MongoDBRepository) ReleaseReceiverAmount(ctx context.Context, receiver string, day time.Time, amount *big.Int) error {
+	dec, err := primitive.ParseDecimal128(new(big.Int).Neg(amount).String())
+	if err != nil {
+		return err
+	}
+	_, err = m.ReceiverDays.UpdateOne(ctx,
+		bson.M{"_id": receiverDayId(receiver, day.UTC().Truncate(24*time.Hour))},
+		bson.M{
+			"$inc": bson.M{"total": dec},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
+func receiverDayId(receiver string, day time.Time) string {
+	return receiver + "|" + day.Format(time.DateOnly)
+}
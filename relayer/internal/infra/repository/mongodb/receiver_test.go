@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func TestReserveReceiverAmount(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	receiver := "0x5AEDA56215b167893e80B4fE645BA6d5Bab767DE"
+	at := time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC)
+
+	mt.Run("reserves with a conditional upsert", func(mt *mtest.T) {
+		repo := &MongoDBRepository{ReceiverDays: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		require.NoError(mt, repo.ReserveReceiverAmount(context.Background(), receiver, at, big.NewInt(300), big.NewInt(1000)))
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(mt, update.Lookup("upsert").Boolean())
+
+		filter := update.Lookup("q").Document()
+		assert.Equal(mt, receiver+"|2026-03-14", filter.Lookup("_id").StringValue())
+		// total + 300 <= 1000
+		assert.Equal(mt, "700", filter.Lookup("total", "$lte").Decimal128().String())
+		assert.Equal(mt, "300", update.Lookup("u", "$inc", "total").Decimal128().String())
+	})
+
+	mt.Run("cap reached when the upsert collides", func(mt *mtest.T) {
+		repo := &MongoDBRepository{ReceiverDays: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "E11000 duplicate key error",
+		}))
+
+		err := repo.ReserveReceiverAmount(context.Background(), receiver, at, big.NewInt(300), big.NewInt(1000))
+		assert.ErrorIs(mt, err, entity.ErrDailyCapReached)
+	})
+
+	mt.Run("amount above the cap", func(mt *mtest.T) {
+		repo := &MongoDBRepository{ReceiverDays: mt.Coll}
+
+		err := repo.ReserveReceiverAmount(context.Background(), receiver, at, big.NewInt(1001), big.NewInt(1000))
+		assert.ErrorIs(mt, err, entity.ErrDailyCapReached)
+	})
+
+	mt.Run("release", func(mt *mtest.T) {
+		repo := &MongoDBRepository{ReceiverDays: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		require.NoError(mt, repo.ReleaseReceiverAmount(context.Background(), receiver, at, big.NewInt(300)))
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(mt, receiver+"|2026-03-14", update.Lookup("q", "_id").StringValue())
+		assert.Equal(mt, "-300", update.Lookup("u", "$inc", "total").Decimal128().String())
+	})
+}
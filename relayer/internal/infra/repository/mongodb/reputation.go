@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (m *MongoDBRepository) FindSensorReputation(ctx context.Context, sensorId string) (*entity.SensorReputation, error) {
+	var reputation entity.SensorReputation
+	err := m.Reputation.FindOne(ctx, bson.M{"_id": sensorId}).Decode(&reputation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrReputationNotFound
+		}
+		return nil, err
+	}
+	return &reputation, nil
+}
+
+// SaveSensorReputation inserts a new reputation, or replaces the stored one if its version
+// is still the one the reputation was read with.
+func (m *MongoDBRepository) SaveSensorReputation(ctx context.Context, reputation *entity.SensorReputation) error {
+	saved := *reputation
+	saved.Version++
+
+	if reputation.Version == 0 {
+		if _, err := m.Reputation.InsertOne(ctx, saved); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return entity.ErrReputationConflict
+			}
+			return err
+		}
+	} else {
+		res, err := m.Reputation.ReplaceOne(ctx, bson.M{"_id": reputation.SensorId, "version": reputation.Version}, saved)
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount == 0 {
+			return entity.ErrReputationConflict
+		}
+	}
+
+	reputation.Version = saved.Version
+	return nil
+}
@@ -0,0 +1,50 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveSensor upserts a sensor, keeping the time it was first registered.
+func (m *MongoDBRepository) SaveSensor(ctx context.Context, sensor *entity.Sensor) (*entity.Sensor, error) {
+	var saved entity.Sensor
+	err := m.Sensors.FindOneAndUpdate(ctx,
+		bson.M{"_id": sensor.SensorId},
+		bson.M{
+			"$set":         bson.M{"receiver": sensor.Receiver, "rate": sensor.Rate, "updated_at": sensor.UpdatedAt},
+			"$setOnInsert": bson.M{"created_at": sensor.CreatedAt},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (m *MongoDBRepository) FindSensor(ctx context.Context, sensorId string) (*entity.Sensor, error) {
+	var sensor entity.Sensor
+	err := m.Sensors.FindOne(ctx, bson.M{"_id": sensorId}).Decode(&sensor)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrSensorNotFound
+		}
+		return nil, err
+	}
+	return &sensor, nil
+}
+
+func (m *MongoDBRepository) DeleteSensor(ctx context.Context, sensorId string) error {
+	res, err := m.Sensors.DeleteOne(ctx, bson.M{"_id": sensorId})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return entity.ErrSensorNotFound
+	}
+	return nil
+}
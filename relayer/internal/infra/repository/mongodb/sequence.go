@@ -0,0 +1,36 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AdvanceSensorSequence stores the last reading of a sensor if its sequence number is above
+// the stored one, or equal to it for the same reading. Otherwise the upsert collides with the
+// stored document and the reading is stale.
+func (m *MongoDBRepository) AdvanceSensorSequence(ctx context.Context, sequence *entity.SensorSequence) error {
+	_, err := m.Sequences.UpdateOne(ctx,
+		bson.M{
+			"_id": sequence.SensorId,
+			"$or": bson.A{
+				bson.M{"sequence": bson.M{"$lt": sequence.Sequence}},
+				bson.M{"sequence": sequence.Sequence, "reading_id": sequence.ReadingId},
+			},
+		},
+		bson.M{"$set": bson.M{
+			"sequence":   sequence.Sequence,
+			"reading_id": sequence.ReadingId,
+			"emitted_at": sequence.EmittedAt,
+			"updated_at": sequence.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.ErrStaleSequence
+	}
+	return err
+}
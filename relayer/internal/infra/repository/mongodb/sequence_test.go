@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func TestAdvanceSensorSequence(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	sequence := &entity.SensorSequence{
+		SensorId:  "sensor-1",
+		Sequence:  7,
+		ReadingId: "reading-7",
+		EmittedAt: time.Unix(1700000000, 0).UTC(),
+		UpdatedAt: time.Unix(1700000005, 0).UTC(),
+	}
+
+	mt.Run("advances with a conditional upsert", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Sequences: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		require.NoError(mt, repo.AdvanceSensorSequence(context.Background(), sequence))
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(mt, update.Lookup("upsert").Boolean())
+
+		filter := update.Lookup("q").Document()
+		assert.Equal(mt, "sensor-1", filter.Lookup("_id").StringValue())
+		or, err := filter.Lookup("$or").Array().Values()
+		require.NoError(mt, err)
+		require.Len(mt, or, 2)
+		assert.Equal(mt, int64(7), or[0].Document().Lookup("sequence", "$lt").Int64())
+		assert.Equal(mt, int64(7), or[1].Document().Lookup("sequence").Int64())
+		assert.Equal(mt, "reading-7", or[1].Document().Lookup("reading_id").StringValue())
+
+		set := update.Lookup("u", "$set").Document()
+		assert.Equal(mt, int64(7), set.Lookup("sequence").Int64())
+		assert.Equal(mt, "reading-7", set.Lookup("reading_id").StringValue())
+	})
+
+	mt.Run("stale when the upsert collides", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Sequences: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "E11000 duplicate key error",
+		}))
+
+		err := repo.AdvanceSensorSequence(context.Background(), sequence)
+		assert.ErrorIs(mt, err, entity.ErrStaleSequence)
+	})
+
+	mt.Run("other write errors are returned", func(mt *mtest.T) {
+		repo := &MongoDBRepository{Sequences: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    2,
+			Message: "bad value",
+		}))
+
+		err := repo.AdvanceSensorSequence(context.Background(), sequence)
+		require.Error(mt, err)
+		assert.NotErrorIs(mt, err, entity.ErrStaleSequence)
+	})
+}
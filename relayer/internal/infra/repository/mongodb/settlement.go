@@ -0,0 +1,167 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Settlements are read from the outbox, newest first.
+func (s *MongoDBRepository) FindSettlements(ctx context.Context, filter repository.SettlementFilter) ([]*entity.Settlement, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: settlementFilter(filter)}},
+		{{Key: "$sort", Value: bson.M{"_id": -1}}},
+	}
+	if filter.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: filter.Limit}})
+	}
+	pipeline = append(pipeline, s.settlementLookup()...)
+	pipeline = append(pipeline, bson.D{{Key: "$unset", Value: bson.A{"events"}}})
+	return s.aggregateSettlements(ctx, pipeline)
+}
+
+func (s *MongoDBRepository) FindSettlementById(ctx context.Context, id primitive.ObjectID) (*entity.Settlement, error) {
+	pipeline := append(mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": id}}},
+	}, s.settlementLookup()...)
+
+	settlements, err := s.aggregateSettlements(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	if len(settlements) == 0 {
+		return nil, entity.ErrSettlementNotFound
+	}
+	return settlements[0], nil
+}
+
+// SumSettlementsByState counts the settlements in each state and adds up their amounts.
+// The amounts are added as decimals, which are exact for up to 34 digits.
+func (s *MongoDBRepository) SumSettlementsByState(ctx context.Context, filter repository.SettlementFilter) (map[entity.OutboxState]repository.SettlementTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: settlementFilter(filter)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$state",
+			"count":  bson.M{"$sum": 1},
+			"amount": bson.M{"$sum": bson.M{"$toDecimal": "$amount"}},
+		}}},
+	}
+
+	cursor, err := s.Outbox.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	totals := make(map[entity.OutboxState]repository.SettlementTotal)
+	for cursor.Next(ctx) {
+		var group struct {
+			State  entity.OutboxState   `bson:"_id"`
+			Count  int64                `bson:"count"`
+			Amount primitive.Decimal128 `bson:"amount"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		amount, err := decimalToInt(group.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s amount: %w", group.State, err)
+		}
+		totals[group.State] = repository.SettlementTotal{Count: group.Count, Amount: amount}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+func settlementFilter(filter repository.SettlementFilter) bson.M {
+	match := bson.M{}
+	if filter.Receiver != "" {
+		match["receiver"] = filter.Receiver
+	}
+	if filter.SensorId != "" {
+		match["sensor_id"] = filter.SensorId
+	}
+	if filter.Latitude != nil && filter.Longitude != nil {
+		match["latitude"] = *filter.Latitude
+		match["longitude"] = *filter.Longitude
+	}
+	if len(filter.States) > 0 {
+		match["state"] = bson.M{"$in": filter.States}
+	}
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		match["created_at"] = createdAt
+	}
+	if !filter.Before.IsZero() {
+		match["_id"] = bson.M{"$lt": filter.Before}
+	}
+	return match
+}
+
+// settlementLookup takes the location of the entries created before they held one from the
+// reward document of that location.
+func (s *MongoDBRepository) settlementLookup() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         s.Collection.Name(),
+			"localField":   "reward_id",
+			"foreignField": "_id",
+			"as":           "reward",
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"latitude":  bson.M{"$ifNull": bson.A{"$latitude", bson.M{"$arrayElemAt": bson.A{"$reward.latitude", 0}}}},
+			"longitude": bson.M{"$ifNull": bson.A{"$longitude", bson.M{"$arrayElemAt": bson.A{"$reward.longitude", 0}}}},
+		}}},
+		{{Key: "$unset", Value: bson.A{"reward", "raw_tx", "trace", "claimed_by", "claimed_until"}}},
+	}
+}
+
+func (s *MongoDBRepository) aggregateSettlements(ctx context.Context, pipeline mongo.Pipeline) ([]*entity.Settlement, error) {
+	cursor, err := s.Outbox.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var settlements []*entity.Settlement
+	for cursor.Next(ctx) {
+		var settlement entity.Settlement
+		if err := cursor.Decode(&settlement); err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, &settlement)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return settlements, nil
+}
+
+func decimalToInt(d primitive.Decimal128) (*big.Int, error) {
+	coefficient, exponent, err := d.BigInt()
+	if err != nil {
+		return nil, err
+	}
+	if exponent < 0 {
+		return nil, fmt.Errorf("%s is not an integer", d)
+	}
+	return coefficient.Mul(coefficient, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)), nil
+}
@@ -2,19 +2,179 @@ package repository
 
 import (
 	"context"
+	"math/big"
+	"time"
 
 	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// SettlementFilter selects settlements. Zero fields do not filter.
+type SettlementFilter struct {
+	Receiver  string
+	SensorId  string
+	Latitude  *float64 // set along with Longitude
+	Longitude *float64
+	States    []entity.OutboxState
+	From      time.Time          // created at or after
+	To        time.Time          // created before
+	Before    primitive.ObjectID // page cursor: settlements with a lower id, that is, older ones
+	Limit     int64
+}
+
+type SettlementTotal struct {
+	Count  int64
+	Amount *big.Int
+}
+
 type RewardRepository interface {
-	CreateReward(ctx context.Context, reward *entity.Reward) (*entity.Reward, error)
-	FindRewardByLocation(ctx context.Context, latitude, longitude float64) (*entity.Reward, error)
-	UpdateReward(ctx context.Context, reward *entity.Reward) (*entity.Reward, error)
-	UpdateRewardTxHash(ctx context.Context, rewardId primitive.ObjectID, txHash string) error
+	FindSettlements(ctx context.Context, filter SettlementFilter) ([]*entity.Settlement, error)
+	FindSettlementById(ctx context.Context, id primitive.ObjectID) (*entity.Settlement, error)
+	SumSettlementsByState(ctx context.Context, filter SettlementFilter) (map[entity.OutboxState]SettlementTotal, error)
+}
+
+type OutboxRepository interface {
+	CreateOutboxEntry(ctx context.Context, entry *entity.OutboxEntry) (*entity.OutboxEntry, error)
+	ClaimOutboxEntry(ctx context.Context, owner string, lease time.Duration) (*entity.OutboxEntry, error)
+	FindOutboxEntryByReadingId(ctx context.Context, readingId string) (*entity.OutboxEntry, error)
+	FindOutboxEntriesByState(ctx context.Context, state entity.OutboxState) ([]*entity.OutboxEntry, error)
+	// UpdateOutboxEntry saves an entry that still holds guard, and fails with
+	// ErrOutboxEntryChanged when another dispatcher changed it since.
+	UpdateOutboxEntry(ctx context.Context, entry *entity.OutboxEntry, guard entity.OutboxGuard, events ...entity.RewardEvent) error
+	ReplaceOutboxTransaction(ctx context.Context, entry *entity.OutboxEntry, previous string) error
+	FindOutboxEntriesWithUnpublishedEvents(ctx context.Context, limit int64) ([]*entity.OutboxEntry, error)
+	MarkRewardEventPublished(ctx context.Context, entryId primitive.ObjectID, eventId string, publishedAt time.Time) error
+	// RecordRewardEventFailure counts a failed publication of an event, with its reason.
+	RecordRewardEventFailure(ctx context.Context, entryId primitive.ObjectID, eventId string, reason string) error
+	CountOutboxEntriesByState(ctx context.Context) (map[entity.OutboxState]int64, error)
+	CompactOutboxEntries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// HistoryRepository stores the reward records in a time series. The retention applies to
+// the readings as well.
+type HistoryRepository interface {
+	CreateRewardRecord(ctx context.Context, record *entity.RewardRecord) error
+	FindRewardRecordByReadingId(ctx context.Context, readingId string) (*entity.RewardRecord, error)
+	// SetHistoryRetention makes the database delete records older than retention, or keep
+	// them all when it is zero.
+	SetHistoryRetention(ctx context.Context, retention time.Duration) error
+	// DownsampleRewardRecords rolls the records up per sensor and hour, from the last rollup
+	// on. Rollups outlive the records.
+	DownsampleRewardRecords(ctx context.Context) error
+}
+
+// ReadingFilter selects readings. Zero fields do not filter.
+type ReadingFilter struct {
+	SensorId string
+	From     time.Time // recorded at or after
+	To       time.Time // recorded before
+}
+
+// ReadingArea selects the readings of the sensors in a box of coordinates, recorded at or
+// after Since, except those of ExcludeSensorId.
+type ReadingArea struct {
+	South           float64
+	West            float64
+	North           float64
+	East            float64
+	Since           time.Time
+	ExcludeSensorId string
+}
+
+type ReadingRepository interface {
+	CreateReading(ctx context.Context, reading *entity.Reading) error
+	FindReadingByReadingId(ctx context.Context, readingId string) (*entity.Reading, error)
+	// AggregateReadings summarizes the readings of every sensor per window, aligned to the
+	// epoch. A zero window summarizes the whole range, starting at filter.From.
+	AggregateReadings(ctx context.Context, filter ReadingFilter, window time.Duration) ([]*entity.ReadingWindow, error)
+	// ReadingHeatmap summarizes a parameter over a grid of cells of cellSize degrees.
+	ReadingHeatmap(ctx context.Context, filter ReadingFilter, parameter string, cellSize float64) ([]*entity.HeatmapCell, error)
+	// FindLatestReadings returns the latest reading of every sensor in an area.
+	FindLatestReadings(ctx context.Context, area ReadingArea) ([]*entity.Reading, error)
+}
+
+type ReputationRepository interface {
+	FindSensorReputation(ctx context.Context, sensorId string) (*entity.SensorReputation, error)
+	// SaveSensorReputation stores a reputation, and fails with ErrReputationConflict when
+	// it changed since it was read.
+	SaveSensorReputation(ctx context.Context, reputation *entity.SensorReputation) error
+}
+
+// DeviceRepository stores the public keys readings are verified with.
+type DeviceRepository interface {
+	// SaveDevice registers a device, or replaces the key of a registered one.
+	SaveDevice(ctx context.Context, device *entity.Device) (*entity.Device, error)
+	// CreateDevice registers a device, and fails with ErrDeviceRegistered when it is already.
+	CreateDevice(ctx context.Context, device *entity.Device) error
+	FindDevice(ctx context.Context, sensorId string) (*entity.Device, error)
+	DeleteDevice(ctx context.Context, sensorId string) error
+}
+
+// SensorRepository stores the payout of every registered sensor.
+type SensorRepository interface {
+	// SaveSensor registers a sensor, or replaces the payout of a registered one.
+	SaveSensor(ctx context.Context, sensor *entity.Sensor) (*entity.Sensor, error)
+	FindSensor(ctx context.Context, sensorId string) (*entity.Sensor, error)
+	DeleteSensor(ctx context.Context, sensorId string) error
+}
+
+// ReceiverRepository stores the wallets rewards are allowed or denied to.
+type ReceiverRepository interface {
+	// SaveReceiver registers a receiver, or replaces the policy of a registered one.
+	SaveReceiver(ctx context.Context, receiver *entity.Receiver) (*entity.Receiver, error)
+	FindReceiver(ctx context.Context, address string) (*entity.Receiver, error)
+	DeleteReceiver(ctx context.Context, address string) error
+	// ReserveReceiverAmount counts amount against what a receiver is paid on a UTC day, and
+	// fails with ErrDailyCapReached when the total would exceed limit.
+	ReserveReceiverAmount(ctx context.Context, receiver string, day time.Time, amount, limit *big.Int) error
+	// ReleaseReceiverAmount gives back an amount reserved on a day and not paid.
+	ReleaseReceiverAmount(ctx context.Context, receiver string, day time.Time, amount *big.Int) error
+}
+
+// GasRepository stores the wei committed to gas per UTC day, shared by every relayer.
+type GasRepository interface {
+	// ReserveGasSpend adds amount to the gas spent on a UTC day and returns the new total. With
+	// a positive limit, it fails with ErrGasBudgetExceeded when the total would exceed it.
+	ReserveGasSpend(ctx context.Context, day time.Time, amount, limit *big.Int) (*big.Int, error)
+	// AdjustGasSpend adds delta, which may be negative, to the gas spent on a UTC day and
+	// returns the new total.
+	AdjustGasSpend(ctx context.Context, day time.Time, delta *big.Int) (*big.Int, error)
+}
+
+type SequenceRepository interface {
+	// AdvanceSensorSequence records the last reading of a sensor, and fails with
+	// ErrStaleSequence when its sequence number is not above the recorded one. A redelivery
+	// of the recorded reading is not stale.
+	AdvanceSensorSequence(ctx context.Context, sequence *entity.SensorSequence) error
+}
+
+type QuarantineRepository interface {
+	// QuarantineReading stores a reading, once however often it is delivered. A reading held
+	// again keeps what was first received, with the new check, reason and release time.
+	QuarantineReading(ctx context.Context, reading *entity.QuarantinedReading) error
+	FindQuarantinedReading(ctx context.Context, readingId string) (*entity.QuarantinedReading, error)
+	FindQuarantinedReadings(ctx context.Context, sensorId string, limit int64) ([]*entity.QuarantinedReading, error)
+	// ReleaseQuarantinedReading makes a reading due to be processed again at the time at.
+	ReleaseQuarantinedReading(ctx context.Context, readingId string, at time.Time) (*entity.QuarantinedReading, error)
+	// ClaimQuarantinedReading returns a reading due at now, and postpones it by lease so that
+	// no one else processes it meanwhile. It fails with ErrQuarantinedReadingNotFound when
+	// none is due.
+	ClaimQuarantinedReading(ctx context.Context, now time.Time, lease time.Duration) (*entity.QuarantinedReading, error)
+	DeleteQuarantinedReading(ctx context.Context, readingId string) error
 }
 
 type Repository interface {
 	RewardRepository
+	OutboxRepository
+	HistoryRepository
+	ReadingRepository
+	ReputationRepository
+	DeviceRepository
+	SensorRepository
+	ReceiverRepository
+	GasRepository
+	SequenceRepository
+	QuarantineRepository
+	Ping(ctx context.Context) error
 	Close() error
 }
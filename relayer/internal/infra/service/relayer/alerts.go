@@ -0,0 +1,101 @@
+package relayer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/alert"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+)
+
+// alertBuffer bounds the alert events waiting for each publisher. Events past it are dropped,
+// so that a slow webhook never holds back the rewards.
+const alertBuffer = 1000
+
+// observeAQI feeds the index of a rewarded reading to the alert rules of its zone. Readings
+// of producers that do not send a sensor id are told apart by their location.
+func (s *Service) observeAQI(reading *entity.Reading) {
+	if s.alerts == nil || reading.AQI == nil {
+		return
+	}
+
+	source := reading.SensorId
+	if source == "" {
+		source = fmt.Sprintf("%g,%g", reading.Latitude, reading.Longitude)
+	}
+	events := s.alerts.Observe(alert.Sample{
+		Zone:     alert.ZoneOf(reading.Latitude, reading.Longitude, s.settings().alertZoneSize),
+		Source:   source,
+		Standard: reading.AQI.Standard,
+		Index:    reading.AQI.Index,
+		Category: reading.AQI.Category,
+		At:       time.Now().UTC(),
+	})
+
+	for _, event := range events {
+		s.metrics.alerts.WithLabelValues(event.Rule, string(event.Type)).Inc()
+		s.Logger.Warn("Air quality alert",
+			"type", event.Type,
+			"rule", event.Rule,
+			"zone", event.Zone.Id,
+			"index", event.Index,
+			"category", event.Category,
+			"since", event.Since)
+
+		for i, queue := range s.alertQueues {
+			select {
+			case queue <- event:
+			default:
+				s.metrics.alertPublishFailures.WithLabelValues("dropped").Inc()
+				s.Logger.Error("Alert buffer full, dropping alert event",
+					"publisher", s.alertPublishers[i].Name(),
+					"event_id", event.Id,
+					"rule", event.Rule)
+			}
+		}
+	}
+}
+
+// publishAlerts delivers the alert events of its queue to one publisher. Every publisher has
+// a goroutine and a queue of its own, so that one retrying a failing destination does not
+// hold back the others.
+func (s *Service) publishAlerts(publisher alert.Publisher, queue <-chan alert.Event) {
+	defer s.wg.Done()
+	s.Logger.Info("Alert publisher started", "publisher", publisher.Name())
+
+	policy := retry.DefaultPolicy()
+	for {
+		select {
+		case event := <-queue:
+			attempts, err := policy.Do(s.Context, func(int) error {
+				return publisher.Publish(s.Context, event)
+			})
+			if err != nil {
+				s.metrics.alertPublishFailures.WithLabelValues(publisher.Name()).Inc()
+				s.Logger.Error("Failed to publish alert event",
+					"error", err,
+					"publisher", publisher.Name(),
+					"event_id", event.Id,
+					"attempts", attempts)
+				continue
+			}
+			s.Logger.Debug("Alert event published", "publisher", publisher.Name(), "event_id", event.Id)
+		case <-s.sigintChan:
+			s.Logger.Info("Alert publisher stopping", "publisher", publisher.Name())
+			return
+		case <-s.Context.Done():
+			s.Logger.Info("Alert publisher cancelled", "publisher", publisher.Name())
+			return
+		}
+	}
+}
+
+// closeAlertPublishers disconnects the publishers that hold a connection of their own.
+func (s *Service) closeAlertPublishers() {
+	for _, publisher := range s.alertPublishers {
+		if closer, ok := publisher.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// signaturePolicy is what happens to readings that are not signed by their device.
+type signaturePolicy string
+
+const (
+	signaturesOff        signaturePolicy = "off"
+	signaturesQuarantine signaturePolicy = "quarantine"
+	signaturesReject     signaturePolicy = "reject"
+)
+
+func parseSignaturePolicy(s string) (signaturePolicy, error) {
+	switch policy := signaturePolicy(s); policy {
+	case signaturesOff, signaturesQuarantine, signaturesReject:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid device signature policy %q, expected off, quarantine or reject", s)
+}
+
+// verifyReading checks the signature of a reading as it was sent. Unverified readings are
+// quarantined, and reported true, or returned as a permanent error under the reject policy.
+func (s *Service) verifyReading(ctx context.Context, msg *source.Message, input *usecase.CreateRewardInputDTO, settings *settings) (bool, int, error) {
+	if settings.signatures == signaturesOff {
+		return false, 0, nil
+	}
+
+	verifyReading := usecase.NewVerifyReadingUseCase(s.repository)
+	attempts, err := settings.retryPolicy.Do(ctx, func(int) error {
+		err := verifyReading.Execute(ctx, input)
+		if errors.Is(err, usecase.ErrUnverifiedReading) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("verify_reading").Inc()
+		}
+		return err
+	})
+	if err == nil {
+		return false, attempts, nil
+	}
+	if !errors.Is(err, usecase.ErrUnverifiedReading) {
+		s.Logger.Error("Failed to verify reading", "error", err, "sensor_id", input.SensorId, "attempts", attempts)
+		return false, attempts, fmt.Errorf("failed to verify reading: %w", err)
+	}
+
+	readingId := input.ReadingId
+	if readingId == "" {
+		readingId = msg.Id
+	}
+	if settings.signatures == signaturesReject {
+		s.metrics.readingsUnverified.WithLabelValues("rejected").Inc()
+		s.Logger.Warn("Rejecting unverified reading", "error", err, "reading_id", readingId, "sensor_id", input.SensorId)
+		return false, attempts, err
+	}
+
+	reason := err.Error()
+	attempts, err = s.quarantineReading(ctx, msg, input, &usecase.QuarantineReadingInputDTO{
+		ReadingId: readingId,
+		Check:     entity.QuarantineSignature,
+		Reason:    reason,
+	}, settings)
+	if err != nil {
+		return false, attempts, err
+	}
+	s.metrics.readingsUnverified.WithLabelValues("quarantined").Inc()
+	s.Logger.Warn("Reading quarantined", "reason", reason, "reading_id", readingId, "sensor_id", input.SensorId)
+	return true, attempts, nil
+}
+
+// quarantineReading stores a reading that is held back instead of rewarded. hold names the
+// check and the reason, and the rest is filled in from the message as it was received.
+func (s *Service) quarantineReading(ctx context.Context, msg *source.Message, input *usecase.CreateRewardInputDTO, hold *usecase.QuarantineReadingInputDTO, settings *settings) (int, error) {
+	hold.SensorId = input.SensorId
+	hold.Topic = msg.Topic
+	hold.Payload = msg.Value
+	hold.ReceivedAt = msg.ReceivedAt
+
+	quarantineReading := usecase.NewQuarantineReadingUseCase(s.repository)
+	attempts, err := settings.retryPolicy.Do(ctx, func(int) error {
+		err := quarantineReading.Execute(ctx, hold)
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("quarantine_reading").Inc()
+		}
+		return err
+	})
+	if err != nil {
+		s.Logger.Error("Failed to quarantine reading", "error", err, "reading_id", hold.ReadingId, "attempts", attempts)
+	}
+	return attempts, err
+}
@@ -0,0 +1,200 @@
+package relayer
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
+)
+
+// RewardEventMessage is the payload of the messages published to the events topic. Events
+// are delivered at least once: consumers deduplicate them by id.
+type RewardEventMessage struct {
+	Id         string                 `json:"id"`
+	Type       entity.RewardEventType `json:"type"`
+	RewardId   string                 `json:"reward_id"`
+	ReadingId  string                 `json:"reading_id"`
+	Receiver   string                 `json:"receiver"`
+	Amount     string                 `json:"amount"`
+	Token      string                 `json:"token"`
+	TxHash     string                 `json:"tx_hash,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+const eventsBatchSize = 100
+
+// notifyEvents wakes the events publisher up without waiting for the next poll.
+func (s *Service) notifyEvents() {
+	select {
+	case s.eventsSignal <- struct{}{}:
+	default:
+	}
+}
+
+// publishEvents publishes the reward lifecycle events stored in the outbox. An event is
+// marked as published only after the broker acknowledged it, so a crash in between leads to
+// a duplicate and never to a lost event.
+func (s *Service) publishEvents() {
+	defer s.wg.Done()
+
+	pollInterval := s.settings().outbox.pollInterval
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	s.Logger.Info("Reward events publisher started", "topic", s.settings().eventsTopic)
+	for {
+		s.publishPendingEvents()
+		if interval := s.settings().outbox.pollInterval; interval != pollInterval {
+			pollInterval = interval
+			ticker.Reset(pollInterval)
+		}
+
+		select {
+		case <-s.eventsSignal:
+		case <-ticker.C:
+		case <-s.sigintChan:
+			s.Logger.Info("Reward events publisher stopping")
+			return
+		case <-s.Context.Done():
+			s.Logger.Info("Reward events publisher cancelled")
+			return
+		}
+	}
+}
+
+// publishPendingEvents publishes the stored events that were not published yet. An event the
+// sink refuses is counted and only holds back the later events of its own reward, until it
+// is refused often enough to be sent to the dead-letter topic instead.
+func (s *Service) publishPendingEvents() {
+	findUseCase := usecase.NewFindUnpublishedRewardEventsUseCase(s.repository)
+	markUseCase := usecase.NewMarkRewardEventPublishedUseCase(s.repository)
+	failureUseCase := usecase.NewRecordRewardEventFailureUseCase(s.repository)
+
+	for s.Context.Err() == nil {
+		entries, err := findUseCase.Execute(s.Context, eventsBatchSize)
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("find_reward_events").Inc()
+			s.Logger.Error("Failed to load unpublished reward events", "error", err)
+			return
+		}
+
+		failed := false
+		for _, entry := range entries {
+			for i := range entry.Events {
+				event := &entry.Events[i]
+				if event.PublishedAt != nil {
+					continue
+				}
+				if err := s.publishEvent(entry, event); err != nil {
+					failed = true
+					s.Logger.Error("Failed to publish reward event",
+						"error", err,
+						"event_id", event.Id,
+						"type", event.Type,
+						"reward_id", entry.RewardId.Hex(),
+						"failures", event.PublishFailures+1)
+					if err := failureUseCase.Execute(s.Context, entry, event, err.Error()); err != nil {
+						s.metrics.dbErrors.WithLabelValues("record_reward_event_failure").Inc()
+						s.Logger.Error("Failed to record reward event failure", "error", err, "event_id", event.Id)
+						return
+					}
+					// Events of a reward are published in order, so a failure skips the rest of
+					// them, unless the event is given up on and dead-lettered.
+					maxFailures := s.settings().eventsFailures
+					if maxFailures <= 0 || event.PublishFailures < maxFailures {
+						break
+					}
+					if !s.deadLetterEvent(entry, event) {
+						break
+					}
+				}
+				if err := markUseCase.Execute(s.Context, entry, event); err != nil {
+					s.metrics.dbErrors.WithLabelValues("mark_reward_event_published").Inc()
+					s.Logger.Error("Failed to mark reward event as published", "error", err, "event_id", event.Id)
+					return
+				}
+				s.Logger.Debug("Reward event published",
+					"event_id", event.Id,
+					"type", event.Type,
+					"reward_id", entry.RewardId.Hex())
+			}
+		}
+
+		// A batch with failures is not followed by another, which could hold the same
+		// events, before the next tick.
+		if failed || len(entries) < eventsBatchSize {
+			return
+		}
+	}
+}
+
+// deadLetterEvent sends an event the sink keeps refusing to the dead-letter topic, from
+// where it can be replayed to the events topic. It reports whether the event was sent.
+func (s *Service) deadLetterEvent(entry *entity.OutboxEntry, event *entity.RewardEvent) bool {
+	settings := s.settings()
+	// An event that cannot be encoded is dead-lettered without its payload.
+	payload, _ := s.eventMessage(entry, event)
+	value, err := json.Marshal(&kafka.DeadLetter{
+		Topic:     settings.eventsTopic,
+		Partition: -1,
+		Offset:    -1,
+		Key:       entry.RewardId.Hex(),
+		Payload:   string(payload),
+		Error:     event.PublishError,
+		Attempts:  event.PublishFailures,
+		FailedAt:  time.Now().UTC(),
+	})
+	if err == nil {
+		err = s.sink.Publish(s.Context, settings.deadLetter, []byte(entry.RewardId.Hex()), value, nil)
+	}
+	if err != nil {
+		s.Logger.Error("Failed to publish reward event to the dead-letter topic",
+			"error", err,
+			"topic", settings.deadLetter,
+			"event_id", event.Id)
+		return false
+	}
+	s.Logger.Warn("Reward event sent to dead-letter topic",
+		"error", event.PublishError,
+		"failures", event.PublishFailures,
+		"topic", settings.deadLetter,
+		"event_id", event.Id,
+		"reward_id", entry.RewardId.Hex())
+	return true
+}
+
+func (s *Service) publishEvent(entry *entity.OutboxEntry, event *entity.RewardEvent) error {
+	value, err := s.eventMessage(entry, event)
+	if err != nil {
+		return err
+	}
+
+	// Keyed by reward so that the events of a reward land on the same partition, in order.
+	headers := map[string]string{
+		"event_id":   event.Id,
+		"event_type": string(event.Type),
+	}
+	for key, value := range entry.Trace {
+		headers[key] = value
+	}
+	return s.sink.Publish(s.Context, s.settings().eventsTopic, []byte(entry.RewardId.Hex()), value, headers)
+}
+
+// eventMessage encodes an event as the payload of the events topic.
+func (s *Service) eventMessage(entry *entity.OutboxEntry, event *entity.RewardEvent) ([]byte, error) {
+	return json.Marshal(RewardEventMessage{
+		Id:         event.Id,
+		Type:       event.Type,
+		RewardId:   entry.RewardId.Hex(),
+		ReadingId:  entry.ReadingId,
+		Receiver:   entry.Receiver,
+		Amount:     entry.Amount,
+		Token:      entry.Token,
+		TxHash:     event.TxHash,
+		Error:      event.Error,
+		OccurredAt: event.OccurredAt,
+	})
+}
@@ -0,0 +1,69 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/gas"
+)
+
+// gasLedger keeps the daily gas budget in the repository, so that it survives restarts and
+// is shared by every relayer sending from the same account.
+type gasLedger struct {
+	repository repository.GasRepository
+}
+
+func (l gasLedger) Reserve(ctx context.Context, day time.Time, cost, limit *big.Int) (*big.Int, error) {
+	spent, err := l.repository.ReserveGasSpend(ctx, day, cost, limit)
+	if errors.Is(err, entity.ErrGasBudgetExceeded) {
+		return nil, gas.ErrDailyBudgetExceeded
+	}
+	return spent, err
+}
+
+func (l gasLedger) Adjust(ctx context.Context, day time.Time, delta *big.Int) (*big.Int, error) {
+	return l.repository.AdjustGasSpend(ctx, day, delta)
+}
+
+// reserveGas records a reservation of the gas strategy on an entry, to settle it once the
+// transaction has a receipt.
+func reserveGas(entry *entity.OutboxEntry, reservation *gas.Reservation) {
+	entry.GasReserved = append(slices.Clone(entry.GasReserved), entity.GasReservation{
+		Day:    reservation.Day,
+		Amount: reservation.Cost.String(),
+	})
+}
+
+// refundGas gives back a reservation for a transaction that was not sent. A failure only
+// leaves the budget lower than it should be for the day, so it is logged and not retried.
+func (s *Service) refundGas(reservation *gas.Reservation) {
+	if err := s.gasStrategy.Refund(s.Context, reservation); err != nil {
+		s.metrics.dbErrors.WithLabelValues("adjust_gas_spend").Inc()
+		s.Logger.Error("Failed to refund gas reservation", "error", err, "cost", reservation.Cost, "day", reservation.Day)
+	}
+}
+
+// settleGas replaces what an entry reserved on the gas budget by what its transaction cost,
+// which is zero for a transaction that was never mined.
+func (s *Service) settleGas(entry *entity.OutboxEntry, reserved []entity.GasReservation, cost *big.Int) {
+	reservations := make([]gas.Reservation, 0, len(reserved))
+	for _, r := range reserved {
+		amount, ok := new(big.Int).SetString(r.Amount, 10)
+		if !ok {
+			s.Logger.Error("Invalid gas reservation", "id", entry.Id.Hex(), "amount", r.Amount)
+			return
+		}
+		reservations = append(reservations, gas.Reservation{Day: r.Day, Cost: amount})
+	}
+	if err := s.gasStrategy.Settle(s.Context, reservations, cost); err != nil {
+		s.metrics.dbErrors.WithLabelValues("adjust_gas_spend").Inc()
+		s.Logger.Error("Failed to settle gas reservations", "error", err, "id", entry.Id.Hex(), "cost", cost)
+		return
+	}
+	s.Logger.Debug("Gas reservations settled", "id", entry.Id.Hex(), "tx_hash", entry.TxHash, "cost", cost)
+}
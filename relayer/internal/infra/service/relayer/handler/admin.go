@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+)
+
+// AdminHandlers manage the registries of devices, sensors and receivers, and inspect and
+// release the readings held back. Every route requires the admin token as a bearer token.
+type AdminHandlers struct {
+	Repository repository.Repository
+	Token      string
+	Logger     *slog.Logger
+}
+
+func NewAdminHandlers(repository repository.Repository, token string, logger *slog.Logger) *AdminHandlers {
+	return &AdminHandlers{
+		Repository: repository,
+		Token:      token,
+		Logger:     logger,
+	}
+}
+
+// Register adds the routes of the admin API to mux.
+func (h *AdminHandlers) Register(mux *http.ServeMux) {
+	mux.Handle("PUT /api/v1/devices/{sensor_id}", h.authorize(h.RegisterDevice))
+	mux.Handle("GET /api/v1/devices/{sensor_id}", h.authorize(h.FindDevice))
+	mux.Handle("DELETE /api/v1/devices/{sensor_id}", h.authorize(h.DeleteDevice))
+	mux.Handle("PUT /api/v1/sensors/{sensor_id}", h.authorize(h.RegisterSensor))
+	mux.Handle("GET /api/v1/sensors/{sensor_id}", h.authorize(h.FindSensor))
+	mux.Handle("DELETE /api/v1/sensors/{sensor_id}", h.authorize(h.DeleteSensor))
+	mux.Handle("PUT /api/v1/receivers/{address}", h.authorize(h.RegisterReceiver))
+	mux.Handle("GET /api/v1/receivers/{address}", h.authorize(h.FindReceiver))
+	mux.Handle("DELETE /api/v1/receivers/{address}", h.authorize(h.DeleteReceiver))
+	mux.Handle("GET /api/v1/quarantine", h.authorize(h.FindQuarantinedReadings))
+	mux.Handle("POST /api/v1/quarantine/{reading_id}/release", h.authorize(h.ReleaseQuarantinedReading))
+}
+
+func (h *AdminHandlers) authorize(next http.HandlerFunc) http.Handler {
+	return bearer(h.Token, next)
+}
+
+// bearer only passes the requests that carry token as a bearer token, and none when it is
+// empty.
+func bearer(token string, next http.HandlerFunc) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// RegisterDevice sets the public key of a sensor, from a body such as {"public_key": "..."}.
+func (h *AdminHandlers) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	var input usecase.RegisterDeviceInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	input.SensorId = r.PathValue("sensor_id")
+
+	registerDevice := usecase.NewRegisterDeviceUseCase(h.Repository)
+	output, err := registerDevice.Execute(r.Context(), &input)
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Device registered", "sensor_id", output.SensorId)
+	writeJSON(w, output)
+}
+
+func (h *AdminHandlers) FindDevice(w http.ResponseWriter, r *http.Request) {
+	findDevice := usecase.NewFindDeviceUseCase(h.Repository)
+	output, err := findDevice.Execute(r.Context(), r.PathValue("sensor_id"))
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	writeJSON(w, output)
+}
+
+func (h *AdminHandlers) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	sensorId := r.PathValue("sensor_id")
+	deleteDevice := usecase.NewDeleteDeviceUseCase(h.Repository)
+	if err := deleteDevice.Execute(r.Context(), sensorId); err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Device unregistered", "sensor_id", sensorId)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegisterSensor sets the payout of a sensor, from a body such as
+// {"receiver": "0x...", "rate": "1000000000000000000"}.
+func (h *AdminHandlers) RegisterSensor(w http.ResponseWriter, r *http.Request) {
+	var input usecase.RegisterSensorInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	input.SensorId = r.PathValue("sensor_id")
+
+	registerSensor := usecase.NewRegisterSensorUseCase(h.Repository)
+	output, err := registerSensor.Execute(r.Context(), &input)
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Sensor registered", "sensor_id", output.SensorId, "receiver", output.Receiver, "rate", output.Rate)
+	writeJSON(w, output)
+}
+
+func (h *AdminHandlers) FindSensor(w http.ResponseWriter, r *http.Request) {
+	findSensor := usecase.NewFindSensorUseCase(h.Repository)
+	output, err := findSensor.Execute(r.Context(), r.PathValue("sensor_id"))
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	writeJSON(w, output)
+}
+
+func (h *AdminHandlers) DeleteSensor(w http.ResponseWriter, r *http.Request) {
+	sensorId := r.PathValue("sensor_id")
+	deleteSensor := usecase.NewDeleteSensorUseCase(h.Repository)
+	if err := deleteSensor.Execute(r.Context(), sensorId); err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Sensor unregistered", "sensor_id", sensorId)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegisterReceiver allows or denies rewards to an address, from a body such as
+// {"status": "allowed", "ens_name": "alice.eth", "daily_cap": "10000000000000000000"}.
+func (h *AdminHandlers) RegisterReceiver(w http.ResponseWriter, r *http.Request) {
+	var input usecase.RegisterReceiverInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	input.Address = r.PathValue("address")
+
+	registerReceiver := usecase.NewRegisterReceiverUseCase(h.Repository)
+	output, err := registerReceiver.Execute(r.Context(), &input)
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Receiver registered", "address", output.Address, "status", output.Status, "daily_cap", output.DailyCap)
+	writeJSON(w, output)
+}
+
+func (h *AdminHandlers) FindReceiver(w http.ResponseWriter, r *http.Request) {
+	findReceiver := usecase.NewFindReceiverUseCase(h.Repository)
+	output, err := findReceiver.Execute(r.Context(), r.PathValue("address"))
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	writeJSON(w, output)
+}
+
+func (h *AdminHandlers) DeleteReceiver(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("address")
+	deleteReceiver := usecase.NewDeleteReceiverUseCase(h.Repository)
+	if err := deleteReceiver.Execute(r.Context(), address); err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Receiver unregistered", "address", address)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FindQuarantinedReadings returns the latest readings held back, newest first. The query
+// takes sensor_id and limit.
+func (h *AdminHandlers) FindQuarantinedReadings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := &usecase.FindQuarantinedReadingsInputDTO{SensorId: query.Get("sensor_id")}
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		if input.Limit, err = strconv.ParseInt(limit, 10, 64); err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	findQuarantined := usecase.NewFindQuarantinedReadingsUseCase(h.Repository)
+	output, err := findQuarantined.Execute(r.Context(), input)
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	writeJSON(w, output)
+}
+
+// ReleaseQuarantinedReading has a reading held by the payout or receiver checks processed
+// again, from the check that held it, on the next tick of the relayer.
+func (h *AdminHandlers) ReleaseQuarantinedReading(w http.ResponseWriter, r *http.Request) {
+	readingId := r.PathValue("reading_id")
+	releaseQuarantined := usecase.NewReleaseQuarantinedReadingUseCase(h.Repository)
+	output, err := releaseQuarantined.Execute(r.Context(), readingId, time.Now())
+	if err != nil {
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Quarantined reading released", "reading_id", readingId, "check", output.Check)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(output)
+}
+
+// writeError answers an admin or enrollment request that failed with err, logging the
+// errors that are not the caller's.
+func writeError(w http.ResponseWriter, err error, logger *slog.Logger) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidDevice), errors.Is(err, entity.ErrInvalidSensor), errors.Is(err, entity.ErrInvalidReceiver),
+		errors.Is(err, usecase.ErrInvalidQuarantineQuery):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, entity.ErrDeviceNotFound), errors.Is(err, entity.ErrSensorNotFound), errors.Is(err, entity.ErrReceiverNotFound),
+		errors.Is(err, entity.ErrQuarantinedReadingNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, entity.ErrNotReleasable), errors.Is(err, entity.ErrDeviceRegistered):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Error("Failed to serve admin request", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
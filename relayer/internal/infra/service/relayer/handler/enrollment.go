@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+)
+
+// EnrollmentHandlers let devices register their own public key, once. They take a token of
+// their own, which cannot replace a key nor touch the payouts, so that the edge never holds
+// the admin token.
+type EnrollmentHandlers struct {
+	Repository repository.Repository
+	Token      string
+	Logger     *slog.Logger
+}
+
+func NewEnrollmentHandlers(repository repository.Repository, token string, logger *slog.Logger) *EnrollmentHandlers {
+	return &EnrollmentHandlers{
+		Repository: repository,
+		Token:      token,
+		Logger:     logger,
+	}
+}
+
+// Register adds the routes of the enrollment API to mux.
+func (h *EnrollmentHandlers) Register(mux *http.ServeMux) {
+	mux.Handle("POST /api/v1/enrollments/{sensor_id}", bearer(h.Token, h.EnrollDevice))
+}
+
+// EnrollDevice registers the public key of a sensor that has none, from a body such as
+// {"public_key": "..."}. The key already registered is accepted again, and another one is
+// refused with 409 Conflict.
+func (h *EnrollmentHandlers) EnrollDevice(w http.ResponseWriter, r *http.Request) {
+	var input usecase.RegisterDeviceInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	input.SensorId = r.PathValue("sensor_id")
+
+	enrollDevice := usecase.NewEnrollDeviceUseCase(h.Repository)
+	output, err := enrollDevice.Execute(r.Context(), &input)
+	if err != nil {
+		h.Logger.Warn("Device enrollment refused", "sensor_id", input.SensorId, "error", err)
+		writeError(w, err, h.Logger)
+		return
+	}
+	h.Logger.Info("Device enrolled", "sensor_id", output.SensorId)
+	writeJSON(w, output)
+}
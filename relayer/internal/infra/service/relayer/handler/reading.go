@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/aqi"
+)
+
+// ReadingHandlers serve aggregations of the readings of the sensors.
+type ReadingHandlers struct {
+	Repository repository.Repository
+	Standard   aqi.Standard // default scale of the air quality index
+	Logger     *slog.Logger
+}
+
+func NewReadingHandlers(repository repository.Repository, standard aqi.Standard, logger *slog.Logger) *ReadingHandlers {
+	return &ReadingHandlers{
+		Repository: repository,
+		Standard:   standard,
+		Logger:     logger,
+	}
+}
+
+// Register adds the routes of the reading API to mux.
+func (h *ReadingHandlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/readings/stats", h.AggregateReadings)
+	mux.HandleFunc("GET /api/v1/readings/heatmap", h.ReadingHeatmap)
+	mux.HandleFunc("GET /api/v1/readings/aqi", h.ComputeAQI)
+	mux.HandleFunc("GET /api/v1/sensors/{id}/reputation", h.FindSensorReputation)
+}
+
+// AggregateReadings returns the minimum, maximum and average of every parameter per sensor
+// and window. The query takes sensor_id, from, to and window, a duration such as 15m, or 0
+// for the whole range. The range defaults to the last 24 hours and the window to an hour.
+func (h *ReadingHandlers) AggregateReadings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := &usecase.AggregateReadingsInputDTO{
+		SensorId: query.Get("sensor_id"),
+		Window:   time.Hour,
+	}
+
+	var err error
+	if input.From, input.To, err = parseTimeRange(query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if window := query.Get("window"); window != "" {
+		if input.Window, err = time.ParseDuration(window); err != nil {
+			http.Error(w, "invalid window", http.StatusBadRequest)
+			return
+		}
+	}
+
+	aggregateReadings := usecase.NewAggregateReadingsUseCase(h.Repository)
+	output, err := aggregateReadings.Execute(r.Context(), input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, output)
+}
+
+// ReadingHeatmap returns a grid of a parameter over the city. The query takes parameter,
+// from, to and cell, the size of the cells in degrees.
+func (h *ReadingHandlers) ReadingHeatmap(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := &usecase.ReadingHeatmapInputDTO{Parameter: query.Get("parameter")}
+
+	var err error
+	if input.From, input.To, err = parseTimeRange(query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if cell := query.Get("cell"); cell != "" {
+		if input.CellSize, err = strconv.ParseFloat(cell, 64); err != nil {
+			http.Error(w, "invalid cell", http.StatusBadRequest)
+			return
+		}
+	}
+
+	readingHeatmap := usecase.NewReadingHeatmapUseCase(h.Repository)
+	output, err := readingHeatmap.Execute(r.Context(), input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, output)
+}
+
+// ComputeAQI returns the air quality index of every sensor, or of sensor_id, at the time at.
+// The query may take standard, us-epa or eu, to use another scale than the configured one.
+func (h *ReadingHandlers) ComputeAQI(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := &usecase.ComputeAQIInputDTO{SensorId: query.Get("sensor_id")}
+
+	var err error
+	if input.At, err = parseTime(query, "at"); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	computeAQI := usecase.NewComputeAQIUseCase(h.Repository)
+	computeAQI.Standard = h.Standard
+	if name := query.Get("standard"); name != "" {
+		if computeAQI.Standard, err = aqi.ParseStandard(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	output, err := computeAQI.Execute(r.Context(), input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, output)
+}
+
+// FindSensorReputation returns the data quality score of a sensor, the state of the checks
+// on its readings and the latest reasons it lost score.
+func (h *ReadingHandlers) FindSensorReputation(w http.ResponseWriter, r *http.Request) {
+	findReputation := usecase.NewFindSensorReputationUseCase(h.Repository)
+	output, err := findReputation.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, output)
+}
+
+func (h *ReadingHandlers) writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, usecase.ErrInvalidReadingQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, entity.ErrReputationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	h.Logger.Error("Failed to query readings", "error", err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
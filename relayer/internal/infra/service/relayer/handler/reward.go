@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+)
+
+// RewardHandlers serve the settlement history of the relayer: one reward per reading paid,
+// with the status of its mint.
+type RewardHandlers struct {
+	Repository repository.Repository
+	Logger     *slog.Logger
+}
+
+func NewRewardHandlers(repository repository.Repository, logger *slog.Logger) *RewardHandlers {
+	return &RewardHandlers{
+		Repository: repository,
+		Logger:     logger,
+	}
+}
+
+// Register adds the routes of the read API to mux.
+func (h *RewardHandlers) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/rewards", h.FindRewards)
+	mux.HandleFunc("GET /api/v1/rewards/{id}", h.FindReward)
+	mux.HandleFunc("GET /api/v1/receivers/{address}/rewards", h.FindReceiverRewards)
+	mux.HandleFunc("GET /api/v1/receivers/{address}/totals", h.SumReceiverRewards)
+	mux.HandleFunc("GET /api/v1/sensors/{id}/rewards", h.FindSensorRewards)
+}
+
+// FindRewards lists rewards, newest first, filtered by the query: receiver, sensor_id,
+// latitude and longitude, state, from and to. Pages hold limit rewards and continue with
+// cursor. With format=csv, or an Accept header asking for text/csv, every page is exported
+// as CSV instead.
+func (h *RewardHandlers) FindRewards(w http.ResponseWriter, r *http.Request) {
+	input, err := parseRewardsQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.findRewards(w, r, input)
+}
+
+// FindReceiverRewards lists the rewards of the receiver in the path, like FindRewards.
+func (h *RewardHandlers) FindReceiverRewards(w http.ResponseWriter, r *http.Request) {
+	input, err := parseRewardsQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.Receiver = r.PathValue("address")
+	h.findRewards(w, r, input)
+}
+
+// FindSensorRewards lists the rewards of the sensor in the path, like FindRewards.
+func (h *RewardHandlers) FindSensorRewards(w http.ResponseWriter, r *http.Request) {
+	input, err := parseRewardsQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.SensorId = r.PathValue("id")
+	h.findRewards(w, r, input)
+}
+
+func (h *RewardHandlers) findRewards(w http.ResponseWriter, r *http.Request, input *usecase.FindSettlementsInputDTO) {
+	findSettlements := usecase.NewFindSettlementsUseCase(h.Repository)
+	if wantsCSV(r) {
+		h.exportCSV(w, r, findSettlements, input)
+		return
+	}
+
+	output, err := findSettlements.Execute(r.Context(), input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, output)
+}
+
+// FindReward returns a reward with its mint status and lifecycle events.
+func (h *RewardHandlers) FindReward(w http.ResponseWriter, r *http.Request) {
+	findSettlement := usecase.NewFindSettlementByIdUseCase(h.Repository)
+	output, err := findSettlement.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, output)
+}
+
+// SumReceiverRewards returns the amounts paid to a receiver and still pending, optionally
+// between from and to.
+func (h *RewardHandlers) SumReceiverRewards(w http.ResponseWriter, r *http.Request) {
+	input := &usecase.SumReceiverSettlementsInputDTO{Receiver: r.PathValue("address")}
+
+	var err error
+	if input.From, input.To, err = parseTimeRange(r.URL.Query()); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sumSettlements := usecase.NewSumReceiverSettlementsUseCase(h.Repository)
+	output, err := sumSettlements.Execute(r.Context(), input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	writeJSON(w, output)
+}
+
+var csvHeader = []string{
+	"id", "reward_id", "reading_id", "sensor_id", "receiver", "token", "amount", "latitude", "longitude",
+	"state", "attempts", "tx_hash", "last_error", "created_at", "updated_at",
+}
+
+// exportCSV writes every page from the cursor on. Errors after the first page can only cut
+// the export short, since the status was already sent.
+func (h *RewardHandlers) exportCSV(w http.ResponseWriter, r *http.Request, findSettlements *usecase.FindSettlementsUseCase, input *usecase.FindSettlementsInputDTO) {
+	input.Limit = usecase.MaxSettlementPageSize
+	output, err := findSettlements.Execute(r.Context(), input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="rewards.csv"`)
+	writer := csv.NewWriter(w)
+	writer.Write(csvHeader)
+	for {
+		for _, s := range output.Settlements {
+			writer.Write([]string{
+				s.Id.Hex(), s.RewardId.Hex(), s.ReadingId, s.SensorId, s.Receiver, s.Token, s.Amount,
+				strconv.FormatFloat(s.Latitude, 'f', -1, 64),
+				strconv.FormatFloat(s.Longitude, 'f', -1, 64),
+				string(s.State), strconv.Itoa(s.Attempts), s.TxHash, s.LastError,
+				s.CreatedAt.UTC().Format(time.RFC3339), s.UpdatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		writer.Flush()
+		if writer.Error() != nil || output.NextCursor == "" {
+			return
+		}
+
+		input.Cursor = output.NextCursor
+		output, err = findSettlements.Execute(r.Context(), input)
+		if err != nil {
+			h.Logger.Error("Failed to export rewards", "error", err, "cursor", input.Cursor)
+			return
+		}
+	}
+}
+
+func (h *RewardHandlers) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSettlementQuery):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, entity.ErrSettlementNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		h.Logger.Error("Failed to query rewards", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func parseRewardsQuery(query url.Values) (*usecase.FindSettlementsInputDTO, error) {
+	input := &usecase.FindSettlementsInputDTO{
+		Receiver: query.Get("receiver"),
+		SensorId: query.Get("sensor_id"),
+		Cursor:   query.Get("cursor"),
+	}
+
+	var err error
+	if input.Latitude, err = parseFloat(query, "latitude"); err != nil {
+		return nil, err
+	}
+	if input.Longitude, err = parseFloat(query, "longitude"); err != nil {
+		return nil, err
+	}
+	if input.From, input.To, err = parseTimeRange(query); err != nil {
+		return nil, err
+	}
+	for _, value := range query["state"] {
+		for _, state := range strings.Split(value, ",") {
+			input.States = append(input.States, entity.OutboxState(state))
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if input.Limit, err = strconv.ParseInt(limit, 10, 64); err != nil || input.Limit <= 0 {
+			return nil, errors.New("invalid limit")
+		}
+	}
+	return input, nil
+}
+
+func wantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "csv"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+func parseFloat(query url.Values, key string) (*float64, error) {
+	values := query[key]
+	if len(values) == 0 || values[0] == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(values[0], 64)
+	if err != nil {
+		return nil, errors.New("invalid " + key)
+	}
+	return &value, nil
+}
+
+// parseTimeRange reads from and to, as RFC 3339 timestamps or Unix seconds.
+func parseTimeRange(query url.Values) (from, to time.Time, err error) {
+	if from, err = parseTime(query, "from"); err != nil {
+		return
+	}
+	to, err = parseTime(query, "to")
+	return
+}
+
+func parseTime(query url.Values, key string) (time.Time, error) {
+	values := query[key]
+	if len(values) == 0 || values[0] == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(values[0], 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, values[0])
+	if err != nil {
+		return time.Time{}, errors.New("invalid " + key + ", expected RFC 3339 or Unix seconds")
+	}
+	return t, nil
+}
@@ -0,0 +1,63 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/configs"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// registerHealthChecks adds the dependencies of the relayer to the readiness checks: a
+// relayer that cannot reach one of them accepts messages it cannot turn into mints. It is
+// called again on reload, which replaces the checks with ones using the new thresholds.
+func registerHealthChecks(s *Service, config configs.RelayerConfig) {
+	if config.HealthCheckTimeout > 0 {
+		s.Health.SetTimeout(config.HealthCheckTimeout)
+	}
+
+	s.Health.Register("mongodb", s.repository.Ping)
+
+	if checker, ok := s.source.(source.HealthChecker); ok {
+		s.Health.Register("source", checker.Health)
+	}
+
+	chainId := new(big.Int).SetUint64(config.BlockchainId)
+	maxBlockAge := config.HealthMaxBlockAge
+	s.Health.Register("rpc", func(ctx context.Context) error {
+		id, err := s.ethClient.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		if id.Cmp(chainId) != 0 {
+			return fmt.Errorf("chainId mismatch: network %d != provided %d", id, chainId)
+		}
+
+		header, err := s.ethClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		age := time.Since(time.Unix(int64(header.Time), 0))
+		if maxBlockAge > 0 && age > maxBlockAge {
+			return fmt.Errorf("latest block %d is %s old, node is out of sync", header.Number, age.Truncate(time.Second))
+		}
+		return nil
+	})
+
+	minBalance := config.HealthMinBalance
+	s.Health.Register("signer_balance", func(ctx context.Context) error {
+		if minBalance == nil || minBalance.Sign() <= 0 {
+			return nil
+		}
+		balance, err := s.ethClient.BalanceAt(ctx, s.txOpts.From, nil)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(minBalance) < 0 {
+			return fmt.Errorf("signer %s balance %s wei is below %s wei", s.txOpts.From.Hex(), balance, minBalance)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,66 @@
+package relayer
+
+import (
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+)
+
+func (s *Service) refreshWalletBalance() error {
+	balance, err := s.ethClient.BalanceAt(s.Context, s.txOpts.From, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+	s.metrics.walletBalance.Set(weiToEth(balance))
+	return nil
+}
+
+// checkPendingReceipts wakes the outbox dispatcher up, which looks for the receipts of the
+// submitted transactions, and reports how many entries are in each state. The receipts are
+// not checked here, so that a single goroutine updates the submitted entries.
+func (s *Service) checkPendingReceipts() error {
+	s.notifyOutbox()
+
+	countUseCase := usecase.NewCountOutboxEntriesUseCase(s.repository)
+	counts, err := countUseCase.Execute(s.Context)
+	if err != nil {
+		s.metrics.dbErrors.WithLabelValues("count_outbox_entries").Inc()
+		return err
+	}
+	for state, count := range counts {
+		s.metrics.outboxEntries.WithLabelValues(string(state)).Set(float64(count))
+	}
+	return nil
+}
+
+// compactOutbox drops the signed transactions and trace contexts of the entries that finished
+// more than RELAYER_OUTBOX_RETENTION ago.
+func (s *Service) compactOutbox() error {
+	retention := s.settings().outbox.retention
+	if retention <= 0 {
+		return nil
+	}
+
+	compactUseCase := usecase.NewCompactOutboxEntriesUseCase(s.repository)
+	compacted, err := compactUseCase.Execute(s.Context, retention)
+	if err != nil {
+		s.metrics.dbErrors.WithLabelValues("compact_outbox_entries").Inc()
+		return err
+	}
+	if compacted > 0 {
+		s.metrics.outboxCompacted.Add(float64(compacted))
+		s.Logger.Info("Outbox entries compacted", "count", compacted)
+	}
+	return nil
+}
+
+// downsampleHistory rolls the reward history up per sensor and hour, so that the charts of
+// the history outlive RELAYER_HISTORY_RETENTION.
+func (s *Service) downsampleHistory() error {
+	downsampleUseCase := usecase.NewDownsampleRewardRecordsUseCase(s.repository)
+	if err := downsampleUseCase.Execute(s.Context); err != nil {
+		s.metrics.dbErrors.WithLabelValues("downsample_reward_records").Inc()
+		return err
+	}
+	return nil
+}
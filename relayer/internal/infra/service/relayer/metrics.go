@@ -0,0 +1,182 @@
+package relayer
+
+import (
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+)
+
+type metrics struct {
+	messagesConsumed     *prometheus.CounterVec
+	processingDuration   prometheus.Histogram
+	dbErrors             *prometheus.CounterVec
+	mintsSubmitted       prometheus.Counter
+	mintsConfirmed       prometheus.Counter
+	mintsFailed          prometheus.Counter
+	mintsReplaced        prometheus.Counter
+	mintsStuck           prometheus.Gauge
+	gasSpent             prometheus.Counter
+	walletBalance        prometheus.Gauge
+	outboxEntries        *prometheus.GaugeVec
+	outboxCompacted      prometheus.Counter
+	readingsValidated    *prometheus.CounterVec
+	readingsUnverified   *prometheus.CounterVec
+	payoutsRejected      prometheus.Counter
+	readingsReplayed     *prometheus.CounterVec
+	rewardsHeld          *prometheus.CounterVec
+	readingsReleased     *prometheus.CounterVec
+	alerts               *prometheus.CounterVec
+	alertPublishFailures *prometheus.CounterVec
+}
+
+func newMetrics(s *Service) *metrics {
+	m := &metrics{
+		messagesConsumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_messages_consumed_total",
+			Help: "Messages consumed from the source, by topic and outcome (rewarded, duplicate, withheld, dead_lettered or nacked).",
+		}, []string{"topic", "outcome"}),
+		processingDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "relayer_message_processing_seconds",
+			Help:    "Time from receiving a message to saving its reward, retries included.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+		}),
+		dbErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_db_errors_total",
+			Help: "Failed database operations, by operation.",
+		}, []string{"operation"}),
+		mintsSubmitted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "relayer_mints_submitted_total",
+			Help: "Mint transactions signed, stored and broadcast.",
+		}),
+		mintsConfirmed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "relayer_mints_confirmed_total",
+			Help: "Mint transactions included in a block that succeeded.",
+		}),
+		mintsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "relayer_mints_failed_total",
+			Help: "Mints that reverted or ran out of attempts.",
+		}),
+		mintsReplaced: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "relayer_mints_replaced_total",
+			Help: "Unconfirmed mint transactions signed again with the same nonce and a higher fee.",
+		}),
+		mintsStuck: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "relayer_mints_stuck",
+			Help: "Mint transactions unconfirmed for longer than the replacement timeout that could not be replaced, refreshed on every outbox scan.",
+		}),
+		gasSpent: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "relayer_gas_spent_eth_total",
+			Help: "ETH paid in fees by the mint transactions with a receipt.",
+		}),
+		walletBalance: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "relayer_wallet_balance_eth",
+			Help: "ETH balance of the minting wallet, refreshed on every tick.",
+		}),
+		outboxEntries: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "relayer_outbox_entries",
+			Help: "Outbox entries by state, refreshed on every tick.",
+		}, []string{"state"}),
+		outboxCompacted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "relayer_outbox_entries_compacted_total",
+			Help: "Finished outbox entries stripped of their signed transaction and trace context.",
+		}),
+		readingsValidated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_readings_validated_total",
+			Help: "Readings through the data quality checks, by outcome (clean, flagged, rejected or withheld).",
+		}, []string{"outcome"}),
+		readingsUnverified: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_readings_unverified_total",
+			Help: "Readings not signed by the registered key of their sensor, by action (quarantined or rejected).",
+		}, []string{"action"}),
+		payoutsRejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "relayer_payouts_rejected_total",
+			Help: "Readings quarantined because their sensor is not registered or claims another payout.",
+		}),
+		readingsReplayed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_readings_replayed_total",
+			Help: "Readings rejected as replays, by reason (unsequenced, stale or sequence).",
+		}, []string{"reason"}),
+		rewardsHeld: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_rewards_held_total",
+			Help: "Rewards quarantined by the receiver policy, by reason (denied, unlisted or daily_cap).",
+		}, []string{"reason"}),
+		readingsReleased: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_readings_released_total",
+			Help: "Quarantined readings processed again, by outcome (rewarded, quarantined, withheld, duplicate or failed).",
+		}, []string{"outcome"}),
+		alerts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_aqi_alerts_total",
+			Help: "Air quality alerts raised or cleared, by rule and event type.",
+		}, []string{"rule", "type"}),
+		alertPublishFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "relayer_aqi_alert_publish_failures_total",
+			Help: "Alert events a publisher failed to deliver after retrying, by publisher, or dropped because the buffer was full.",
+		}, []string{"publisher"}),
+	}
+
+	s.Registry.MustRegister(
+		m.messagesConsumed,
+		m.processingDuration,
+		m.dbErrors,
+		m.mintsSubmitted,
+		m.mintsConfirmed,
+		m.mintsFailed,
+		m.mintsReplaced,
+		m.mintsStuck,
+		m.gasSpent,
+		m.walletBalance,
+		m.outboxEntries,
+		m.outboxCompacted,
+		m.readingsValidated,
+		m.readingsUnverified,
+		m.payoutsRejected,
+		m.readingsReplayed,
+		m.rewardsHeld,
+		m.readingsReleased,
+		m.alerts,
+		m.alertPublishFailures,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "relayer_gas_spent_today_eth",
+			Help: "ETH spent in fees today, as counted against the daily gas budget.",
+		}, func() float64 { return weiToEth(s.gasStrategy.Spent()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "relayer_worker_queue_depth",
+			Help: "Messages waiting for a worker.",
+		}, func() float64 { return float64(len(s.jobChan)) }),
+	)
+
+	if statsSource, ok := s.source.(kafkaStatsSource); ok {
+		s.Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "relayer_kafka_consumer_lag",
+			Help: "Messages behind the end of the assigned partitions, from the latest client statistics.",
+		}, func() float64 {
+			if stats := statsSource.Stats(); stats != nil {
+				return float64(stats.Lag)
+			}
+			return 0
+		}))
+	}
+	return m
+}
+
+func validationOutcome(validation *usecase.ValidateReadingOutputDTO) string {
+	switch {
+	case validation.Rejected:
+		return "rejected"
+	case validation.Withheld:
+		return "withheld"
+	case len(validation.Findings) > 0:
+		return "flagged"
+	}
+	return "clean"
+}
+
+func weiToEth(wei *big.Int) float64 {
+	if wei == nil {
+		return 0
+	}
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return eth
+}
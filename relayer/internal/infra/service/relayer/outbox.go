@@ -0,0 +1,577 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/contracts/rewardtoken"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/gas"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/tracing"
+)
+
+type outboxConfig struct {
+	owner        string
+	pollInterval time.Duration
+	claimTimeout time.Duration
+	maxAttempts  int
+	retention    time.Duration
+	replaceAfter time.Duration
+	// retryPolicy is how long a failed entry waits before it is claimed again.
+	retryPolicy retry.Policy
+}
+
+// notifyOutbox wakes the dispatcher up without waiting for the next poll.
+func (s *Service) notifyOutbox() {
+	select {
+	case s.outboxSignal <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchOutbox mints pending outbox entries and tracks submitted transactions until they
+// are confirmed. Since every step is persisted, a restart resumes where the last run stopped.
+func (s *Service) dispatchOutbox() {
+	defer s.wg.Done()
+
+	pollInterval := s.settings().outbox.pollInterval
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	s.Logger.Info("Outbox dispatcher started", "owner", s.settings().outbox.owner)
+	for {
+		s.checkSubmittedEntries()
+		minting := s.mintPendingEntries()
+		s.notifyEvents()
+		if interval := s.settings().outbox.pollInterval; interval != pollInterval {
+			pollInterval = interval
+			ticker.Reset(pollInterval)
+		}
+		if !minting {
+			select {
+			case <-time.After(s.settings().pauseInterval):
+				continue
+			case <-s.sigintChan:
+				s.Logger.Info("Outbox dispatcher stopping")
+				return
+			case <-s.Context.Done():
+				s.Logger.Info("Outbox dispatcher cancelled")
+				return
+			}
+		}
+
+		select {
+		case <-s.outboxSignal:
+		case <-ticker.C:
+		case <-s.sigintChan:
+			s.Logger.Info("Outbox dispatcher stopping")
+			return
+		case <-s.Context.Done():
+			s.Logger.Info("Outbox dispatcher cancelled")
+			return
+		}
+	}
+}
+
+// mintPendingEntries claims and mints entries until the outbox is drained.
+// It returns false when minting is paused by a gas limit.
+func (s *Service) mintPendingEntries() bool {
+	claimUseCase := usecase.NewClaimOutboxEntryUseCase(s.repository)
+	updateUseCase := usecase.NewUpdateOutboxEntryUseCase(s.repository)
+
+	for s.Context.Err() == nil {
+		outbox := s.settings().outbox
+		entry, err := claimUseCase.Execute(s.Context, outbox.owner, outbox.claimTimeout)
+		if errors.Is(err, entity.ErrOutboxEntryNotFound) {
+			return true
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("claim_outbox_entry").Inc()
+			s.Logger.Error("Failed to claim outbox entry", "error", err)
+			return true
+		}
+
+		if !s.submitEntry(entry, updateUseCase) {
+			return false
+		}
+	}
+	return true
+}
+
+// submitEntry signs, stores and broadcasts the mint transaction of a claimed entry, within
+// the trace of the reading that created it. It returns false when minting is paused by a
+// gas limit.
+func (s *Service) submitEntry(entry *entity.OutboxEntry, updateUseCase *usecase.UpdateOutboxEntryUseCase) bool {
+	// Every write is made under the claim, so that a dispatcher whose claim ran out while it
+	// was signing never stores or broadcasts a second transaction.
+	guard := entry.Guard()
+	_, span := tracer.Start(tracing.Extract(s.Context, entry.Trace), "mint.submit",
+		trace.WithAttributes(
+			attribute.String("reward.id", entry.RewardId.Hex()),
+			attribute.Int("attempt", entry.Attempts),
+		))
+	defer span.End()
+
+	tx, reservation, err := s.mintReward(entry)
+	if gas.IsLimitExceeded(err) {
+		if !s.mintPaused.Swap(true) {
+			s.Logger.Warn("Minting paused by gas limits",
+				"error", err,
+				"reward_id", entry.RewardId.Hex(),
+				"retry_in", s.settings().pauseInterval)
+		}
+		span.SetStatus(codes.Error, "minting paused")
+		// A paused attempt does not count against the entry.
+		entry.Attempts--
+		entry.ClaimedUntil = time.Time{}
+		if err := updateUseCase.Execute(s.Context, entry, guard); err != nil {
+			s.updateFailed(entry, err)
+		}
+		return false
+	}
+	if s.mintPaused.Swap(false) {
+		s.Logger.Info("Minting resumed", "spent_today", s.gasStrategy.Spent())
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "mint failed")
+		entry.LastError = err.Error()
+		// The entry is not claimed again before its backoff, so that an outage of the node
+		// does not use up its attempts at once.
+		entry.ClaimedUntil = time.Now().Add(s.settings().outbox.retryPolicy.Backoff(entry.Attempts))
+		var events []entity.RewardEventType
+		if entry.Attempts >= s.settings().outbox.maxAttempts {
+			entry.State = entity.OutboxStateFailed
+			events = append(events, entity.RewardEventFailed)
+			s.metrics.mintsFailed.Inc()
+		}
+		s.Logger.Error("Failed to mint reward on blockchain",
+			"error", err,
+			"id", entry.Id.Hex(),
+			"reward_id", entry.RewardId.Hex(),
+			"receiver", entry.Receiver,
+			"amount", entry.Amount,
+			"attempts", entry.Attempts,
+			"retry_at", entry.ClaimedUntil,
+			"state", entry.State)
+		if err := updateUseCase.Execute(s.Context, entry, guard, events...); err != nil {
+			s.updateFailed(entry, err)
+		} else if entry.State == entity.OutboxStateFailed {
+			s.releaseCap(entry.Receiver, entry.CapDay, entry.Amount)
+		}
+		return true
+	}
+
+	// Persist the signed transaction before broadcasting it, so that a crash in between
+	// leads to a rebroadcast of the very same transaction and never to a second mint.
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		s.refundGas(reservation)
+		s.Logger.Error("Failed to encode transaction", "error", err, "id", entry.Id.Hex())
+		return true
+	}
+	reserveGas(entry, reservation)
+	entry.State = entity.OutboxStateSubmitted
+	entry.TxHash = tx.Hash().Hex()
+	entry.RawTx = hexutil.Encode(rawTx)
+	entry.SubmittedAt = time.Now()
+	entry.LastError = ""
+	span.SetAttributes(attribute.String("tx.hash", entry.TxHash))
+	if err := updateUseCase.Execute(s.Context, entry, guard, entity.RewardEventMinted); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "transaction not stored")
+		s.refundGas(reservation)
+		if errors.Is(err, entity.ErrOutboxEntryChanged) {
+			s.Logger.Warn("Outbox entry claimed by another dispatcher, not broadcasting",
+				"id", entry.Id.Hex(),
+				"tx_hash", entry.TxHash)
+			return true
+		}
+		s.metrics.dbErrors.WithLabelValues("update_outbox_entry").Inc()
+		s.Logger.Error("Failed to store signed transaction, not broadcasting",
+			"error", err,
+			"id", entry.Id.Hex(),
+			"tx_hash", entry.TxHash)
+		return true
+	}
+
+	s.metrics.mintsSubmitted.Inc()
+	if err := s.ethClient.SendTransaction(s.Context, tx); err != nil {
+		// The entry is already submitted, checkSubmittedEntries rebroadcasts it.
+		span.RecordError(err)
+		s.Logger.Warn("Failed to broadcast mint transaction, will retry",
+			"error", err,
+			"id", entry.Id.Hex(),
+			"tx_hash", entry.TxHash)
+		return true
+	}
+
+	s.Logger.Info("Reward minted on blockchain",
+		"id", entry.RewardId.Hex(),
+		"token", s.token,
+		"amount", entry.Amount,
+		"receiver", entry.Receiver,
+		"tx_hash", entry.TxHash)
+	return true
+}
+
+// checkSubmittedEntries looks up receipts for submitted transactions, rebroadcasting the
+// ones the node does not know about and replacing the ones left unconfirmed for too long.
+func (s *Service) checkSubmittedEntries() {
+	findUseCase := usecase.NewFindOutboxEntriesByStateUseCase(s.repository)
+	updateUseCase := usecase.NewUpdateOutboxEntryUseCase(s.repository)
+
+	entries, err := findUseCase.Execute(s.Context, entity.OutboxStateSubmitted)
+	if err != nil {
+		s.metrics.dbErrors.WithLabelValues("find_outbox_entries").Inc()
+		s.Logger.Error("Failed to load submitted outbox entries", "error", err)
+		return
+	}
+
+	stuck := 0
+	defer func() { s.metrics.mintsStuck.Set(float64(stuck)) }()
+	for _, entry := range entries {
+		if s.Context.Err() != nil {
+			return
+		}
+
+		guard := entry.Guard()
+		var events []entity.RewardEventType
+		// gasCost is what the transaction cost, once it is known to be mined or to never be.
+		var gasCost *big.Int
+		reserved := entry.GasReserved
+		receipt, err := s.transactionReceipt(entry)
+		switch {
+		case err == nil:
+			_, span := tracer.Start(tracing.Extract(s.Context, entry.Trace), "mint.confirm",
+				trace.WithAttributes(
+					attribute.String("reward.id", entry.RewardId.Hex()),
+					attribute.String("tx.hash", entry.TxHash),
+					attribute.Int64("block", receipt.BlockNumber.Int64()),
+					attribute.Int64("gas_used", int64(receipt.GasUsed)),
+				))
+			if receipt.EffectiveGasPrice != nil {
+				gasCost = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+				s.metrics.gasSpent.Add(weiToEth(gasCost))
+			}
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				entry.State = entity.OutboxStateConfirmed
+				events = append(events, entity.RewardEventConfirmed)
+				s.metrics.mintsConfirmed.Inc()
+				s.Logger.Info("Mint transaction confirmed",
+					"id", entry.RewardId.Hex(),
+					"tx_hash", entry.TxHash,
+					"block", receipt.BlockNumber)
+			} else {
+				entry.State = entity.OutboxStateFailed
+				entry.LastError = "transaction reverted"
+				events = append(events, entity.RewardEventFailed)
+				s.metrics.mintsFailed.Inc()
+				span.SetStatus(codes.Error, entry.LastError)
+				s.Logger.Error("Mint transaction reverted",
+					"id", entry.RewardId.Hex(),
+					"tx_hash", entry.TxHash,
+					"block", receipt.BlockNumber)
+			}
+			span.End()
+		case errors.Is(err, ethereum.NotFound):
+			if s.isStuck(entry) {
+				if s.replaceTransaction(entry) {
+					continue
+				}
+				stuck++
+			}
+			if !s.rebroadcast(entry) {
+				continue
+			}
+			if entry.State == entity.OutboxStatePendingMint {
+				// The nonce went to another transaction, none of the entry was mined.
+				gasCost = new(big.Int)
+				entry.GasReserved = nil
+			}
+			if entry.State == entity.OutboxStateFailed {
+				events = append(events, entity.RewardEventFailed)
+				s.metrics.mintsFailed.Inc()
+			}
+		default:
+			s.Logger.Warn("Failed to get mint transaction receipt",
+				"error", err,
+				"tx_hash", entry.TxHash)
+			continue
+		}
+
+		if err := updateUseCase.Execute(s.Context, entry, guard, events...); err != nil {
+			s.updateFailed(entry, err)
+			continue
+		}
+		if entry.State == entity.OutboxStateFailed {
+			s.releaseCap(entry.Receiver, entry.CapDay, entry.Amount)
+		}
+		if gasCost != nil {
+			s.settleGas(entry, reserved, gasCost)
+		}
+	}
+}
+
+// updateFailed reports an outbox entry that was not saved. An entry changed by another
+// dispatcher is left to it.
+func (s *Service) updateFailed(entry *entity.OutboxEntry, err error) {
+	if errors.Is(err, entity.ErrOutboxEntryChanged) {
+		s.Logger.Warn("Outbox entry changed by another dispatcher", "id", entry.Id.Hex())
+		return
+	}
+	s.metrics.dbErrors.WithLabelValues("update_outbox_entry").Inc()
+	s.Logger.Error("Failed to update outbox entry", "error", err, "id", entry.Id.Hex())
+}
+
+// transactionReceipt returns the receipt of the transaction of an entry or, when there is
+// none, of one of the transactions it replaced, which then becomes the transaction of the
+// entry.
+func (s *Service) transactionReceipt(entry *entity.OutboxEntry) (*types.Receipt, error) {
+	receipt, err := s.ethClient.TransactionReceipt(s.Context, common.HexToHash(entry.TxHash))
+	if !errors.Is(err, ethereum.NotFound) {
+		return receipt, err
+	}
+	for _, txHash := range entry.ReplacedTxs {
+		replaced, err := s.ethClient.TransactionReceipt(s.Context, common.HexToHash(txHash))
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.Logger.Info("Replaced mint transaction was mined",
+			"id", entry.RewardId.Hex(),
+			"tx_hash", txHash,
+			"replacement", entry.TxHash)
+		entry.TxHash = txHash
+		return replaced, nil
+	}
+	return nil, err
+}
+
+// isStuck reports whether the transaction of a submitted entry went unconfirmed for longer
+// than the replacement timeout.
+func (s *Service) isStuck(entry *entity.OutboxEntry) bool {
+	replaceAfter := s.settings().outbox.replaceAfter
+	if replaceAfter <= 0 {
+		return false
+	}
+	submittedAt := entry.SubmittedAt
+	if submittedAt.IsZero() {
+		// Entries submitted before the submission time was recorded.
+		submittedAt = entry.UpdatedAt
+	}
+	return time.Since(submittedAt) >= replaceAfter
+}
+
+// replaceTransaction signs the transaction of an entry again with the same nonce and a
+// higher fee, stores the replacement and broadcasts it. It returns false when no
+// replacement was stored, which leaves the entry stuck until the next scan.
+func (s *Service) replaceTransaction(entry *entity.OutboxEntry) bool {
+	_, span := tracer.Start(tracing.Extract(s.Context, entry.Trace), "mint.replace",
+		trace.WithAttributes(
+			attribute.String("reward.id", entry.RewardId.Hex()),
+			attribute.String("tx.hash", entry.TxHash),
+		))
+	defer span.End()
+
+	previous, err := storedTransaction(entry)
+	if err != nil {
+		return false
+	}
+
+	txOpts := *s.txOpts // clone
+	txOpts.Context = s.Context
+	reservation, err := s.gasStrategy.Bump(s.Context, &txOpts, previous)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "not replaced")
+		s.Logger.Warn("Mint transaction stuck and not replaced",
+			"error", err,
+			"id", entry.RewardId.Hex(),
+			"tx_hash", entry.TxHash,
+			"submitted_at", entry.SubmittedAt)
+		return false
+	}
+
+	tx, err := txOpts.Signer(txOpts.From, types.NewTx(&types.DynamicFeeTx{
+		ChainID:   previous.ChainId(),
+		Nonce:     previous.Nonce(),
+		GasTipCap: txOpts.GasTipCap,
+		GasFeeCap: txOpts.GasFeeCap,
+		Gas:       txOpts.GasLimit,
+		To:        previous.To(),
+		Value:     previous.Value(),
+		Data:      previous.Data(),
+	}))
+	var rawTx []byte
+	if err == nil {
+		rawTx, err = tx.MarshalBinary()
+	}
+	if err != nil {
+		s.refundGas(reservation)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "not replaced")
+		s.Logger.Error("Failed to sign replacement mint transaction", "error", err, "id", entry.RewardId.Hex())
+		return false
+	}
+
+	// As in submitEntry, the replacement is stored before it is broadcast.
+	previousHash := entry.TxHash
+	reserved := entry.GasReserved
+	reserveGas(entry, reservation)
+	replaceUseCase := usecase.NewReplaceOutboxTransactionUseCase(s.repository)
+	if err := replaceUseCase.Execute(s.Context, entry, tx.Hash().Hex(), hexutil.Encode(rawTx)); err != nil {
+		entry.GasReserved = reserved
+		s.refundGas(reservation)
+		if errors.Is(err, entity.ErrOutboxEntryChanged) {
+			// Another dispatcher replaced or settled it first.
+			return true
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "replacement not stored")
+		s.metrics.dbErrors.WithLabelValues("replace_outbox_transaction").Inc()
+		s.Logger.Error("Failed to store replacement mint transaction, not broadcasting",
+			"error", err,
+			"id", entry.Id.Hex(),
+			"tx_hash", tx.Hash().Hex())
+		return false
+	}
+	span.SetAttributes(attribute.String("tx.replacement", entry.TxHash))
+	s.metrics.mintsReplaced.Inc()
+
+	if err := s.ethClient.SendTransaction(s.Context, tx); err != nil {
+		span.RecordError(err)
+		s.Logger.Warn("Failed to broadcast replacement mint transaction, will retry",
+			"error", err,
+			"id", entry.Id.Hex(),
+			"tx_hash", entry.TxHash)
+		return true
+	}
+
+	s.Logger.Info("Stuck mint transaction replaced",
+		"id", entry.RewardId.Hex(),
+		"tx_hash", previousHash,
+		"replacement", entry.TxHash,
+		"gas_fee_cap", txOpts.GasFeeCap,
+		"gas_tip_cap", txOpts.GasTipCap)
+	return true
+}
+
+// storedTransaction decodes the signed transaction of an entry.
+func storedTransaction(entry *entity.OutboxEntry) (*types.Transaction, error) {
+	rawTx, err := hexutil.Decode(entry.RawTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored transaction: %w", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return nil, fmt.Errorf("invalid stored transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// rebroadcast sends a stored transaction again. It returns true when the entry changed and
+// must be saved: that happens when the nonce was taken by a transaction that is not one of
+// the entry, in which case none of them can be mined and the entry goes back to pending.
+func (s *Service) rebroadcast(entry *entity.OutboxEntry) bool {
+	tx, err := storedTransaction(entry)
+	if err != nil {
+		entry.State = entity.OutboxStateFailed
+		entry.LastError = err.Error()
+		return true
+	}
+
+	err = s.ethClient.SendTransaction(s.Context, tx)
+	switch {
+	case err == nil:
+		s.Logger.Info("Mint transaction rebroadcast", "id", entry.RewardId.Hex(), "tx_hash", entry.TxHash)
+		return false
+	case strings.Contains(err.Error(), "already known"):
+		return false
+	case strings.Contains(err.Error(), "nonce too low"):
+		// Most of the time the nonce went to the transaction of the entry, or to one it
+		// replaced, mined since its receipt was looked up. The next scan settles it.
+		if _, err := s.transactionReceipt(entry); !errors.Is(err, ethereum.NotFound) {
+			if err != nil {
+				s.Logger.Warn("Failed to get mint transaction receipt", "error", err, "tx_hash", entry.TxHash)
+			}
+			return false
+		}
+		s.Logger.Warn("Mint transaction replaced, minting again",
+			"id", entry.RewardId.Hex(),
+			"tx_hash", entry.TxHash)
+		entry.State = entity.OutboxStatePendingMint
+		entry.TxHash = ""
+		entry.RawTx = ""
+		entry.SubmittedAt = time.Time{}
+		entry.ReplacedTxs = nil
+		entry.LastError = err.Error()
+		entry.ClaimedUntil = time.Time{}
+		return true
+	default:
+		s.Logger.Warn("Failed to rebroadcast mint transaction",
+			"error", err,
+			"tx_hash", entry.TxHash)
+		return false
+	}
+}
+
+// mintReward builds and signs the mint transaction of an outbox entry without sending it.
+// The returned reservation is made on the gas budget and must be refunded if the
+// transaction is not sent.
+func (s *Service) mintReward(entry *entity.OutboxEntry) (*types.Transaction, *gas.Reservation, error) {
+	tokenAddr := common.HexToAddress(entry.Token)
+	receiverAddr, err := ethutil.ParseAddress(entry.Receiver)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid receiver: %w", err)
+	}
+
+	contract, err := rewardtoken.NewRewardToken(tokenAddr, s.ethClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create contract: %w", err)
+	}
+
+	amount := new(big.Int)
+	if _, ok := amount.SetString(entry.Amount, 10); !ok {
+		return nil, nil, fmt.Errorf("invalid amount format: %s", entry.Amount)
+	}
+
+	txOpts := *s.txOpts // clone
+	txOpts.Context = s.Context
+	txOpts.NoSend = true
+	nonce, err := s.ethClient.PendingNonceAt(s.Context, txOpts.From)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	txOpts.Nonce = new(big.Int).SetUint64(nonce)
+
+	reservation, err := s.gasStrategy.Apply(s.Context, &txOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to apply gas strategy: %w", err)
+	}
+
+	tx, err := contract.Mint(&txOpts, receiverAddr, amount)
+	if err != nil {
+		s.refundGas(reservation)
+		return nil, nil, fmt.Errorf("failed to mint: %w", err)
+	}
+
+	return tx, reservation, nil
+}
@@ -0,0 +1,52 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// resolvePayout replaces the receiver and amount a reading claims with the ones registered
+// for its sensor. Readings the registry does not pay are quarantined and reported true.
+func (s *Service) resolvePayout(ctx context.Context, msg *source.Message, input *usecase.CreateRewardInputDTO, settings *settings) (bool, int, error) {
+	var payout *usecase.ResolvePayoutOutputDTO
+	resolvePayout := usecase.NewResolvePayoutUseCase(s.repository, settings.payouts)
+	attempts, err := settings.retryPolicy.Do(ctx, func(int) error {
+		var err error
+		payout, err = resolvePayout.Execute(ctx, input)
+		if errors.Is(err, usecase.ErrPayoutRejected) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("resolve_payout").Inc()
+		}
+		return err
+	})
+	if err == nil {
+		input.Receiver = payout.Receiver
+		input.Amount = payout.Amount
+		return false, attempts, nil
+	}
+	if !errors.Is(err, usecase.ErrPayoutRejected) {
+		s.Logger.Error("Failed to resolve payout", "error", err, "sensor_id", input.SensorId, "attempts", attempts)
+		return false, attempts, fmt.Errorf("failed to resolve payout: %w", err)
+	}
+
+	reason := err.Error()
+	attempts, err = s.quarantineReading(ctx, msg, input, &usecase.QuarantineReadingInputDTO{
+		ReadingId: input.ReadingId,
+		Check:     entity.QuarantinePayout,
+		Reason:    reason,
+	}, settings)
+	if err != nil {
+		return false, attempts, err
+	}
+	s.metrics.payoutsRejected.Inc()
+	s.Logger.Warn("Reading quarantined", "reason", reason, "reading_id", input.ReadingId, "sensor_id", input.SensorId)
+	return true, attempts, nil
+}
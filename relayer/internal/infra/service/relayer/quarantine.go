@@ -0,0 +1,115 @@
+package relayer
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+const (
+	// quarantineLease is how long a released reading waits before it is processed again,
+	// when processing it failed.
+	quarantineLease = 10 * time.Minute
+	// releaseBatch is the most released readings processed on a tick.
+	releaseBatch = 100
+)
+
+// releaseQuarantine processes the quarantined readings that are due again: the ones an
+// operator released, and the rewards held over a daily cap once the next UTC day starts.
+func (s *Service) releaseQuarantine() error {
+	claimUseCase := usecase.NewClaimQuarantinedReadingUseCase(s.repository)
+	for range releaseBatch {
+		if s.Context.Err() != nil {
+			return nil
+		}
+		held, err := claimUseCase.Execute(s.Context, time.Now(), quarantineLease)
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("claim_quarantined_reading").Inc()
+			return err
+		}
+		if held == nil {
+			return nil
+		}
+		s.releaseReading(held)
+	}
+	return nil
+}
+
+// releaseReading takes a quarantined reading back into the pipeline at the check that held
+// it, having passed the ones before. It leaves the quarantine unless it is held again, and is
+// retried after quarantineLease when processing fails.
+func (s *Service) releaseReading(held *entity.QuarantinedReading) {
+	ctx, span := tracer.Start(s.Context, "quarantine.release",
+		trace.WithAttributes(
+			attribute.String("reading.id", held.ReadingId),
+			attribute.String("quarantine.check", string(held.Check)),
+		))
+	defer span.End()
+
+	// The reading is checked against the time it is released, so that a reward held over
+	// the cap of a day counts toward the cap of the day it is paid.
+	msg := &source.Message{
+		Id:         held.ReadingId,
+		Topic:      held.Topic,
+		Value:      []byte(held.Payload),
+		ReceivedAt: time.Now(),
+	}
+	result := RewardResult{
+		MessageId: held.ReadingId,
+		Attempts:  1,
+		Message:   msg,
+	}
+
+	var input usecase.CreateRewardInputDTO
+	if err := json.Unmarshal(msg.Value, &input); err != nil {
+		span.RecordError(err)
+		s.Logger.Error("Failed to unmarshal quarantined reading", "error", err, "reading_id", held.ReadingId)
+		return
+	}
+	input.Token = s.token
+	input.ReadingId = held.ReadingId
+
+	settings := s.settings()
+	switch held.Check {
+	case entity.QuarantinePayout:
+		result = s.rewardReading(ctx, span, msg, &input, nil, settings, result)
+	case entity.QuarantineReceiver:
+		input.Receiver = held.Receiver
+		input.Amount = held.Amount
+		result = s.payReward(ctx, span, msg, &input, nil, settings, result)
+	default:
+		span.SetStatus(codes.Error, "not releasable")
+		s.Logger.Warn("Quarantined reading cannot be released", "reading_id", held.ReadingId, "check", held.Check)
+		return
+	}
+
+	outcome := rewardOutcome(result)
+	s.metrics.readingsReleased.WithLabelValues(outcome).Inc()
+	switch {
+	case !result.Success:
+		s.Logger.Error("Failed to process released reading, will retry",
+			"error", result.Error,
+			"reading_id", held.ReadingId,
+			"retry_in", quarantineLease)
+		return
+	case result.Quarantined:
+		s.Logger.Info("Released reading held again", "reading_id", held.ReadingId)
+		return
+	}
+
+	deleteUseCase := usecase.NewDeleteQuarantinedReadingUseCase(s.repository)
+	if err := deleteUseCase.Execute(s.Context, held.ReadingId); err != nil {
+		// It is found paid next time, and deleted then.
+		s.metrics.dbErrors.WithLabelValues("delete_quarantined_reading").Inc()
+		s.Logger.Error("Failed to delete released reading", "error", err, "reading_id", held.ReadingId)
+		return
+	}
+	s.Logger.Info("Quarantined reading released", "reading_id", held.ReadingId, "outcome", outcome)
+}
@@ -0,0 +1,41 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// recordReading stores the values of a verified reading before anything decides its reward,
+// so that withheld, held and rejected readings are kept too. Data that does not parse is
+// left to the quality checks, which reject it, and returns no reading.
+func (s *Service) recordReading(ctx context.Context, msg *source.Message, input *usecase.CreateRewardInputDTO, settings *settings) (*entity.Reading, int, error) {
+	recordReading := usecase.NewRecordReadingUseCase(s.repository)
+	recordReading.Standard = s.aqiStandard
+
+	var output *usecase.RecordReadingOutputDTO
+	attempts, err := settings.retryPolicy.Do(ctx, func(int) error {
+		var err error
+		output, err = recordReading.Execute(ctx, input, msg.ReceivedAt)
+		if errors.Is(err, entity.ErrInvalidReading) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("record_reading").Inc()
+		}
+		return err
+	})
+	if errors.Is(err, entity.ErrInvalidReading) {
+		return nil, attempts, nil
+	}
+	if err != nil {
+		s.Logger.Error("Failed to record reading", "error", err, "reading_id", input.ReadingId, "attempts", attempts)
+		return nil, attempts, fmt.Errorf("failed to record reading: %w", err)
+	}
+	return output.Reading, attempts, nil
+}
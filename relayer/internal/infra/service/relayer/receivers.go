@@ -0,0 +1,94 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/city.fun/relayer/configs"
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// receiverDenylist returns the configured addresses rewards are never paid to.
+func receiverDenylist() (map[common.Address]bool, error) {
+	addresses, err := configs.GetReceiverDenylist()
+	if err != nil && !errors.Is(err, configs.ErrNotDefined) {
+		return nil, err
+	}
+	return usecase.ParseDenylist(addresses)
+}
+
+// checkReceiver holds back a reward that the receiver policy does not let be minted: it is
+// quarantined instead, and reported true. When the amount is reserved against the daily cap
+// of the receiver, the day is set on input, for releaseCap.
+func (s *Service) checkReceiver(ctx context.Context, msg *source.Message, input *usecase.CreateRewardInputDTO, settings *settings) (bool, int, error) {
+	checkReceiver := usecase.NewCheckReceiverUseCase(s.repository, settings.receivers, settings.denylist, settings.dailyCap)
+	attempts, err := settings.retryPolicy.Do(ctx, func(int) error {
+		output, err := checkReceiver.Execute(ctx, input, msg.ReceivedAt)
+		if err == nil && output.Reserved != nil {
+			input.CapDay = output.Day
+		}
+		if errors.Is(err, usecase.ErrRewardHeld) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("check_receiver").Inc()
+		}
+		return err
+	})
+	if err == nil {
+		return false, attempts, nil
+	}
+	if !errors.Is(err, usecase.ErrRewardHeld) {
+		s.Logger.Error("Failed to check receiver", "error", err, "receiver", input.Receiver, "attempts", attempts)
+		return false, attempts, fmt.Errorf("failed to check receiver: %w", err)
+	}
+
+	hold := &usecase.QuarantineReadingInputDTO{
+		ReadingId: input.ReadingId,
+		Check:     entity.QuarantineReceiver,
+		Reason:    err.Error(),
+		Receiver:  input.Receiver,
+		Amount:    input.Amount,
+	}
+	var label string
+	switch {
+	case errors.Is(err, usecase.ErrReceiverUnlisted):
+		label = "unlisted"
+	case errors.Is(err, usecase.ErrDailyCapExceeded):
+		label = "daily_cap"
+		// The cap is checked again once the next UTC day starts.
+		hold.ReleaseAt = msg.ReceivedAt.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	default:
+		label = "denied"
+	}
+	reason := hold.Reason
+	attempts, err = s.quarantineReading(ctx, msg, input, hold, settings)
+	if err != nil {
+		return false, attempts, err
+	}
+	s.metrics.rewardsHeld.WithLabelValues(label).Inc()
+	s.Logger.Warn("Reward held", "reason", reason, "reading_id", input.ReadingId, "receiver", input.Receiver)
+	return true, attempts, nil
+}
+
+// releaseCap gives back to the daily cap of a receiver an amount reserved on day and not
+// paid. Nothing was reserved when day is zero. A failure only leaves the cap lower than it
+// should be for the day, so it is logged and not retried.
+func (s *Service) releaseCap(receiver string, day time.Time, amount string) {
+	if day.IsZero() {
+		return
+	}
+	releaseUseCase := usecase.NewReleaseReceiverAmountUseCase(s.repository)
+	if err := releaseUseCase.Execute(s.Context, common.HexToAddress(receiver).Hex(), day, amount); err != nil {
+		s.metrics.dbErrors.WithLabelValues("release_receiver_amount").Inc()
+		s.Logger.Error("Failed to release daily cap", "error", err, "receiver", receiver, "amount", amount, "day", day)
+		return
+	}
+	s.Logger.Debug("Daily cap released", "receiver", receiver, "amount", amount, "day", day)
+}
@@ -3,53 +3,85 @@ package relayer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/big"
+	"net/http"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/henriquemarlon/city.fun/relayer/configs"
 	"github.com/henriquemarlon/city.fun/relayer/configs/auth"
 	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/service/relayer/handler"
 	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
-	"github.com/henriquemarlon/city.fun/relayer/pkg/contracts/rewardtoken"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/alert"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/aqi"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/gas"
 	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
 	"github.com/henriquemarlon/city.fun/relayer/pkg/service"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/sink"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/tracing"
 	"github.com/henriquemarlon/city.fun/relayer/pkg/workerpool"
 )
 
+var tracer = tracing.Tracer("relayer")
+
 type RewardResult struct {
-	MessageId string
-	Success   bool
-	Error     error
-	Output    *usecase.CreateRewardOutputDTO
-	KafkaMsg  *ckafka.Message
+	MessageId   string
+	Success     bool
+	Error       error
+	Attempts    int
+	Output      *usecase.CreateRewardOutputDTO
+	Message     *source.Message
+	Withheld    bool // the reading failed the data quality checks and was not rewarded
+	Quarantined bool // the reading is not signed by its device, not paid by the registry or held by the receiver policy
 }
 
 type Service struct {
 	service.Service
-	token         common.Address
-	kafkaConsumer *kafka.KafkaConsumer
-	repository    repository.Repository
-	workerPool    workerpool.WorkerPool
-	jobChan       chan workerpool.Job
-	txChan        chan *usecase.CreateRewardOutputDTO
-	sigintChan    chan struct{}
-	wg            sync.WaitGroup
-	ethClient     *ethclient.Client
-	txOpts        *bind.TransactOpts
+	token           common.Address
+	source          source.MessageSource
+	receiverDone    chan struct{}
+	sink            sink.Sink // dead letters and reward events
+	eventsSignal    chan struct{}
+	repository      repository.Repository
+	workerPool      workerpool.WorkerPool
+	jobChan         chan workerpool.Job
+	outboxSignal    chan struct{}
+	sigintChan      chan struct{}
+	wg              sync.WaitGroup
+	ethClient       *ethclient.Client
+	txOpts          *bind.TransactOpts
+	gasStrategy     *gas.Strategy
+	mintPaused      atomic.Bool
+	config          configs.RelayerConfig // configuration in effect, updated by Reload
+	live            atomic.Pointer[settings]
+	metrics         *metrics
+	aqiStandard     aqi.Standard
+	alerts          *alert.Evaluator // nil without alert rules
+	alertPublishers []alert.Publisher
+	alertQueues     []chan alert.Event // one per publisher
 }
 
 type CreateInfo struct {
 	service.CreateInfo
-	Config        configs.RelayerConfig
-	KafkaConsumer *kafka.KafkaConsumer
-	Repository    repository.Repository
-	EthClient     *ethclient.Client
+	Config          configs.RelayerConfig
+	Source          source.MessageSource
+	Sink            sink.Sink
+	Repository      repository.Repository
+	EthClient       *ethclient.Client
+	AlertRules      []alert.Rule
+	AlertPublishers []alert.Publisher
 }
 
 func Create(ctx context.Context, createInfo *CreateInfo) (*Service, error) {
@@ -71,9 +103,65 @@ func Create(ctx context.Context, createInfo *CreateInfo) (*Service, error) {
 		return nil, fmt.Errorf("repository on relayer service create is nil")
 	}
 
-	s.kafkaConsumer = createInfo.KafkaConsumer
-	if s.kafkaConsumer == nil {
-		return nil, fmt.Errorf("kafka consumer on relayer service create is nil")
+	s.source = createInfo.Source
+	if s.source == nil {
+		return nil, fmt.Errorf("message source on relayer service create is nil")
+	}
+
+	adminToken, err := configs.GetAdminToken()
+	if err != nil {
+		return nil, fmt.Errorf("admin token: %w", err)
+	}
+	if err := configs.CheckToken(adminToken.Value); err != nil {
+		return nil, fmt.Errorf("admin token: %w", err)
+	}
+	enrollmentToken, err := configs.GetEnrollmentToken()
+	if err != nil && !errors.Is(err, configs.ErrNotDefined) {
+		return nil, err
+	}
+	if enrollmentToken.Value != "" {
+		if err := configs.CheckToken(enrollmentToken.Value); err != nil {
+			return nil, fmt.Errorf("enrollment token: %w", err)
+		}
+		if enrollmentToken.Value == adminToken.Value {
+			return nil, fmt.Errorf("enrollment token must differ from the admin token")
+		}
+	}
+
+	if _, ok := s.source.(kafkaStatsSource); ok && s.ServeMux != nil {
+		s.ServeMux.Handle("/kafka/stats", http.HandlerFunc(s.KafkaStatsHandler))
+	}
+
+	s.aqiStandard, err = aqi.ParseStandard(createInfo.Config.AqiStandard)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ServeMux != nil {
+		handler.NewRewardHandlers(s.repository, s.Logger).Register(s.ServeMux)
+		handler.NewReadingHandlers(s.repository, s.aqiStandard, s.Logger).Register(s.ServeMux)
+		handler.NewAdminHandlers(s.repository, adminToken.Value, s.Logger).Register(s.ServeMux)
+		if enrollmentToken.Value != "" {
+			handler.NewEnrollmentHandlers(s.repository, enrollmentToken.Value, s.Logger).Register(s.ServeMux)
+		}
+	}
+
+	if len(createInfo.AlertRules) > 0 {
+		if createInfo.Config.AlertZoneSize <= 0 {
+			return nil, fmt.Errorf("alert zone size must be positive")
+		}
+		s.alerts = alert.NewEvaluator(createInfo.AlertRules)
+	}
+	s.alertPublishers = createInfo.AlertPublishers
+	if s.alerts != nil {
+		for range s.alertPublishers {
+			s.alertQueues = append(s.alertQueues, make(chan alert.Event, alertBuffer))
+		}
+	}
+
+	s.sink = createInfo.Sink
+	if s.sink == nil {
+		return nil, fmt.Errorf("message sink on relayer service create is nil")
 	}
 
 	s.ethClient = createInfo.EthClient
@@ -89,76 +177,148 @@ func Create(ctx context.Context, createInfo *CreateInfo) (*Service, error) {
 		return nil, fmt.Errorf("chainId mismatch: network %d != provided %d", chainId.Uint64(), createInfo.Config.BlockchainId)
 	}
 
-	s.txOpts, err = auth.GetTransactOpts(chainId)
+	s.txOpts, err = auth.GetTransactOpts(ctx, chainId)
 	if err != nil {
 		return nil, err
 	}
 
+	s.gasStrategy = gas.NewStrategy(s.ethClient, gasConfig(createInfo.Config), gasLedger{repository: s.repository})
+
 	s.token = createInfo.Config.RewardToken
 	if s.token == (common.Address{}) {
 		return nil, fmt.Errorf("token address on relayer service create is nil")
 	}
 
+	ranges, err := qualityRanges()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseSignaturePolicy(createInfo.Config.DeviceSignatures); err != nil {
+		return nil, err
+	}
+	if _, err := usecase.ParsePayoutMode(createInfo.Config.Payouts); err != nil {
+		return nil, err
+	}
+	if _, err := usecase.ParseReceiverPolicy(createInfo.Config.Receivers); err != nil {
+		return nil, err
+	}
+	denylist, err := receiverDenylist()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	s.config = createInfo.Config
+	s.live.Store(newSettings(fmt.Sprintf("%s-%s-%d", createInfo.Name, hostname, os.Getpid()), createInfo.Config, ranges, denylist))
+
 	s.jobChan = make(chan workerpool.Job, 100)
-	s.txChan = make(chan *usecase.CreateRewardOutputDTO, 100)
+	s.outboxSignal = make(chan struct{}, 1)
+	s.eventsSignal = make(chan struct{}, 1)
 	s.sigintChan = make(chan struct{})
 
+	setRetention := usecase.NewSetHistoryRetentionUseCase(s.repository)
+	if err := setRetention.Execute(ctx, createInfo.Config.HistoryRetention); err != nil {
+		return nil, err
+	}
+
+	s.metrics = newMetrics(s)
+	registerHealthChecks(s, createInfo.Config)
+
 	processFunc := func(ctx context.Context, job workerpool.Job) workerpool.Result {
-		msg := job.(*ckafka.Message)
+		msg := job.(*source.Message)
+		defer func() {
+			s.metrics.processingDuration.Observe(time.Since(msg.ReceivedAt).Seconds())
+		}()
+
+		// Continue the trace started by the producer of the reading, if it sent one.
+		ctx, span := tracer.Start(tracing.Extract(ctx, msg.Headers), "message.consume",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.message.id", msg.Id),
+				attribute.String("messaging.destination.name", msg.Topic),
+				attribute.Int("messaging.destination.partition.id", int(msg.Partition)),
+			))
+		defer span.End()
 
 		result := RewardResult{
-			MessageId: string(msg.Key),
+			MessageId: msg.Id,
 			Success:   false,
-			KafkaMsg:  msg,
+			Attempts:  1,
+			Message:   msg,
 		}
 
 		var input usecase.CreateRewardInputDTO
 		if err := json.Unmarshal(msg.Value, &input); err != nil {
 			result.Error = fmt.Errorf("failed to unmarshal message: %w", err)
+			span.RecordError(result.Error)
+			span.SetStatus(codes.Error, "invalid message")
 			s.Logger.Error("Failed to unmarshal message",
 				"error", err,
 				"message", string(msg.Value),
-				"partition", msg.TopicPartition.Partition,
-				"offset", msg.TopicPartition.Offset)
+				"topic", msg.Topic,
+				"partition", msg.Partition,
+				"offset", msg.Offset)
 			return result
 		}
 
-		input.Token = s.token
+		settings := s.settings()
 
-		createRewardUseCase := usecase.NewCreateRewardUseCase(s.repository)
-		output, err := createRewardUseCase.Execute(ctx, &input)
+		// Readings are verified as they were sent, before anything is filled in.
+		quarantined, attempts, err := s.verifyReading(ctx, msg, &input, settings)
 		if err != nil {
-			result.Error = fmt.Errorf("failed to create reward: %w", err)
-			s.Logger.Error("Failed to save reward to DB",
-				"error", err,
-				"receiver", input.Receiver,
-				"amount", input.Amount)
+			result.Attempts = attempts
+			result.Error = err
+			span.RecordError(result.Error)
+			span.SetStatus(codes.Error, "reading not verified")
+			return result
+		}
+		if quarantined {
+			result.Success = true
+			result.Quarantined = true
 			return result
 		}
 
-		s.Logger.Info("Reward processed in DB",
-			"id", output.Id.Hex(),
-			"receiver", output.Receiver,
-			"amount", output.Amount,
-			"latitude", output.Latitude,
-			"longitude", output.Longitude)
+		input.Token = s.token
+		if input.ReadingId == "" {
+			// Producers that do not send a reading id are deduplicated by the message id,
+			// which stays the same when a message is redelivered.
+			input.ReadingId = msg.Id
+		}
+		result.MessageId = input.ReadingId
+		span.SetAttributes(attribute.String("reading.id", input.ReadingId))
 
-		select {
-		case s.txChan <- output:
-			s.Logger.Debug("Transaction queued for blockchain", "id", output.Id.Hex())
-		case <-ctx.Done():
-			s.Logger.Warn("Context cancelled, transaction not queued", "id", output.Id.Hex())
+		// Replayed, stale and out of sequence readings are rejected before they are stored,
+		// so that they never reach the history, the AQI or the quality checks.
+		attempts, err = s.checkReplay(ctx, msg, &input, settings)
+		if err != nil {
+			result.Attempts = attempts
+			result.Error = err
+			span.RecordError(result.Error)
+			span.SetStatus(codes.Error, "reading replayed")
+			return result
 		}
 
-		result.Success = true
-		result.Output = output
+		// Every verified reading is stored, whatever becomes of its reward.
+		reading, attempts, err := s.recordReading(ctx, msg, &input, settings)
+		if err != nil {
+			result.Attempts = attempts
+			result.Error = err
+			span.RecordError(result.Error)
+			span.SetStatus(codes.Error, "reading not recorded")
+			return result
+		}
 
-		return result
+		return s.rewardReading(ctx, span, msg, &input, reading, settings, result)
 	}
 
 	config := workerpool.Config{
-		WorkerCount: 5,
+		WorkerCount: int(createInfo.Config.WorkerCount),
 		Logger:      s.Logger,
+		// Messages of a partition are processed one at a time and in order, which keeps the
+		// committed offset close to the processed ones.
+		PartitionFunc: func(job workerpool.Job) int {
+			return int(job.(*source.Message).Partition)
+		},
 	}
 	s.workerPool = workerpool.New(processFunc, config)
 
@@ -180,15 +340,33 @@ func (s *Service) Ready() bool {
 	if s.workerPool == nil {
 		return false
 	}
-	return s.workerPool.IsRunning()
-}
-
-func (s *Service) Reload() []error {
-	return nil
+	return s.workerPool.IsRunning() && !s.mintPaused.Load()
 }
 
+// Tick runs the maintenance of the relayer. See maintenance.go.
 func (s *Service) Tick() []error {
-	return nil
+	var errs []error
+	for _, task := range []func() error{
+		s.refreshWalletBalance,
+		s.checkPendingReceipts,
+		s.compactOutbox,
+		s.downsampleHistory,
+		s.releaseQuarantine,
+	} {
+		if err := task(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if statsSource, ok := s.source.(kafkaStatsSource); ok {
+		if stats := statsSource.Stats(); stats != nil {
+			s.Logger.Debug("Kafka consumer",
+				"lag", stats.Lag,
+				"messages_per_second", stats.MessagesPerSecond,
+				"bytes_per_second", stats.BytesPerSecond)
+		}
+	}
+	return errs
 }
 
 func (s *Service) Serve() error {
@@ -198,13 +376,22 @@ func (s *Service) Serve() error {
 	}
 
 	s.wg.Add(1)
-	go s.processBlockchainTransactions()
+	go s.dispatchOutbox()
+
+	s.wg.Add(1)
+	go s.publishEvents()
+
+	for i, queue := range s.alertQueues {
+		s.wg.Add(1)
+		go s.publishAlerts(s.alertPublishers[i], queue)
+	}
 
 	s.wg.Add(1)
 	go s.processWorkerResults(resultChan)
 
+	s.receiverDone = make(chan struct{})
 	s.wg.Add(1)
-	go s.consumeKafkaMessages()
+	go s.receiveMessages()
 
 	return s.Service.Serve()
 }
@@ -226,21 +413,20 @@ func (s *Service) processWorkerResults(resultChan <-chan workerpool.Result) {
 				continue
 			}
 
-			if rewardResult.Success && rewardResult.KafkaMsg != nil {
-				if err := s.kafkaConsumer.CommitMessage(rewardResult.KafkaMsg); err != nil {
-					s.Logger.Error("Failed to commit Kafka offset",
+			if rewardResult.Success && rewardResult.Message != nil {
+				outcome := rewardOutcome(rewardResult)
+				s.metrics.messagesConsumed.WithLabelValues(rewardResult.Message.Topic, outcome).Inc()
+				if err := s.source.Ack(s.Context, rewardResult.Message); err != nil {
+					s.Logger.Error("Failed to acknowledge message",
 						"error", err,
-						"partition", rewardResult.KafkaMsg.TopicPartition.Partition,
-						"offset", rewardResult.KafkaMsg.TopicPartition.Offset)
+						"message_id", rewardResult.Message.Id)
 				} else {
-					s.Logger.Debug("Kafka offset committed after successful DB save",
-						"id", rewardResult.Output.Id.Hex(),
-						"partition", rewardResult.KafkaMsg.TopicPartition.Partition,
-						"offset", rewardResult.KafkaMsg.TopicPartition.Offset)
+					s.Logger.Debug("Message acknowledged",
+						"outcome", outcome,
+						"message_id", rewardResult.Message.Id)
 				}
-			} else {
-				s.Logger.Warn("Worker processing failed, not committing offset",
-					"error", rewardResult.Error)
+			} else if rewardResult.Message != nil {
+				s.handleFailedMessage(rewardResult)
 			}
 
 		case <-s.sigintChan:
@@ -253,124 +439,148 @@ func (s *Service) processWorkerResults(resultChan <-chan workerpool.Result) {
 	}
 }
 
-func (s *Service) consumeKafkaMessages() {
-	defer s.wg.Done()
+// rewardOutcome labels what became of a reading: rewarded, quarantined, withheld, duplicate
+// or, when processing it failed, failed.
+func rewardOutcome(result RewardResult) string {
+	switch {
+	case !result.Success:
+		return "failed"
+	case result.Quarantined:
+		return "quarantined"
+	case result.Withheld:
+		return "withheld"
+	case result.Output.Duplicate:
+		return "duplicate"
+	}
+	return "rewarded"
+}
 
-	inputChan := make(chan *ckafka.Message, 10)
-	errChan := make(chan error, 1)
+// handleFailedMessage moves a message that ran out of attempts to the dead-letter topic and
+// acknowledges it, so that a poison message neither blocks nor silently disappears. Messages
+// that cannot be dead-lettered are negatively acknowledged, to be delivered again.
+func (s *Service) handleFailedMessage(result RewardResult) {
+	msg := result.Message
+	if errors.Is(result.Error, context.Canceled) {
+		s.Logger.Warn("Worker processing interrupted, not acknowledging message",
+			"error", result.Error,
+			"message_id", msg.Id)
+		s.nack(msg)
+		return
+	}
 
-	go func() {
-		if err := s.kafkaConsumer.Consume(inputChan); err != nil {
-			s.Logger.Error("Kafka consumer fatal error", "error", err)
-			errChan <- err
-		}
-	}()
+	deadLetter := &kafka.DeadLetter{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Payload:   string(msg.Value),
+		Attempts:  result.Attempts,
+		FailedAt:  time.Now().UTC(),
+	}
+	if result.Error != nil {
+		deadLetter.Error = result.Error.Error()
+	}
+	value, err := json.Marshal(deadLetter)
+	if err != nil {
+		s.Logger.Error("Failed to encode dead letter, not acknowledging message", "error", err)
+		s.nack(msg)
+		return
+	}
 
-	for {
-		select {
-		case msg := <-inputChan:
-			select {
-			case s.jobChan <- msg:
-				s.Logger.Debug("Job enqueued",
-					"topic", *msg.TopicPartition.Topic,
-					"partition", msg.TopicPartition.Partition,
-					"offset", msg.TopicPartition.Offset)
-			case <-s.sigintChan:
-				return
-			case <-s.Context.Done():
-				return
-			}
-		case err := <-errChan:
-			s.Logger.Error("Kafka consumer failed, requesting shutdown", "error", err)
-			select { case <-s.sigintChan: default: close(s.sigintChan) }
-			return
-		case <-s.sigintChan:
-			return
-		case <-s.Context.Done():
-			return
-		}
+	topic := s.settings().deadLetter
+	if err := s.sink.Publish(s.Context, topic, msg.Key, value, nil); err != nil {
+		s.Logger.Error("Failed to publish dead letter, not acknowledging message",
+			"error", err,
+			"topic", topic,
+			"message_id", msg.Id)
+		s.nack(msg)
+		return
 	}
-}
 
-func (s *Service) processBlockchainTransactions() {
-	defer s.wg.Done()
-	for {
-		select {
-		case reward := <-s.txChan:
-			s.Logger.Debug("Processing blockchain transaction",
-				"id", reward.Id.Hex(),
-				"receiver", reward.Receiver,
-				"amount", reward.Amount)
-
-			txHash, err := s.mintReward(reward)
-			if err != nil {
-				s.Logger.Error("Failed to mint reward on blockchain",
-					"error", err,
-					"id", reward.Id.Hex(),
-					"receiver", reward.Receiver,
-					"amount", reward.Amount)
-				continue
-			}
+	s.metrics.messagesConsumed.WithLabelValues(msg.Topic, "dead_lettered").Inc()
+	s.Logger.Warn("Message sent to dead-letter topic",
+		"error", result.Error,
+		"attempts", result.Attempts,
+		"topic", topic,
+		"message_id", msg.Id)
+
+	if err := s.source.Ack(s.Context, msg); err != nil {
+		s.Logger.Error("Failed to acknowledge message",
+			"error", err,
+			"message_id", msg.Id)
+	}
+}
 
-			updateTxHashUseCase := usecase.NewUpdateRewardTxHashUseCase(s.repository)
-			err = updateTxHashUseCase.Execute(s.Context, reward.Id, txHash.Hex())
-			if err != nil {
-				s.Logger.Error("Failed to update reward tx hash in DB",
-					"error", err,
-					"id", reward.Id.Hex(),
-					"tx_hash", txHash.Hex())
-			}
+func (s *Service) nack(msg *source.Message) {
+	s.metrics.messagesConsumed.WithLabelValues(msg.Topic, "nacked").Inc()
+	if err := s.source.Nack(s.Context, msg); err != nil {
+		s.Logger.Error("Failed to negatively acknowledge message",
+			"error", err,
+			"message_id", msg.Id)
+	}
+}
 
-			s.Logger.Info("Reward minted on blockchain",
-				"id", reward.Id.Hex(),
-				"token", s.token,
-				"amount", reward.Amount,
-				"receiver", reward.Receiver,
-				"tx_hash", txHash,
-				"data", reward.Data)
+// receiveMessages feeds the worker pool until the source is closed.
+func (s *Service) receiveMessages() {
+	defer s.wg.Done()
+	defer close(s.receiverDone)
 
-		case <-s.sigintChan:
-			s.Logger.Info("Blockchain transaction processor stopping")
+	for {
+		msg, err := s.source.Receive(s.Context)
+		if errors.Is(err, source.ErrSourceClosed) || s.Context.Err() != nil {
+			return
+		}
+		if err != nil {
+			s.Logger.Error("Message source failed, requesting shutdown", "error", err)
+			s.Cancel()
 			return
+		}
+
+		select {
+		case s.jobChan <- msg:
+			s.Logger.Debug("Job enqueued",
+				"message_id", msg.Id,
+				"topic", msg.Topic,
+				"partition", msg.Partition)
 		case <-s.Context.Done():
-			s.Logger.Info("Blockchain transaction processor cancelled")
 			return
 		}
 	}
 }
 
-func (s *Service) mintReward(reward *usecase.CreateRewardOutputDTO) (common.Hash, error) {
-	tokenAddr := s.token
-	receiverAddr := common.HexToAddress(reward.Receiver)
-
-	contract, err := rewardtoken.NewRewardToken(tokenAddr, s.ethClient)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to create contract: %w", err)
-	}
-
-	amount := new(big.Int)
-	if _, ok := amount.SetString(reward.Amount, 10); !ok {
-		return common.Hash{}, fmt.Errorf("invalid amount format: %s", reward.Amount)
-	}
+// kafkaStatsSource is implemented by message sources backed by a Kafka consumer.
+type kafkaStatsSource interface {
+	Stats() *kafka.Stats
+}
 
-	txOpts := *s.txOpts // clone
-	nonce, err := s.ethClient.PendingNonceAt(s.Context, txOpts.From)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to get nonce: %w", err)
+// KafkaStatsHandler serves the latest statistics of the Kafka consumer as JSON.
+func (s *Service) KafkaStatsHandler(w http.ResponseWriter, r *http.Request) {
+	statsSource, ok := s.source.(kafkaStatsSource)
+	if !ok || statsSource.Stats() == nil {
+		http.Error(w, s.Name+": kafka statistics not available",
+			http.StatusServiceUnavailable)
+		return
 	}
-	txOpts.Nonce = big.NewInt(int64(nonce))
-
-	tx, err := contract.Mint(&txOpts, receiverAddr, amount)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to mint: %w", err)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statsSource.Stats()); err != nil {
+		s.Logger.Error("Failed to encode Kafka statistics", "error", err)
 	}
-
-	return tx.Hash(), nil
 }
 
 func (s *Service) Stop(force bool) []error {
 	var errs []error
 
+	// Close the source first: it waits for the messages already received to be acknowledged,
+	// which needs the workers and the result processor.
+	if s.source != nil {
+		if err := s.source.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close message source: %w", err))
+		}
+		if s.receiverDone != nil {
+			<-s.receiverDone
+		}
+	}
+
 	if s.sigintChan != nil {
 		close(s.sigintChan)
 	}
@@ -379,15 +589,18 @@ func (s *Service) Stop(force bool) []error {
 		close(s.jobChan)
 	}
 
-	if s.txChan != nil {
-		close(s.txChan)
-	}
-
 	if err := s.workerPool.Stop(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to stop worker pool: %w", err))
 	}
 
 	s.wg.Wait()
+	s.closeAlertPublishers()
+
+	if s.sink != nil {
+		if err := s.sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close message sink: %w", err))
+		}
+	}
 
 	if s.repository != nil {
 		if err := s.repository.Close(); err != nil {
@@ -0,0 +1,237 @@
+package relayer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/city.fun/relayer/configs"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/gas"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/quality"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/service"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// settings are the parts of the configuration that are read on every use, so that Reload
+// can replace them while the relayer runs.
+type settings struct {
+	deadLetter     string
+	eventsTopic    string
+	eventsFailures int // failed publications after which an event is dead-lettered
+	retryPolicy    retry.Policy
+	outbox         outboxConfig
+	pauseInterval  time.Duration
+	quality        quality.Config
+	neighborRadius float64
+	neighborWindow time.Duration
+	signatures     signaturePolicy
+	payouts        usecase.PayoutMode
+	replayWindow   time.Duration
+	alertZoneSize  float64
+	receivers      usecase.ReceiverPolicy
+	denylist       map[common.Address]bool
+	dailyCap       *big.Int
+}
+
+func newSettings(owner string, config configs.RelayerConfig, ranges map[string]quality.Range, denylist map[common.Address]bool) *settings {
+	return &settings{
+		deadLetter:     config.KafkaDeadLetterTopic,
+		eventsTopic:    config.KafkaEventsTopic,
+		eventsFailures: int(config.KafkaEventsMaxFailures),
+		retryPolicy: retry.Policy{
+			MaxAttempts: int(config.KafkaRetryMaxAttempts),
+			MinWait:     config.KafkaRetryMinWait,
+			MaxWait:     config.KafkaRetryMaxWait,
+		},
+		outbox: outboxConfig{
+			owner:        owner,
+			pollInterval: config.OutboxPollInterval,
+			claimTimeout: config.OutboxClaimTimeout,
+			maxAttempts:  int(config.OutboxMaxAttempts),
+			retention:    config.OutboxRetention,
+			replaceAfter: config.OutboxReplaceAfter,
+			retryPolicy: retry.Policy{
+				MinWait: config.OutboxRetryMinWait,
+				MaxWait: config.OutboxRetryMaxWait,
+			},
+		},
+		pauseInterval: config.BlockchainGasPauseInterval,
+		quality: quality.Config{
+			Ranges:            ranges,
+			MinInterval:       config.QualityMinInterval,
+			StuckReadings:     int(config.QualityStuckReadings),
+			SpikeFactor:       config.QualitySpikeFactor,
+			NeighborTolerance: config.QualityNeighborTolerance,
+			Withhold:          config.QualityWithholdScore,
+		},
+		neighborRadius: config.QualityNeighborRadius,
+		neighborWindow: config.QualityNeighborWindow,
+		signatures:     signaturePolicy(config.DeviceSignatures),
+		payouts:        usecase.PayoutMode(config.Payouts),
+		replayWindow:   config.ReplayWindow,
+		alertZoneSize:  config.AlertZoneSize,
+		receivers:      usecase.ReceiverPolicy(config.Receivers),
+		denylist:       denylist,
+		dailyCap:       config.ReceiverDailyCap,
+	}
+}
+
+// qualityRanges returns the plausible ranges of the parameters, the built-in ones replaced
+// by the configured ones.
+func qualityRanges() (map[string]quality.Range, error) {
+	value, err := configs.GetQualityRanges()
+	if err != nil && !errors.Is(err, configs.ErrNotDefined) {
+		return nil, err
+	}
+	return quality.ParseRanges(value)
+}
+
+func (s *Service) settings() *settings {
+	return s.live.Load()
+}
+
+func gasConfig(config configs.RelayerConfig) gas.Config {
+	return gas.Config{
+		GasLimit:             config.BlockchainGasLimit,
+		MaxBaseFee:           config.BlockchainMaxBaseFee,
+		MaxPriorityFee:       config.BlockchainMaxPriorityFee,
+		FeeHistoryBlocks:     config.BlockchainFeeHistoryBlocks,
+		FeeHistoryPercentile: float64(config.BlockchainFeeHistoryPercentile),
+		DailyBudget:          config.BlockchainDailyBudget,
+	}
+}
+
+// Reload reads the configuration again and applies the log level, the worker count, the
+// topics, the retry and outbox policy, the gas caps, the history retention, the data quality
+// checks, the device signature policy, the payout mode, the replay window, the receiver
+// policy and the health thresholds. Changes to any other setting are reported as errors,
+// since they only take effect on restart.
+func (s *Service) Reload() []error {
+	config, err := configs.LoadRelayerConfig()
+	if err != nil {
+		return []error{fmt.Errorf("failed to load config: %w", err)}
+	}
+
+	changed := service.ChangedSettings(s.config, *config)
+	if len(changed) == 0 {
+		return nil
+	}
+	s.Logger.Info("Configuration changed", "settings", changed)
+
+	var errs []error
+	var failed []string
+	fail := func(name string, err error) {
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		failed = append(failed, name)
+	}
+	applied := s.config
+
+	if config.LogLevel != applied.LogLevel {
+		s.LogLevel.Set(config.LogLevel)
+		applied.LogLevel = config.LogLevel
+	}
+
+	if config.WorkerCount != applied.WorkerCount {
+		if err := s.workerPool.Resize(int(config.WorkerCount)); err != nil {
+			fail(configs.WORKER_COUNT, err)
+		} else {
+			applied.WorkerCount = config.WorkerCount
+		}
+	}
+
+	if !slices.Equal(config.KafkaTopics, applied.KafkaTopics) {
+		if subscriber, ok := s.source.(source.Subscriber); !ok {
+			fail(configs.KAFKA_TOPICS, service.ErrRestartRequired)
+		} else if err := subscriber.Subscribe(s.Context, config.KafkaTopics); err != nil {
+			fail(configs.KAFKA_TOPICS, err)
+		} else {
+			applied.KafkaTopics = config.KafkaTopics
+		}
+	}
+
+	applied.BlockchainGasLimit = config.BlockchainGasLimit
+	applied.BlockchainMaxBaseFee = config.BlockchainMaxBaseFee
+	applied.BlockchainMaxPriorityFee = config.BlockchainMaxPriorityFee
+	applied.BlockchainFeeHistoryBlocks = config.BlockchainFeeHistoryBlocks
+	applied.BlockchainFeeHistoryPercentile = config.BlockchainFeeHistoryPercentile
+	applied.BlockchainDailyBudget = config.BlockchainDailyBudget
+	applied.BlockchainGasPauseInterval = config.BlockchainGasPauseInterval
+	s.gasStrategy.SetConfig(gasConfig(applied))
+
+	applied.KafkaDeadLetterTopic = config.KafkaDeadLetterTopic
+	applied.KafkaEventsTopic = config.KafkaEventsTopic
+	applied.KafkaEventsMaxFailures = config.KafkaEventsMaxFailures
+	applied.KafkaRetryMaxAttempts = config.KafkaRetryMaxAttempts
+	applied.KafkaRetryMinWait = config.KafkaRetryMinWait
+	applied.KafkaRetryMaxWait = config.KafkaRetryMaxWait
+	applied.OutboxPollInterval = config.OutboxPollInterval
+	applied.OutboxClaimTimeout = config.OutboxClaimTimeout
+	applied.OutboxMaxAttempts = config.OutboxMaxAttempts
+	applied.OutboxRetention = config.OutboxRetention
+	applied.OutboxReplaceAfter = config.OutboxReplaceAfter
+	applied.OutboxRetryMinWait = config.OutboxRetryMinWait
+	applied.OutboxRetryMaxWait = config.OutboxRetryMaxWait
+	applied.QualityMinInterval = config.QualityMinInterval
+	applied.QualityStuckReadings = config.QualityStuckReadings
+	applied.QualitySpikeFactor = config.QualitySpikeFactor
+	applied.QualityNeighborRadius = config.QualityNeighborRadius
+	applied.QualityNeighborWindow = config.QualityNeighborWindow
+	applied.QualityNeighborTolerance = config.QualityNeighborTolerance
+	applied.QualityWithholdScore = config.QualityWithholdScore
+	if _, err := parseSignaturePolicy(config.DeviceSignatures); err != nil {
+		fail(configs.DEVICE_SIGNATURES, err)
+	} else {
+		applied.DeviceSignatures = config.DeviceSignatures
+	}
+	if _, err := usecase.ParsePayoutMode(config.Payouts); err != nil {
+		fail(configs.PAYOUTS, err)
+	} else {
+		applied.Payouts = config.Payouts
+	}
+	applied.ReplayWindow = config.ReplayWindow
+	if _, err := usecase.ParseReceiverPolicy(config.Receivers); err != nil {
+		fail(configs.RECEIVERS, err)
+	} else {
+		applied.Receivers = config.Receivers
+	}
+	applied.ReceiverDailyCap = config.ReceiverDailyCap
+	ranges, err := qualityRanges()
+	if err != nil {
+		fail(configs.QUALITY_RANGES, err)
+		ranges = s.settings().quality.Ranges
+	}
+	denylist, err := receiverDenylist()
+	if err != nil {
+		fail(configs.RECEIVER_DENYLIST, err)
+		denylist = s.settings().denylist
+	}
+	s.live.Store(newSettings(s.settings().outbox.owner, applied, ranges, denylist))
+
+	if config.HistoryRetention != applied.HistoryRetention {
+		setRetention := usecase.NewSetHistoryRetentionUseCase(s.repository)
+		if err := setRetention.Execute(s.Context, config.HistoryRetention); err != nil {
+			fail(configs.HISTORY_RETENTION, err)
+		} else {
+			applied.HistoryRetention = config.HistoryRetention
+		}
+	}
+
+	applied.HealthCheckTimeout = config.HealthCheckTimeout
+	applied.HealthMaxBlockAge = config.HealthMaxBlockAge
+	applied.HealthMinBalance = config.HealthMinBalance
+	registerHealthChecks(s, applied)
+
+	for _, name := range service.ChangedSettings(applied, *config) {
+		if !slices.Contains(failed, name) {
+			fail(name, service.ErrRestartRequired)
+		}
+	}
+	s.config = applied
+	s.notifyOutbox()
+	return errs
+}
@@ -0,0 +1,51 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// checkReplay rejects readings that are not fresh or do not follow the last one of their
+// sensor, as a permanent error that sends them to the dead-letter topic.
+func (s *Service) checkReplay(ctx context.Context, msg *source.Message, input *usecase.CreateRewardInputDTO, settings *settings) (int, error) {
+	if settings.replayWindow <= 0 {
+		return 0, nil
+	}
+
+	checkReplay := usecase.NewCheckReplayUseCase(s.repository, settings.replayWindow)
+	attempts, err := settings.retryPolicy.Do(ctx, func(int) error {
+		err := checkReplay.Execute(ctx, input, msg.ReceivedAt)
+		if errors.Is(err, usecase.ErrReplayedReading) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("check_replay").Inc()
+		}
+		return err
+	})
+	if err == nil {
+		return attempts, nil
+	}
+	if !errors.Is(err, usecase.ErrReplayedReading) {
+		s.Logger.Error("Failed to check replay", "error", err, "sensor_id", input.SensorId, "attempts", attempts)
+		return attempts, fmt.Errorf("failed to check replay: %w", err)
+	}
+
+	var reason string
+	switch {
+	case errors.Is(err, usecase.ErrUnsequencedReading):
+		reason = "unsequenced"
+	case errors.Is(err, usecase.ErrStaleReading):
+		reason = "stale"
+	default:
+		reason = "sequence"
+	}
+	s.metrics.readingsReplayed.WithLabelValues(reason).Inc()
+	s.Logger.Warn("Rejecting replayed reading", "error", err, "reading_id", input.ReadingId, "sensor_id", input.SensorId)
+	return attempts, err
+}
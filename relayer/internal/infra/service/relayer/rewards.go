@@ -0,0 +1,186 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/usecase"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/retry"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// rewardReading decides the reward of a reading that passed the signature and replay checks:
+// its payout, its data quality and its receiver, and saves it to be minted. Readings held by
+// the payout check are released from here.
+func (s *Service) rewardReading(ctx context.Context, span trace.Span, msg *source.Message, input *usecase.CreateRewardInputDTO, reading *entity.Reading, settings *settings, result RewardResult) RewardResult {
+	// The registry decides who gets paid and how much, not the reading.
+	quarantined, attempts, err := s.resolvePayout(ctx, msg, input, settings)
+	if err != nil {
+		result.Attempts = attempts
+		result.Error = err
+		span.RecordError(result.Error)
+		span.SetStatus(codes.Error, "payout not resolved")
+		return result
+	}
+	if quarantined {
+		result.Success = true
+		result.Quarantined = true
+		return result
+	}
+
+	// Readings are checked before they are rewarded: implausible ones are withheld, and
+	// the reward of the others is scaled by the reputation of their sensor.
+	var validation *usecase.ValidateReadingOutputDTO
+	validateReadingUseCase := usecase.NewValidateReadingUseCase(s.repository, settings.quality)
+	validateReadingUseCase.NeighborRadius = settings.neighborRadius
+	validateReadingUseCase.NeighborWindow = settings.neighborWindow
+	attempts, err = settings.retryPolicy.Do(ctx, func(attempt int) error {
+		var err error
+		validation, err = validateReadingUseCase.Execute(ctx, input, msg.ReceivedAt)
+		if errors.Is(err, entity.ErrInvalidReward) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("validate_reading").Inc()
+		}
+		return err
+	})
+	if err != nil {
+		result.Attempts = attempts
+		result.Error = fmt.Errorf("failed to validate reading: %w", err)
+		span.RecordError(result.Error)
+		span.SetStatus(codes.Error, "reading not validated")
+		s.Logger.Error("Failed to validate reading",
+			"error", err,
+			"reading_id", input.ReadingId,
+			"attempts", attempts)
+		return result
+	}
+	s.metrics.readingsValidated.WithLabelValues(validationOutcome(validation)).Inc()
+	span.SetAttributes(attribute.Float64("sensor.reputation", validation.Score))
+	if len(validation.Findings) > 0 {
+		s.Logger.Warn("Reading failed data quality checks",
+			"reading_id", input.ReadingId,
+			"sensor_id", validation.SensorId,
+			"findings", validation.Findings,
+			"score", validation.Score,
+			"withheld", validation.Withheld)
+	}
+	if validation.Withheld {
+		s.Logger.Warn("Reward withheld",
+			"reading_id", input.ReadingId,
+			"sensor_id", validation.SensorId,
+			"rejected", validation.Rejected,
+			"score", validation.Score)
+		result.Success = true
+		result.Withheld = true
+		return result
+	}
+	input.Amount = validation.Amount
+
+	return s.payReward(ctx, span, msg, input, reading, settings, result)
+}
+
+// payReward checks the receiver of a reward whose amount is decided and saves it to be
+// minted. Rewards held by the receiver policy are released from here.
+func (s *Service) payReward(ctx context.Context, span trace.Span, msg *source.Message, input *usecase.CreateRewardInputDTO, reading *entity.Reading, settings *settings, result RewardResult) RewardResult {
+	// The receiver is checked against the final amount, which counts toward its cap.
+	quarantined, attempts, err := s.checkReceiver(ctx, msg, input, settings)
+	if err != nil {
+		result.Attempts = attempts
+		result.Error = err
+		span.RecordError(result.Error)
+		span.SetStatus(codes.Error, "receiver not checked")
+		return result
+	}
+	if quarantined {
+		result.Success = true
+		result.Quarantined = true
+		return result
+	}
+
+	var output *usecase.CreateRewardOutputDTO
+	createRewardUseCase := usecase.NewCreateRewardUseCase(s.repository)
+	retryPolicy := settings.retryPolicy
+	attempts, err = retryPolicy.Do(ctx, func(attempt int) error {
+		attemptCtx, dbSpan := tracer.Start(ctx, "db.upsert_reward",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "mongodb"),
+				attribute.Int("attempt", attempt),
+			))
+		var err error
+		output, err = createRewardUseCase.Execute(attemptCtx, input)
+		if err != nil {
+			dbSpan.RecordError(err)
+			dbSpan.SetStatus(codes.Error, "upsert failed")
+		}
+		dbSpan.End()
+		if errors.Is(err, entity.ErrInvalidReward) {
+			return retry.Permanent(err)
+		}
+		if err != nil {
+			s.metrics.dbErrors.WithLabelValues("create_reward").Inc()
+		}
+		if err != nil && attempt < retryPolicy.MaxAttempts {
+			s.Logger.Warn("Failed to save reward to DB, retrying",
+				"error", err,
+				"reading_id", input.ReadingId,
+				"attempt", attempt,
+				"retry_in", retryPolicy.Backoff(attempt))
+		}
+		return err
+	})
+	result.Attempts = attempts
+	if err != nil || output.Duplicate {
+		// The reading is not paid here: saving failed, or another worker paid it.
+		s.releaseCap(input.Receiver, input.CapDay, input.Amount)
+	}
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create reward: %w", err)
+		span.RecordError(result.Error)
+		span.SetStatus(codes.Error, "reward not saved")
+		s.Logger.Error("Failed to save reward to DB",
+			"error", err,
+			"reading_id", input.ReadingId,
+			"receiver", input.Receiver,
+			"amount", input.Amount,
+			"attempts", attempts)
+		return result
+	}
+
+	if output.Duplicate {
+		s.Logger.Warn("Reading already rewarded, skipping",
+			"reading_id", output.ReadingId,
+			"id", output.Id.Hex(),
+			"tx_hash", output.TxHash)
+		result.Success = true
+		result.Output = output
+		return result
+	}
+
+	s.Logger.Info("Reward processed in DB",
+		"id", output.Id.Hex(),
+		"receiver", output.Receiver,
+		"amount", output.Amount,
+		"latitude", output.Latitude,
+		"longitude", output.Longitude)
+
+	s.notifyOutbox()
+	s.Logger.Debug("Mint queued on outbox", "id", output.Id.Hex())
+
+	if reading != nil {
+		s.observeAQI(reading)
+	}
+
+	result.Success = true
+	result.Output = output
+
+	return result
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+const (
+	// DefaultReadingRange is the range of the reading queries without a start.
+	DefaultReadingRange = 24 * time.Hour
+	// MaxReadingWindows bounds the windows of a sensor in a query.
+	MaxReadingWindows = 10000
+)
+
+var ErrInvalidReadingQuery = errors.New("invalid reading query")
+
+type AggregateReadingsInputDTO struct {
+	SensorId string
+	From     time.Time
+	To       time.Time
+	Window   time.Duration // zero summarizes the whole range
+}
+
+type AggregateReadingsOutputDTO struct {
+	From    time.Time               `json:"from"`
+	To      time.Time               `json:"to"`
+	Window  string                  `json:"window"`
+	Windows []*entity.ReadingWindow `json:"windows"`
+}
+
+type AggregateReadingsUseCase struct {
+	Repository repository.Repository
+}
+
+func NewAggregateReadingsUseCase(repository repository.Repository) *AggregateReadingsUseCase {
+	return &AggregateReadingsUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns the minimum, maximum and average of every parameter, per sensor and window.
+func (uc *AggregateReadingsUseCase) Execute(ctx context.Context, input *AggregateReadingsInputDTO) (*AggregateReadingsOutputDTO, error) {
+	filter, err := readingFilter(input.SensorId, input.From, input.To)
+	if err != nil {
+		return nil, err
+	}
+	if input.Window < 0 {
+		return nil, fmt.Errorf("%w: negative window", ErrInvalidReadingQuery)
+	}
+	if input.Window > 0 && filter.To.Sub(filter.From)/input.Window > MaxReadingWindows {
+		return nil, fmt.Errorf("%w: more than %d windows", ErrInvalidReadingQuery, MaxReadingWindows)
+	}
+
+	windows, err := uc.Repository.AggregateReadings(ctx, filter, input.Window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate readings: %w", err)
+	}
+	if windows == nil {
+		windows = []*entity.ReadingWindow{}
+	}
+	return &AggregateReadingsOutputDTO{
+		From:    filter.From,
+		To:      filter.To,
+		Window:  input.Window.String(),
+		Windows: windows,
+	}, nil
+}
+
+// readingFilter validates the range of a reading query. It ends now and lasts
+// DefaultReadingRange unless given.
+func readingFilter(sensorId string, from, to time.Time) (repository.ReadingFilter, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-DefaultReadingRange)
+	}
+	if !from.Before(to) {
+		return repository.ReadingFilter{}, fmt.Errorf("%w: from must be before to", ErrInvalidReadingQuery)
+	}
+	return repository.ReadingFilter{SensorId: sensorId, From: from, To: to}, nil
+}
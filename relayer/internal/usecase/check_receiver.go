@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+)
+
+// ReceiverPolicy is which receivers rewards may be paid to.
+type ReceiverPolicy string
+
+const (
+	// ReceiversOpen pays any valid receiver that is not denied.
+	ReceiversOpen ReceiverPolicy = "open"
+	// ReceiversAllowlist only pays the receivers registered as allowed.
+	ReceiversAllowlist ReceiverPolicy = "allowlist"
+)
+
+var (
+	// ErrRewardHeld is returned for a reward that the receiver policy does not let be minted.
+	ErrRewardHeld = errors.New("reward held")
+	// ErrReceiverDenied is returned with ErrRewardHeld for an invalid or denied receiver.
+	ErrReceiverDenied = errors.New("receiver denied")
+	// ErrReceiverUnlisted is returned with ErrRewardHeld for a receiver that is not allowed
+	// under the allowlist policy.
+	ErrReceiverUnlisted = errors.New("receiver not on the allowlist")
+	// ErrDailyCapExceeded is returned with ErrRewardHeld for a reward over the daily cap of
+	// its receiver.
+	ErrDailyCapExceeded = errors.New("daily cap of the receiver exceeded")
+)
+
+func ParseReceiverPolicy(s string) (ReceiverPolicy, error) {
+	switch policy := ReceiverPolicy(s); policy {
+	case ReceiversOpen, ReceiversAllowlist:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid receiver policy %q, expected open or allowlist", s)
+}
+
+// ParseDenylist parses the addresses rewards are never paid to, such as sanctioned ones.
+func ParseDenylist(addresses []string) (map[common.Address]bool, error) {
+	denied := make(map[common.Address]bool, len(addresses))
+	for _, address := range addresses {
+		parsed, err := ethutil.ParseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		denied[parsed] = true
+	}
+	return denied, nil
+}
+
+// CheckReceiverOutputDTO is the amount counted against the daily cap of the receiver, which
+// must be released if the reward is not paid.
+type CheckReceiverOutputDTO struct {
+	Receiver string
+	Day      time.Time // UTC day the amount counts against
+	Reserved *big.Int  // nil when nothing was reserved
+}
+
+type CheckReceiverUseCase struct {
+	Repository repository.Repository
+	Policy     ReceiverPolicy
+	Denylist   map[common.Address]bool
+	DailyCap   *big.Int // cap of the receivers without their own, none when nil or zero
+}
+
+func NewCheckReceiverUseCase(repository repository.Repository, policy ReceiverPolicy, denylist map[common.Address]bool, dailyCap *big.Int) *CheckReceiverUseCase {
+	return &CheckReceiverUseCase{
+		Repository: repository,
+		Policy:     policy,
+		Denylist:   denylist,
+		DailyCap:   dailyCap,
+	}
+}
+
+// Execute checks that a reward may be paid to its receiver at the time at, and fails with
+// ErrRewardHeld otherwise. The zero address, the token contract and the denylist are always
+// denied. Rewards already saved for the reading pass, so that a redelivery is not held.
+//
+// The amount of a receiver with a daily cap is reserved on the UTC day of at, atomically, so
+// that readings processed in parallel cannot all fit in the same room. The reservation is
+// returned, to be released if the reward is not saved or its mint fails.
+func (uc *CheckReceiverUseCase) Execute(ctx context.Context, input *CreateRewardInputDTO, at time.Time) (*CheckReceiverOutputDTO, error) {
+	address, err := ethutil.ParseAddress(input.Receiver)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w: %w", ErrRewardHeld, ErrReceiverDenied, err)
+	}
+	if address == (common.Address{}) || address == input.Token || uc.Denylist[address] {
+		return nil, fmt.Errorf("%w: %w: %s is on the denylist", ErrRewardHeld, ErrReceiverDenied, address.Hex())
+	}
+
+	output := &CheckReceiverOutputDTO{Receiver: address.Hex()}
+	if _, err := uc.Repository.FindOutboxEntryByReadingId(ctx, input.ReadingId); err == nil {
+		return output, nil
+	} else if !errors.Is(err, entity.ErrOutboxEntryNotFound) {
+		return nil, fmt.Errorf("failed to check reading id: %w", err)
+	}
+
+	dailyCap := uc.DailyCap
+	receiver, err := uc.Repository.FindReceiver(ctx, address.Hex())
+	switch {
+	case errors.Is(err, entity.ErrReceiverNotFound):
+		if uc.Policy == ReceiversAllowlist {
+			return nil, fmt.Errorf("%w: %w: %s", ErrRewardHeld, ErrReceiverUnlisted, address.Hex())
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to find receiver: %w", err)
+	case receiver.Status == entity.ReceiverDenied:
+		return nil, fmt.Errorf("%w: %w: %s: %s", ErrRewardHeld, ErrReceiverDenied, address.Hex(), receiver.Reason)
+	default:
+		if own, err := receiver.Cap(); err == nil && own != nil {
+			dailyCap = own
+		}
+	}
+	if dailyCap == nil || dailyCap.Sign() <= 0 {
+		return output, nil
+	}
+
+	amount, ok := new(big.Int).SetString(input.Amount, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("%w: invalid amount", entity.ErrInvalidReward)
+	}
+	day := at.UTC().Truncate(24 * time.Hour)
+	err = uc.Repository.ReserveReceiverAmount(ctx, output.Receiver, day, amount, dailyCap)
+	if errors.Is(err, entity.ErrDailyCapReached) {
+		return nil, fmt.Errorf("%w: %w: %s would receive more than %s on %s", ErrRewardHeld, ErrDailyCapExceeded, output.Receiver, dailyCap, day.Format(time.DateOnly))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to reserve daily cap: %w", err)
+	}
+	output.Day = day
+	output.Reserved = amount
+	return output, nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+const testReceiver = "0x5AEDA56215b167893e80B4fE645BA6d5Bab767DE"
+
+func TestCheckReceiver_DailyCap(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	t.Run("reserves until the cap", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReceiverUseCase(repo, ReceiversOpen, nil, big.NewInt(2500))
+
+		for _, readingId := range []string{"reading-1", "reading-2"} {
+			output, err := uc.Execute(ctx, newRewardInput(readingId), now)
+			require.NoError(t, err)
+			assert.Equal(t, day, output.Day)
+			assert.Equal(t, big.NewInt(1000), output.Reserved)
+		}
+
+		_, err := uc.Execute(ctx, newRewardInput("reading-3"), now)
+		assert.ErrorIs(t, err, ErrRewardHeld)
+		assert.ErrorIs(t, err, ErrDailyCapExceeded)
+		assert.Equal(t, big.NewInt(2000), repo.reserved[testReceiver+"|2026-03-14"])
+	})
+
+	t.Run("exactly the cap", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReceiverUseCase(repo, ReceiversOpen, nil, big.NewInt(2000))
+
+		for _, readingId := range []string{"reading-1", "reading-2"} {
+			_, err := uc.Execute(ctx, newRewardInput(readingId), now)
+			require.NoError(t, err)
+		}
+		_, err := uc.Execute(ctx, newRewardInput("reading-3"), now)
+		assert.ErrorIs(t, err, ErrDailyCapExceeded)
+	})
+
+	t.Run("failed mints do not count", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReceiverUseCase(repo, ReceiversOpen, nil, big.NewInt(2000))
+
+		for _, readingId := range []string{"reading-1", "reading-2"} {
+			_, err := uc.Execute(ctx, newRewardInput(readingId), now)
+			require.NoError(t, err)
+		}
+		// The mint of reading-1 fails and gives its amount back.
+		release := NewReleaseReceiverAmountUseCase(repo)
+		require.NoError(t, release.Execute(ctx, testReceiver, day, "1000"))
+
+		_, err := uc.Execute(ctx, newRewardInput("reading-3"), now)
+		require.NoError(t, err)
+		_, err = uc.Execute(ctx, newRewardInput("reading-4"), now)
+		assert.ErrorIs(t, err, ErrDailyCapExceeded)
+	})
+
+	t.Run("counted per UTC day", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReceiverUseCase(repo, ReceiversOpen, nil, big.NewInt(1000))
+
+		_, err := uc.Execute(ctx, newRewardInput("reading-1"), now)
+		require.NoError(t, err)
+		_, err = uc.Execute(ctx, newRewardInput("reading-2"), now)
+		assert.ErrorIs(t, err, ErrDailyCapExceeded)
+
+		output, err := uc.Execute(ctx, newRewardInput("reading-2"), now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, day.Add(24*time.Hour), output.Day)
+	})
+
+	t.Run("cap of the receiver", func(t *testing.T) {
+		repo := newFakeRepository()
+		repo.receivers[testReceiver] = &entity.Receiver{Address: testReceiver, Status: entity.ReceiverAllowed, DailyCap: "3000"}
+		uc := NewCheckReceiverUseCase(repo, ReceiversOpen, nil, big.NewInt(1000))
+
+		for _, readingId := range []string{"reading-1", "reading-2", "reading-3"} {
+			_, err := uc.Execute(ctx, newRewardInput(readingId), now)
+			require.NoError(t, err)
+		}
+		_, err := uc.Execute(ctx, newRewardInput("reading-4"), now)
+		assert.ErrorIs(t, err, ErrDailyCapExceeded)
+	})
+
+	t.Run("uncapped", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReceiverUseCase(repo, ReceiversOpen, nil, nil)
+
+		output, err := uc.Execute(ctx, newRewardInput("reading-1"), now)
+		require.NoError(t, err)
+		assert.Nil(t, output.Reserved)
+		assert.Empty(t, repo.reserved)
+	})
+
+	t.Run("paid readings are not counted again", func(t *testing.T) {
+		repo := newFakeRepository()
+		repo.outbox["reading-1"] = &entity.OutboxEntry{ReadingId: "reading-1"}
+		uc := NewCheckReceiverUseCase(repo, ReceiversOpen, nil, big.NewInt(1000))
+
+		output, err := uc.Execute(ctx, newRewardInput("reading-1"), now)
+		require.NoError(t, err)
+		assert.Nil(t, output.Reserved)
+		assert.Empty(t, repo.reserved)
+	})
+}
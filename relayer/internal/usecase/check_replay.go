@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+var (
+	// ErrReplayedReading is returned for a reading that may be a replay of a captured one.
+	ErrReplayedReading = errors.New("reading rejected as a replay")
+	// ErrUnsequencedReading is returned with ErrReplayedReading for a reading without a
+	// sensor id, emission time or sequence number.
+	ErrUnsequencedReading = errors.New("missing sensor id, emission time or sequence number")
+	// ErrStaleReading is returned with ErrReplayedReading for a reading emitted too long
+	// before or after it was received.
+	ErrStaleReading = errors.New("emission time outside the freshness window")
+)
+
+type CheckReplayUseCase struct {
+	Repository repository.Repository
+	Window     time.Duration // how far the emission time may be from the reception time
+}
+
+func NewCheckReplayUseCase(repository repository.Repository, window time.Duration) *CheckReplayUseCase {
+	return &CheckReplayUseCase{
+		Repository: repository,
+		Window:     window,
+	}
+}
+
+// Execute checks that a reading received at receivedAt is fresh and follows the last one of
+// its sensor, and records it as the last one. It fails with ErrReplayedReading otherwise. A
+// reading that was paid already passes, and one delivered again, from the dead-letter topic
+// for instance, is checked against the time it was first received.
+func (uc *CheckReplayUseCase) Execute(ctx context.Context, input *CreateRewardInputDTO, receivedAt time.Time) error {
+	if input.SensorId == "" || input.EmittedAt.IsZero() || input.Sequence <= 0 {
+		return fmt.Errorf("%w: %w", ErrReplayedReading, ErrUnsequencedReading)
+	}
+
+	_, err := uc.Repository.FindOutboxEntryByReadingId(ctx, input.ReadingId)
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, entity.ErrOutboxEntryNotFound) {
+		return fmt.Errorf("failed to check reading id: %w", err)
+	}
+
+	firstReceivedAt := receivedAt
+	reading, err := uc.Repository.FindReadingByReadingId(ctx, input.ReadingId)
+	if err == nil {
+		if reading.RecordedAt.Before(firstReceivedAt) {
+			firstReceivedAt = reading.RecordedAt
+		}
+	} else if !errors.Is(err, entity.ErrReadingNotFound) {
+		return fmt.Errorf("failed to check reading: %w", err)
+	}
+	if age := firstReceivedAt.Sub(input.EmittedAt); age > uc.Window || age < -uc.Window {
+		return fmt.Errorf("%w: %w: emitted %s before reception", ErrReplayedReading, ErrStaleReading, age)
+	}
+
+	err = uc.Repository.AdvanceSensorSequence(ctx, &entity.SensorSequence{
+		SensorId:  input.SensorId,
+		Sequence:  input.Sequence,
+		ReadingId: input.ReadingId,
+		EmittedAt: input.EmittedAt,
+		UpdatedAt: receivedAt,
+	})
+	if errors.Is(err, entity.ErrStaleSequence) {
+		return fmt.Errorf("%w: %w: sequence %d", ErrReplayedReading, err, input.Sequence)
+	} else if err != nil {
+		return fmt.Errorf("failed to record sensor sequence: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func newSequencedInput(readingId string, sequence int64, emittedAt time.Time) *CreateRewardInputDTO {
+	input := newRewardInput(readingId)
+	input.Sequence = sequence
+	input.EmittedAt = emittedAt
+	return input
+}
+
+func TestCheckReplay(t *testing.T) {
+	repo := newFakeRepository()
+	uc := NewCheckReplayUseCase(repo, 5*time.Minute)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	require.NoError(t, uc.Execute(ctx, newSequencedInput("reading-1", 1, now.Add(-time.Second)), now))
+	// The same reading delivered again is not a replay.
+	require.NoError(t, uc.Execute(ctx, newSequencedInput("reading-1", 1, now.Add(-time.Second)), now))
+	require.NoError(t, uc.Execute(ctx, newSequencedInput("reading-3", 3, now), now))
+
+	err := uc.Execute(ctx, newSequencedInput("reading-2", 2, now), now)
+	assert.ErrorIs(t, err, ErrReplayedReading)
+	assert.ErrorIs(t, err, entity.ErrStaleSequence)
+	err = uc.Execute(ctx, newSequencedInput("reading-4", 3, now), now)
+	assert.ErrorIs(t, err, entity.ErrStaleSequence)
+
+	err = uc.Execute(ctx, newSequencedInput("reading-5", 5, now.Add(-6*time.Minute)), now)
+	assert.ErrorIs(t, err, ErrReplayedReading)
+	assert.ErrorIs(t, err, ErrStaleReading)
+	err = uc.Execute(ctx, newSequencedInput("reading-5", 5, now.Add(6*time.Minute)), now)
+	assert.ErrorIs(t, err, ErrStaleReading)
+
+	input := newSequencedInput("reading-6", 0, now)
+	assert.ErrorIs(t, uc.Execute(ctx, input, now), ErrUnsequencedReading)
+	input = newSequencedInput("reading-6", 6, now)
+	input.SensorId = ""
+	assert.ErrorIs(t, uc.Execute(ctx, input, now), ErrUnsequencedReading)
+
+	assert.Equal(t, "reading-3", repo.sequence["sensor-1"].ReadingId)
+}
+
+func TestCheckReplay_Redelivery(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(2 * time.Hour)
+	ctx := context.Background()
+
+	t.Run("recorded and sequenced", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReplayUseCase(repo, 5*time.Minute)
+		input := newSequencedInput("reading-1", 1, now)
+		repo.readings["reading-1"] = &entity.Reading{ReadingId: "reading-1", RecordedAt: now}
+		require.NoError(t, uc.Execute(ctx, input, now))
+
+		// Redelivered after an outage longer than the window.
+		require.NoError(t, uc.Execute(ctx, input, later))
+	})
+
+	t.Run("recorded before the sequence was", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReplayUseCase(repo, 5*time.Minute)
+		// The first delivery was dead-lettered before its sequence was recorded.
+		repo.readings["reading-1"] = &entity.Reading{ReadingId: "reading-1", RecordedAt: now}
+
+		require.NoError(t, uc.Execute(ctx, newSequencedInput("reading-1", 1, now), later))
+		assert.Equal(t, "reading-1", repo.sequence["sensor-1"].ReadingId)
+	})
+
+	t.Run("stale when first received", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReplayUseCase(repo, 5*time.Minute)
+		repo.readings["reading-1"] = &entity.Reading{ReadingId: "reading-1", RecordedAt: now}
+
+		err := uc.Execute(ctx, newSequencedInput("reading-1", 1, now.Add(-time.Hour)), later)
+		assert.ErrorIs(t, err, ErrStaleReading)
+	})
+
+	t.Run("paid", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReplayUseCase(repo, 5*time.Minute)
+		repo.outbox["reading-1"] = &entity.OutboxEntry{ReadingId: "reading-1"}
+		repo.sequence["sensor-1"] = &entity.SensorSequence{SensorId: "sensor-1", Sequence: 9, ReadingId: "reading-9"}
+
+		// Left to CreateReward, which counts it as a duplicate.
+		require.NoError(t, uc.Execute(ctx, newSequencedInput("reading-1", 1, now), later))
+		assert.Equal(t, int64(9), repo.sequence["sensor-1"].Sequence)
+	})
+
+	t.Run("replayed after later readings", func(t *testing.T) {
+		repo := newFakeRepository()
+		uc := NewCheckReplayUseCase(repo, 5*time.Minute)
+		repo.readings["reading-1"] = &entity.Reading{ReadingId: "reading-1", RecordedAt: now}
+		repo.sequence["sensor-1"] = &entity.SensorSequence{SensorId: "sensor-1", Sequence: 9, ReadingId: "reading-9"}
+
+		err := uc.Execute(ctx, newSequencedInput("reading-1", 1, now), later)
+		assert.ErrorIs(t, err, entity.ErrStaleSequence)
+	})
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type ClaimOutboxEntryUseCase struct {
+	Repository repository.Repository
+}
+
+func NewClaimOutboxEntryUseCase(repository repository.Repository) *ClaimOutboxEntryUseCase {
+	return &ClaimOutboxEntryUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute claims the oldest pending mint for owner during lease.
+// It returns entity.ErrOutboxEntryNotFound when there is nothing to mint.
+func (uc *ClaimOutboxEntryUseCase) Execute(ctx context.Context, owner string, lease time.Duration) (*entity.OutboxEntry, error) {
+	return uc.Repository.ClaimOutboxEntry(ctx, owner, lease)
+}
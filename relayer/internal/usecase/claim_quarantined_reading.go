@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type ClaimQuarantinedReadingUseCase struct {
+	Repository repository.Repository
+}
+
+func NewClaimQuarantinedReadingUseCase(repository repository.Repository) *ClaimQuarantinedReadingUseCase {
+	return &ClaimQuarantinedReadingUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns a released reading due at now, or nil when there is none. It is not due
+// again before lease has passed, in case processing it fails.
+func (uc *ClaimQuarantinedReadingUseCase) Execute(ctx context.Context, now time.Time, lease time.Duration) (*entity.QuarantinedReading, error) {
+	reading, err := uc.Repository.ClaimQuarantinedReading(ctx, now, lease)
+	if errors.Is(err, entity.ErrQuarantinedReadingNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to claim quarantined reading: %w", err)
+	}
+	return reading, nil
+}
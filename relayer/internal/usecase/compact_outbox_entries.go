@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type CompactOutboxEntriesUseCase struct {
+	Repository repository.Repository
+}
+
+func NewCompactOutboxEntriesUseCase(repository repository.Repository) *CompactOutboxEntriesUseCase {
+	return &CompactOutboxEntriesUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute drops the data only needed to mint from the finished entries older than retention,
+// and returns how many entries were compacted.
+func (uc *CompactOutboxEntriesUseCase) Execute(ctx context.Context, retention time.Duration) (int64, error) {
+	compacted, err := uc.Repository.CompactOutboxEntries(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to compact outbox entries: %w", err)
+	}
+	return compacted, nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/aqi"
+)
+
+// pollutantParameters maps the parameters of the sensors to the pollutants of the index.
+var pollutantParameters = map[string]aqi.Pollutant{
+	"mp25": aqi.PM25,
+	"pm25": aqi.PM25,
+	"mp10": aqi.PM10,
+	"pm10": aqi.PM10,
+	"co":   aqi.CO,
+	"no2":  aqi.NO2,
+}
+
+// readingAQI returns the index of the values of a single reading, or nil when they include
+// none of the pollutants of the standard.
+func readingAQI(standard aqi.Standard, values map[string]float64) *aqi.Result {
+	concentrations := make(map[aqi.Pollutant]float64)
+	for parameter, value := range values {
+		if p, ok := pollutantParameters[parameter]; ok {
+			concentrations[p] = value
+		}
+	}
+	result, err := standard.Compute(concentrations)
+	if err != nil {
+		return nil
+	}
+	return &result
+}
+
+type ComputeAQIInputDTO struct {
+	SensorId string
+	At       time.Time // end of the averaging periods, now when zero
+}
+
+type SensorAQIOutputDTO struct {
+	SensorId       string                    `json:"sensor_id,omitempty"`
+	Latitude       float64                   `json:"latitude"`
+	Longitude      float64                   `json:"longitude"`
+	Concentrations map[aqi.Pollutant]float64 `json:"concentrations"`
+	aqi.Result
+}
+
+type ComputeAQIOutputDTO struct {
+	At      time.Time             `json:"at"`
+	Sensors []*SensorAQIOutputDTO `json:"sensors"`
+}
+
+type ComputeAQIUseCase struct {
+	Repository repository.Repository
+	Standard   aqi.Standard
+}
+
+func NewComputeAQIUseCase(repository repository.Repository) *ComputeAQIUseCase {
+	return &ComputeAQIUseCase{
+		Repository: repository,
+		Standard:   aqi.USEPA,
+	}
+}
+
+// Execute returns the AQI of every sensor with readings of a pollutant. The concentration of
+// each pollutant is averaged over the period the standard sets for it, such as 24 hours for
+// PM2.5 and 8 hours for CO.
+func (uc *ComputeAQIUseCase) Execute(ctx context.Context, input *ComputeAQIInputDTO) (*ComputeAQIOutputDTO, error) {
+	at := input.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	type sensorKey struct {
+		id                  string
+		latitude, longitude float64
+	}
+	sensors := make(map[sensorKey]*SensorAQIOutputDTO)
+	var order []sensorKey
+
+	averaged := make(map[time.Duration]bool)
+	for _, pollutant := range uc.Standard.Pollutants() {
+		period := uc.Standard.AveragingPeriod(pollutant)
+		if averaged[period] {
+			continue
+		}
+		averaged[period] = true
+
+		filter := repository.ReadingFilter{SensorId: input.SensorId, From: at.Add(-period), To: at}
+		windows, err := uc.Repository.AggregateReadings(ctx, filter, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to average readings: %w", err)
+		}
+		for _, window := range windows {
+			key := sensorKey{window.SensorId, window.Latitude, window.Longitude}
+			sensor, ok := sensors[key]
+			if !ok {
+				sensor = &SensorAQIOutputDTO{
+					SensorId:       window.SensorId,
+					Latitude:       window.Latitude,
+					Longitude:      window.Longitude,
+					Concentrations: make(map[aqi.Pollutant]float64),
+				}
+				sensors[key] = sensor
+				order = append(order, key)
+			}
+			for parameter, stats := range window.Parameters {
+				p, ok := pollutantParameters[parameter]
+				if ok && uc.Standard.AveragingPeriod(p) == period {
+					sensor.Concentrations[p] = stats.Avg
+				}
+			}
+		}
+	}
+
+	output := &ComputeAQIOutputDTO{At: at, Sensors: []*SensorAQIOutputDTO{}}
+	for _, key := range order {
+		sensor := sensors[key]
+		result, err := uc.Standard.Compute(sensor.Concentrations)
+		if err != nil {
+			// The sensor measures none of the pollutants of the index.
+			continue
+		}
+		sensor.Result = result
+		output.Sensors = append(output.Sensors, sensor)
+	}
+	return output, nil
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type CountOutboxEntriesUseCase struct {
+	Repository repository.Repository
+}
+
+func NewCountOutboxEntriesUseCase(repository repository.Repository) *CountOutboxEntriesUseCase {
+	return &CountOutboxEntriesUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns the number of outbox entries in each state. States without entries are
+// reported with a zero count.
+func (uc *CountOutboxEntriesUseCase) Execute(ctx context.Context) (map[entity.OutboxState]int64, error) {
+	counts, err := uc.Repository.CountOutboxEntriesByState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count outbox entries: %w", err)
+	}
+	for _, state := range []entity.OutboxState{
+		entity.OutboxStatePendingMint,
+		entity.OutboxStateSubmitted,
+		entity.OutboxStateConfirmed,
+		entity.OutboxStateFailed,
+	} {
+		if _, ok := counts[state]; !ok {
+			counts[state] = 0
+		}
+	}
+	return counts, nil
+}
@@ -12,15 +12,23 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
 	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/tracing"
 )
 
 type CreateRewardInputDTO struct {
+	ReadingId string         `json:"reading_id"`
+	SensorId  string         `json:"sensor_id"`
 	Token     common.Address `json:"token"`
 	Amount    string         `json:"amount"`
 	Receiver  string         `json:"receiver"`
 	Latitude  float64        `json:"latitude"`
 	Longitude float64        `json:"longitude"`
+	EmittedAt time.Time      `json:"emitted_at"` // when the device took the reading
+	Sequence  int64          `json:"sequence"`   // increases with every reading of the device
 	Data      string         `json:"data"`
+	Signature string         `json:"signature"` // hex encoded signature of the device, see pkg/device
+	CapDay    time.Time      `json:"-"`         // UTC day the amount was reserved against the cap of the receiver, if it was
 }
 
 type CreateRewardOutputDTO struct {
@@ -32,6 +40,9 @@ type CreateRewardOutputDTO struct {
 	Longitude float64            `json:"longitude"`
 	TxHash    string             `json:"tx_hash"`
 	Data      string             `json:"data"`
+	ReadingId string             `json:"reading_id"`
+	SensorId  string             `json:"sensor_id,omitempty"`
+	Duplicate bool               `json:"duplicate"` // the reading was already rewarded, nothing was written
 }
 
 type CreateRewardUseCase struct {
@@ -47,36 +58,56 @@ func NewCreateRewardUseCase(repository repository.Repository) *CreateRewardUseCa
 func (uc *CreateRewardUseCase) Execute(ctx context.Context, input *CreateRewardInputDTO) (*CreateRewardOutputDTO, error) {
 	amount, ok := new(big.Int).SetString(input.Amount, 10)
 	if !ok {
-		return nil, errors.New("invalid amount")
+		return nil, fmt.Errorf("%w: invalid amount", entity.ErrInvalidReward)
+	}
+	if input.ReadingId == "" {
+		return nil, fmt.Errorf("%w: missing reading id", entity.ErrInvalidReward)
+	}
+	receiver, err := ethutil.ParseAddress(input.Receiver)
+	if err != nil {
+		return nil, errors.Join(entity.ErrInvalidReward, err)
 	}
 
-	existingReward, err := uc.Repository.FindRewardByLocation(ctx, input.Latitude, input.Longitude)
-
-	var result *entity.Reward
+	existingEntry, err := uc.Repository.FindOutboxEntryByReadingId(ctx, input.ReadingId)
+	if err == nil {
+		// The reading may be redelivered because recording it in the history failed.
+		if err := uc.recordMissing(ctx, existingEntry, input); err != nil {
+			return nil, err
+		}
+		return duplicateOutput(existingEntry), nil
+	} else if err != entity.ErrOutboxEntryNotFound {
+		return nil, fmt.Errorf("failed to check reading id: %w", err)
+	}
 
-	if err == nil && existingReward != nil {
-		existingReward.Data = input.Data
-		existingReward.Receiver = common.HexToAddress(input.Receiver).Hex()
-		existingReward.Amount = amount.String()
-		existingReward.UpdatedAt = time.Now()
+	result, err := entity.NewReward(input.Token, amount, receiver, input.Latitude, input.Longitude, input.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reward: %w", err)
+	}
+	result.Id = primitive.NewObjectID()
+	result.SensorId = input.SensorId
 
-		result, err = uc.Repository.UpdateReward(ctx, existingReward)
-		if err != nil {
-			return nil, fmt.Errorf("failed to update reward: %w", err)
-		}
-	} else if err == entity.ErrRewardNotFound {
-		var createErr error
-		newReward, createErr := entity.NewReward(input.Token, amount, common.HexToAddress(input.Receiver), input.Latitude, input.Longitude, input.Data)
-		if createErr != nil {
-			return nil, fmt.Errorf("failed to create reward: %w", createErr)
+	// The outbox entry is the reward: it is the only write that pays the reading, and what
+	// the blockchain dispatcher mints from. The Kafka offset is only committed after this
+	// write, so a failure here leads to a redelivery instead of a lost mint.
+	entry, err := entity.NewOutboxEntry(result, input.ReadingId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox entry: %w", err)
+	}
+	entry.CapDay = input.CapDay
+	entry.Trace = tracing.Inject(ctx)
+	if _, err := uc.Repository.CreateOutboxEntry(ctx, entry); err != nil {
+		if errors.Is(err, entity.ErrDuplicateReading) {
+			// Another worker saved the same reading in the meantime.
+			return duplicateOutput(entry), nil
 		}
+		return nil, fmt.Errorf("failed to save outbox entry: %w", err)
+	}
 
-		result, createErr = uc.Repository.CreateReward(ctx, newReward)
-		if createErr != nil {
-			return nil, fmt.Errorf("failed to save reward: %w", createErr)
-		}
-	} else {
-		return nil, fmt.Errorf("failed to check existing reward: %w", err)
+	// The history is written after the outbox entry, so that it only holds paid readings. If
+	// this fails, the reading is redelivered and recorded on the duplicate path above.
+	record := entity.NewRewardRecord(entry, result.Latitude, result.Longitude, result.Data)
+	if err := uc.Repository.CreateRewardRecord(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to record reward history: %w", err)
 	}
 
 	return &CreateRewardOutputDTO{
@@ -88,5 +119,34 @@ func (uc *CreateRewardUseCase) Execute(ctx context.Context, input *CreateRewardI
 		Longitude: result.Longitude,
 		TxHash:    result.TxHash,
 		Data:      result.Data,
+		ReadingId: input.ReadingId,
+		SensorId:  result.SensorId,
 	}, nil
 }
+
+// recordMissing adds a reading that was already paid to the history, unless it is there.
+func (uc *CreateRewardUseCase) recordMissing(ctx context.Context, entry *entity.OutboxEntry, input *CreateRewardInputDTO) error {
+	record := entity.NewRewardRecord(entry, input.Latitude, input.Longitude, input.Data)
+
+	_, err := uc.Repository.FindRewardRecordByReadingId(ctx, entry.ReadingId)
+	if errors.Is(err, entity.ErrRewardRecordNotFound) {
+		if err := uc.Repository.CreateRewardRecord(ctx, record); err != nil {
+			return fmt.Errorf("failed to record reward history: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to check reward history: %w", err)
+	}
+	return nil
+}
+
+func duplicateOutput(entry *entity.OutboxEntry) *CreateRewardOutputDTO {
+	return &CreateRewardOutputDTO{
+		Id:        entry.RewardId,
+		Token:     entry.Token,
+		Amount:    entry.Amount,
+		Receiver:  entry.Receiver,
+		TxHash:    entry.TxHash,
+		ReadingId: entry.ReadingId,
+		Duplicate: true,
+	}
+}
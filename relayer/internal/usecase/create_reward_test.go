@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func newRewardInput(readingId string) *CreateRewardInputDTO {
+	return &CreateRewardInputDTO{
+		ReadingId: readingId,
+		SensorId:  "sensor-1",
+		Token:     common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		Amount:    "1000",
+		Receiver:  "0x5AEDA56215b167893e80B4fE645BA6d5Bab767DE",
+		Latitude:  -23.55,
+		Longitude: -46.63,
+		Data:      `{"co2": 420, "mp25": 12}`,
+	}
+}
+
+func TestCreateReward(t *testing.T) {
+	repo := newFakeRepository()
+	uc := NewCreateRewardUseCase(repo)
+
+	first, err := uc.Execute(context.Background(), newRewardInput("reading-1"))
+	require.NoError(t, err)
+	second, err := uc.Execute(context.Background(), newRewardInput("reading-2"))
+	require.NoError(t, err)
+
+	// Every reading of a location is a reward of its own, paid by its outbox entry alone.
+	assert.NotEqual(t, first.Id, second.Id)
+	assert.Equal(t, []string{"outbox", "history", "outbox", "history"}, repo.writes)
+	entry := repo.outbox["reading-1"]
+	assert.Equal(t, first.Id, entry.RewardId)
+	assert.Equal(t, -23.55, entry.Latitude)
+	assert.Equal(t, -46.63, entry.Longitude)
+	assert.Equal(t, entity.OutboxStatePendingMint, entry.State)
+
+	duplicate, err := uc.Execute(context.Background(), newRewardInput("reading-1"))
+	require.NoError(t, err)
+	assert.True(t, duplicate.Duplicate)
+	assert.Equal(t, first.Id, duplicate.Id)
+	assert.Len(t, repo.writes, 4)
+}
+
+func TestCreateReward_RedeliveryAfterFailedRecord(t *testing.T) {
+	repo := newFakeRepository()
+	repo.recordErr = errors.New("history unavailable")
+	uc := NewCreateRewardUseCase(repo)
+
+	_, err := uc.Execute(context.Background(), newRewardInput("reading-1"))
+	require.ErrorIs(t, err, repo.recordErr)
+	assert.Equal(t, []string{"outbox"}, repo.writes)
+
+	// The reading is redelivered: it is not paid again, but recorded.
+	repo.recordErr = nil
+	output, err := uc.Execute(context.Background(), newRewardInput("reading-1"))
+	require.NoError(t, err)
+	assert.True(t, output.Duplicate)
+	assert.Equal(t, []string{"outbox", "history"}, repo.writes)
+	assert.Equal(t, repo.outbox["reading-1"].RewardId, repo.records["reading-1"].RewardId)
+}
+
+func TestCreateReward_Invalid(t *testing.T) {
+	repo := newFakeRepository()
+	uc := NewCreateRewardUseCase(repo)
+
+	input := newRewardInput("reading-1")
+	input.Amount = "0"
+	_, err := uc.Execute(context.Background(), input)
+	assert.ErrorIs(t, err, entity.ErrInvalidReward)
+
+	input = newRewardInput("reading-1")
+	input.Receiver = "0x5aeda56215b167893e80B4fE645BA6d5Bab767DE"
+	_, err = uc.Execute(context.Background(), input)
+	assert.ErrorIs(t, err, entity.ErrInvalidReward)
+	assert.Empty(t, repo.writes)
+}
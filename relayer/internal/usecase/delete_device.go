@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type DeleteDeviceUseCase struct {
+	Repository repository.Repository
+}
+
+func NewDeleteDeviceUseCase(repository repository.Repository) *DeleteDeviceUseCase {
+	return &DeleteDeviceUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute unregisters a sensor, whose readings no longer verify from then on.
+func (uc *DeleteDeviceUseCase) Execute(ctx context.Context, sensorId string) error {
+	if err := uc.Repository.DeleteDevice(ctx, sensorId); err != nil {
+		if errors.Is(err, entity.ErrDeviceNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+	return nil
+}
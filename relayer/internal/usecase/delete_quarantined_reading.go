@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type DeleteQuarantinedReadingUseCase struct {
+	Repository repository.Repository
+}
+
+func NewDeleteQuarantinedReadingUseCase(repository repository.Repository) *DeleteQuarantinedReadingUseCase {
+	return &DeleteQuarantinedReadingUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute removes a released reading from the quarantine, once it is no longer held.
+func (uc *DeleteQuarantinedReadingUseCase) Execute(ctx context.Context, readingId string) error {
+	if err := uc.Repository.DeleteQuarantinedReading(ctx, readingId); err != nil {
+		return fmt.Errorf("failed to delete quarantined reading: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+)
+
+type DeleteReceiverUseCase struct {
+	Repository repository.Repository
+}
+
+func NewDeleteReceiverUseCase(repository repository.Repository) *DeleteReceiverUseCase {
+	return &DeleteReceiverUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute unregisters a receiver, which the default policy applies to from then on.
+func (uc *DeleteReceiverUseCase) Execute(ctx context.Context, address string) error {
+	parsed, err := ethutil.ParseAddress(address)
+	if err != nil {
+		return errors.Join(entity.ErrInvalidReceiver, err)
+	}
+	if err := uc.Repository.DeleteReceiver(ctx, parsed.Hex()); err != nil {
+		if errors.Is(err, entity.ErrReceiverNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete receiver: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type DeleteSensorUseCase struct {
+	Repository repository.Repository
+}
+
+func NewDeleteSensorUseCase(repository repository.Repository) *DeleteSensorUseCase {
+	return &DeleteSensorUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute unregisters a sensor, whose readings are no longer rewarded from then on.
+func (uc *DeleteSensorUseCase) Execute(ctx context.Context, sensorId string) error {
+	if err := uc.Repository.DeleteSensor(ctx, sensorId); err != nil {
+		if errors.Is(err, entity.ErrSensorNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete sensor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type DownsampleRewardRecordsUseCase struct {
+	Repository repository.Repository
+}
+
+func NewDownsampleRewardRecordsUseCase(repository repository.Repository) *DownsampleRewardRecordsUseCase {
+	return &DownsampleRewardRecordsUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute rolls the reward history up per sensor and hour, so that it outlives the retention
+// of the records.
+func (uc *DownsampleRewardRecordsUseCase) Execute(ctx context.Context) error {
+	if err := uc.Repository.DownsampleRewardRecords(ctx); err != nil {
+		return fmt.Errorf("failed to downsample reward history: %w", err)
+	}
+	return nil
+}
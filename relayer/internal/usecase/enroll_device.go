@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/device"
+)
+
+type EnrollDeviceUseCase struct {
+	Repository repository.Repository
+}
+
+func NewEnrollDeviceUseCase(repository repository.Repository) *EnrollDeviceUseCase {
+	return &EnrollDeviceUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute registers the public key of a sensor that has none. Enrolling the registered key
+// again changes nothing, so that a device may enroll on every start, and another key fails
+// with ErrDeviceRegistered: only RegisterDeviceUseCase, behind the admin API, replaces keys.
+func (uc *EnrollDeviceUseCase) Execute(ctx context.Context, input *RegisterDeviceInputDTO) (*entity.Device, error) {
+	enrolled, err := entity.NewDevice(input.SensorId, input.PublicKey, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.Repository.CreateDevice(ctx, enrolled)
+	if err == nil {
+		return enrolled, nil
+	} else if !errors.Is(err, entity.ErrDeviceRegistered) {
+		return nil, fmt.Errorf("failed to create device: %w", err)
+	}
+
+	registered, err := uc.Repository.FindDevice(ctx, input.SensorId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find device: %w", err)
+	}
+	if !sameKey(registered.PublicKey, enrolled.PublicKey) {
+		return nil, fmt.Errorf("%w: %s", entity.ErrDeviceRegistered, input.SensorId)
+	}
+	return registered, nil
+}
+
+// sameKey reports whether two hex encoded public keys are the same key, whatever their case
+// or 0x prefix.
+func sameKey(a, b string) bool {
+	keyA, errA := device.ParsePublicKey(a)
+	keyB, errB := device.ParsePublicKey(b)
+	return errA == nil && errB == nil && keyA.Equal(keyB)
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func TestEnrollDevice(t *testing.T) {
+	ctx := context.Background()
+	key := func() string {
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		return hex.EncodeToString(public)
+	}
+	first, other := key(), key()
+
+	repo := newFakeRepository()
+	uc := NewEnrollDeviceUseCase(repo)
+
+	enrolled, err := uc.Execute(ctx, &RegisterDeviceInputDTO{SensorId: "sensor-1", PublicKey: first})
+	require.NoError(t, err)
+	assert.Equal(t, first, enrolled.PublicKey)
+
+	again, err := uc.Execute(ctx, &RegisterDeviceInputDTO{SensorId: "sensor-1", PublicKey: "0x" + strings.ToUpper(first)})
+	require.NoError(t, err)
+	assert.Equal(t, first, again.PublicKey)
+
+	_, err = uc.Execute(ctx, &RegisterDeviceInputDTO{SensorId: "sensor-1", PublicKey: other})
+	assert.ErrorIs(t, err, entity.ErrDeviceRegistered)
+	assert.Equal(t, first, repo.devices["sensor-1"].PublicKey)
+
+	_, err = uc.Execute(ctx, &RegisterDeviceInputDTO{SensorId: "sensor-2", PublicKey: "not a key"})
+	assert.ErrorIs(t, err, entity.ErrInvalidDevice)
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type FindDeviceUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindDeviceUseCase(repository repository.Repository) *FindDeviceUseCase {
+	return &FindDeviceUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc *FindDeviceUseCase) Execute(ctx context.Context, sensorId string) (*entity.Device, error) {
+	device, err := uc.Repository.FindDevice(ctx, sensorId)
+	if err != nil {
+		if errors.Is(err, entity.ErrDeviceNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find device: %w", err)
+	}
+	return device, nil
+}
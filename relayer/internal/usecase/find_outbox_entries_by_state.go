@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type FindOutboxEntriesByStateUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindOutboxEntriesByStateUseCase(repository repository.Repository) *FindOutboxEntriesByStateUseCase {
+	return &FindOutboxEntriesByStateUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc *FindOutboxEntriesByStateUseCase) Execute(ctx context.Context, state entity.OutboxState) ([]*entity.OutboxEntry, error) {
+	entries, err := uc.Repository.FindOutboxEntriesByState(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s outbox entries: %w", state, err)
+	}
+	return entries, nil
+}
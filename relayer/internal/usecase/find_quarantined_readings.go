@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+const (
+	DefaultQuarantinePageSize = 50
+	MaxQuarantinePageSize     = 500
+)
+
+var ErrInvalidQuarantineQuery = errors.New("invalid quarantine query")
+
+type FindQuarantinedReadingsInputDTO struct {
+	SensorId string
+	Limit    int64
+}
+
+type FindQuarantinedReadingsUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindQuarantinedReadingsUseCase(repository repository.Repository) *FindQuarantinedReadingsUseCase {
+	return &FindQuarantinedReadingsUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns the latest quarantined readings, newest first.
+func (uc *FindQuarantinedReadingsUseCase) Execute(ctx context.Context, input *FindQuarantinedReadingsInputDTO) ([]*entity.QuarantinedReading, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultQuarantinePageSize
+	}
+	if limit > MaxQuarantinePageSize {
+		return nil, fmt.Errorf("%w: limit above %d", ErrInvalidQuarantineQuery, MaxQuarantinePageSize)
+	}
+
+	readings, err := uc.Repository.FindQuarantinedReadings(ctx, input.SensorId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quarantined readings: %w", err)
+	}
+	return readings, nil
+}
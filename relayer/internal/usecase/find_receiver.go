@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+)
+
+type FindReceiverUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindReceiverUseCase(repository repository.Repository) *FindReceiverUseCase {
+	return &FindReceiverUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc *FindReceiverUseCase) Execute(ctx context.Context, address string) (*entity.Receiver, error) {
+	parsed, err := ethutil.ParseAddress(address)
+	if err != nil {
+		return nil, errors.Join(entity.ErrInvalidReceiver, err)
+	}
+	receiver, err := uc.Repository.FindReceiver(ctx, parsed.Hex())
+	if err != nil {
+		if errors.Is(err, entity.ErrReceiverNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find receiver: %w", err)
+	}
+	return receiver, nil
+}
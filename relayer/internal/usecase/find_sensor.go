@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type FindSensorUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindSensorUseCase(repository repository.Repository) *FindSensorUseCase {
+	return &FindSensorUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc *FindSensorUseCase) Execute(ctx context.Context, sensorId string) (*entity.Sensor, error) {
+	sensor, err := uc.Repository.FindSensor(ctx, sensorId)
+	if err != nil {
+		if errors.Is(err, entity.ErrSensorNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find sensor: %w", err)
+	}
+	return sensor, nil
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type FindSensorReputationUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindSensorReputationUseCase(repository repository.Repository) *FindSensorReputationUseCase {
+	return &FindSensorReputationUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns the reputation of a sensor with the latest reasons it lost score.
+func (uc *FindSensorReputationUseCase) Execute(ctx context.Context, sensorId string) (*entity.SensorReputation, error) {
+	reputation, err := uc.Repository.FindSensorReputation(ctx, sensorId)
+	if err != nil {
+		if errors.Is(err, entity.ErrReputationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find sensor reputation: %w", err)
+	}
+	return reputation, nil
+}
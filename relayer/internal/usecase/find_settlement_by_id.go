@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type FindSettlementByIdUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindSettlementByIdUseCase(repository repository.Repository) *FindSettlementByIdUseCase {
+	return &FindSettlementByIdUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns a settlement with its mint status and the events of its lifecycle.
+func (uc *FindSettlementByIdUseCase) Execute(ctx context.Context, id string) (*entity.Settlement, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid id", ErrInvalidSettlementQuery)
+	}
+	settlement, err := uc.Repository.FindSettlementById(ctx, objectId)
+	if err != nil {
+		if errors.Is(err, entity.ErrSettlementNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find settlement: %w", err)
+	}
+	return settlement, nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+const (
+	DefaultSettlementPageSize = 50
+	MaxSettlementPageSize     = 1000
+)
+
+var ErrInvalidSettlementQuery = errors.New("invalid settlement query")
+
+type FindSettlementsInputDTO struct {
+	Receiver  string
+	SensorId  string
+	Latitude  *float64 // with Longitude, the location of the reward
+	Longitude *float64
+	States    []entity.OutboxState
+	From      time.Time
+	To        time.Time
+	Cursor    string // NextCursor of the previous page
+	Limit     int64
+}
+
+type FindSettlementsOutputDTO struct {
+	Settlements []*entity.Settlement `json:"rewards"`
+	NextCursor  string               `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+type FindSettlementsUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindSettlementsUseCase(repository repository.Repository) *FindSettlementsUseCase {
+	return &FindSettlementsUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns a page of settlements, newest first.
+func (uc *FindSettlementsUseCase) Execute(ctx context.Context, input *FindSettlementsInputDTO) (*FindSettlementsOutputDTO, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultSettlementPageSize
+	}
+	if limit > MaxSettlementPageSize {
+		return nil, fmt.Errorf("%w: limit above %d", ErrInvalidSettlementQuery, MaxSettlementPageSize)
+	}
+
+	filter, err := uc.filter(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.Cursor != "" {
+		filter.Before, err = primitive.ObjectIDFromHex(input.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidSettlementQuery)
+		}
+	}
+	// One more than asked for tells whether there is a next page.
+	filter.Limit = limit + 1
+
+	settlements, err := uc.Repository.FindSettlements(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find settlements: %w", err)
+	}
+
+	output := &FindSettlementsOutputDTO{Settlements: settlements}
+	if int64(len(settlements)) > limit {
+		output.Settlements = settlements[:limit]
+		output.NextCursor = settlements[limit-1].Id.Hex()
+	}
+	if output.Settlements == nil {
+		output.Settlements = []*entity.Settlement{}
+	}
+	return output, nil
+}
+
+// filter validates the query.
+func (uc *FindSettlementsUseCase) filter(input *FindSettlementsInputDTO) (repository.SettlementFilter, error) {
+	filter, err := settlementFilter(input.Receiver, input.From, input.To)
+	if err != nil {
+		return filter, err
+	}
+	filter.SensorId = input.SensorId
+
+	for _, state := range input.States {
+		switch state {
+		case entity.OutboxStatePendingMint, entity.OutboxStateSubmitted, entity.OutboxStateConfirmed, entity.OutboxStateFailed:
+		default:
+			return filter, fmt.Errorf("%w: unknown state %q", ErrInvalidSettlementQuery, state)
+		}
+	}
+	filter.States = input.States
+
+	if (input.Latitude == nil) != (input.Longitude == nil) {
+		return filter, fmt.Errorf("%w: latitude and longitude go together", ErrInvalidSettlementQuery)
+	}
+	filter.Latitude = input.Latitude
+	filter.Longitude = input.Longitude
+
+	return filter, nil
+}
+
+// settlementFilter validates the filters shared by the settlement queries.
+func settlementFilter(receiver string, from, to time.Time) (repository.SettlementFilter, error) {
+	var filter repository.SettlementFilter
+	if receiver != "" {
+		if !common.IsHexAddress(receiver) {
+			return filter, fmt.Errorf("%w: invalid receiver address", ErrInvalidSettlementQuery)
+		}
+		// Receivers are stored checksummed.
+		filter.Receiver = common.HexToAddress(receiver).Hex()
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return filter, fmt.Errorf("%w: from must be before to", ErrInvalidSettlementQuery)
+	}
+	filter.From = from
+	filter.To = to
+	return filter, nil
+}
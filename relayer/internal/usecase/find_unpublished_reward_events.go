@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type FindUnpublishedRewardEventsUseCase struct {
+	Repository repository.Repository
+}
+
+func NewFindUnpublishedRewardEventsUseCase(repository repository.Repository) *FindUnpublishedRewardEventsUseCase {
+	return &FindUnpublishedRewardEventsUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns up to limit outbox entries that hold events not published yet.
+func (uc *FindUnpublishedRewardEventsUseCase) Execute(ctx context.Context, limit int64) ([]*entity.OutboxEntry, error) {
+	entries, err := uc.Repository.FindOutboxEntriesWithUnpublishedEvents(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unpublished reward events: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type MarkRewardEventPublishedUseCase struct {
+	Repository repository.Repository
+}
+
+func NewMarkRewardEventPublishedUseCase(repository repository.Repository) *MarkRewardEventPublishedUseCase {
+	return &MarkRewardEventPublishedUseCase{
+		Repository: repository,
+	}
+}
+
+func (uc *MarkRewardEventPublishedUseCase) Execute(ctx context.Context, entry *entity.OutboxEntry, event *entity.RewardEvent) error {
+	publishedAt := time.Now()
+	if err := uc.Repository.MarkRewardEventPublished(ctx, entry.Id, event.Id, publishedAt); err != nil {
+		return fmt.Errorf("failed to mark reward event %s as published: %w", event.Id, err)
+	}
+	event.PublishedAt = &publishedAt
+	return nil
+}
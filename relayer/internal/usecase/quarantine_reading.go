@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type QuarantineReadingInputDTO struct {
+	ReadingId  string
+	SensorId   string
+	Check      entity.QuarantineCheck
+	Reason     string
+	Topic      string
+	Payload    []byte
+	ReceivedAt time.Time
+	Receiver   string    // payout of a reward held by the receiver policy
+	Amount     string    // payout of a reward held by the receiver policy
+	ReleaseAt  time.Time // when to process it again, never when zero
+}
+
+type QuarantineReadingUseCase struct {
+	Repository repository.Repository
+}
+
+func NewQuarantineReadingUseCase(repository repository.Repository) *QuarantineReadingUseCase {
+	return &QuarantineReadingUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute holds back a reading as it was received, instead of rewarding it.
+func (uc *QuarantineReadingUseCase) Execute(ctx context.Context, input *QuarantineReadingInputDTO) error {
+	err := uc.Repository.QuarantineReading(ctx, &entity.QuarantinedReading{
+		ReadingId:  input.ReadingId,
+		SensorId:   input.SensorId,
+		Check:      input.Check,
+		Reason:     input.Reason,
+		Topic:      input.Topic,
+		Payload:    string(input.Payload),
+		ReceivedAt: input.ReceivedAt,
+		Receiver:   input.Receiver,
+		Amount:     input.Amount,
+		ReleaseAt:  input.ReleaseAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to quarantine reading: %w", err)
+	}
+	return nil
+}
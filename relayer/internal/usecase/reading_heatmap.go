@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+const DefaultHeatmapCellSize = 0.01 // degrees, about a kilometer
+
+var parameterPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+type ReadingHeatmapInputDTO struct {
+	Parameter string
+	From      time.Time
+	To        time.Time
+	CellSize  float64 // degrees of latitude and longitude
+}
+
+type ReadingHeatmapOutputDTO struct {
+	Parameter string                `json:"parameter"`
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	CellSize  float64               `json:"cell_size"`
+	Cells     []*entity.HeatmapCell `json:"cells"`
+}
+
+type ReadingHeatmapUseCase struct {
+	Repository repository.Repository
+}
+
+func NewReadingHeatmapUseCase(repository repository.Repository) *ReadingHeatmapUseCase {
+	return &ReadingHeatmapUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns a grid over the city with the minimum, maximum and average of a parameter
+// in every cell with readings.
+func (uc *ReadingHeatmapUseCase) Execute(ctx context.Context, input *ReadingHeatmapInputDTO) (*ReadingHeatmapOutputDTO, error) {
+	if !parameterPattern.MatchString(input.Parameter) {
+		return nil, fmt.Errorf("%w: invalid parameter", ErrInvalidReadingQuery)
+	}
+	cellSize := input.CellSize
+	if cellSize == 0 {
+		cellSize = DefaultHeatmapCellSize
+	}
+	if cellSize < 0 || cellSize > 10 {
+		return nil, fmt.Errorf("%w: cell size must be between 0 and 10 degrees", ErrInvalidReadingQuery)
+	}
+	filter, err := readingFilter("", input.From, input.To)
+	if err != nil {
+		return nil, err
+	}
+
+	cells, err := uc.Repository.ReadingHeatmap(ctx, filter, input.Parameter, cellSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute heatmap: %w", err)
+	}
+	if cells == nil {
+		cells = []*entity.HeatmapCell{}
+	}
+	return &ReadingHeatmapOutputDTO{
+		Parameter: input.Parameter,
+		From:      filter.From,
+		To:        filter.To,
+		CellSize:  cellSize,
+		Cells:     cells,
+	}, nil
+}
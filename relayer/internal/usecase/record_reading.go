@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/aqi"
+)
+
+type RecordReadingOutputDTO struct {
+	Reading   *entity.Reading `json:"reading"`
+	Duplicate bool            `json:"duplicate"` // the reading was already recorded, nothing was written
+}
+
+type RecordReadingUseCase struct {
+	Repository repository.Repository
+	Standard   aqi.Standard // scale of the index computed for every reading
+}
+
+func NewRecordReadingUseCase(repository repository.Repository) *RecordReadingUseCase {
+	return &RecordReadingUseCase{
+		Repository: repository,
+		Standard:   aqi.USEPA,
+	}
+}
+
+// Execute stores the values of a verified reading received at at, whether it is rewarded
+// later or not, so that the readings and the neighbor checks cover every sensor that
+// reports. It fails with entity.ErrInvalidReading when the data does not parse, and stores
+// nothing then.
+func (uc *RecordReadingUseCase) Execute(ctx context.Context, input *CreateRewardInputDTO, at time.Time) (*RecordReadingOutputDTO, error) {
+	existing, err := uc.Repository.FindReadingByReadingId(ctx, input.ReadingId)
+	if err == nil {
+		return &RecordReadingOutputDTO{Reading: existing, Duplicate: true}, nil
+	} else if !errors.Is(err, entity.ErrReadingNotFound) {
+		return nil, fmt.Errorf("failed to check reading: %w", err)
+	}
+
+	reading, err := entity.NewReading(input.ReadingId, input.SensorId, input.Latitude, input.Longitude, input.Data, at)
+	if err != nil {
+		return nil, err
+	}
+	reading.AQI = readingAQI(uc.Standard, reading.Values)
+	if err := uc.Repository.CreateReading(ctx, reading); err != nil {
+		return nil, fmt.Errorf("failed to save reading: %w", err)
+	}
+	return &RecordReadingOutputDTO{Reading: reading}, nil
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/aqi"
+)
+
+func TestRecordReading(t *testing.T) {
+	repo := newFakeRepository()
+	uc := NewRecordReadingUseCase(repo)
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	output, err := uc.Execute(context.Background(), newRewardInput("reading-1"), at)
+	require.NoError(t, err)
+	assert.False(t, output.Duplicate)
+	assert.Equal(t, map[string]float64{"co2": 420, "mp25": 12}, output.Reading.Values)
+	assert.Equal(t, at, output.Reading.RecordedAt)
+	require.NotNil(t, output.Reading.AQI)
+	assert.Equal(t, aqi.USEPA.Name, output.Reading.AQI.Standard)
+
+	// A redelivery is recorded once, and nothing is rewarded by recording it.
+	output, err = uc.Execute(context.Background(), newRewardInput("reading-1"), at.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, output.Duplicate)
+	assert.Equal(t, at, output.Reading.RecordedAt)
+	assert.Equal(t, []string{"readings"}, repo.writes)
+	assert.Empty(t, repo.outbox)
+}
+
+func TestRecordReading_InvalidData(t *testing.T) {
+	repo := newFakeRepository()
+	uc := NewRecordReadingUseCase(repo)
+
+	input := newRewardInput("reading-1")
+	input.Data = "co2=420"
+	_, err := uc.Execute(context.Background(), input, time.Now())
+	assert.ErrorIs(t, err, entity.ErrInvalidReading)
+	assert.Empty(t, repo.writes)
+}
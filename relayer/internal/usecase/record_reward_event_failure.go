@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type RecordRewardEventFailureUseCase struct {
+	Repository repository.Repository
+}
+
+func NewRecordRewardEventFailureUseCase(repository repository.Repository) *RecordRewardEventFailureUseCase {
+	return &RecordRewardEventFailureUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute counts a failed publication of event, which failed with reason.
+func (uc *RecordRewardEventFailureUseCase) Execute(ctx context.Context, entry *entity.OutboxEntry, event *entity.RewardEvent, reason string) error {
+	if err := uc.Repository.RecordRewardEventFailure(ctx, entry.Id, event.Id, reason); err != nil {
+		return fmt.Errorf("failed to record failure of reward event %s: %w", event.Id, err)
+	}
+	event.PublishFailures++
+	event.PublishError = reason
+	return nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type RegisterDeviceInputDTO struct {
+	SensorId  string `json:"sensor_id"`
+	PublicKey string `json:"public_key"`
+}
+
+type RegisterDeviceUseCase struct {
+	Repository repository.Repository
+}
+
+func NewRegisterDeviceUseCase(repository repository.Repository) *RegisterDeviceUseCase {
+	return &RegisterDeviceUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute registers the public key of a sensor, replacing the one it had.
+func (uc *RegisterDeviceUseCase) Execute(ctx context.Context, input *RegisterDeviceInputDTO) (*entity.Device, error) {
+	device, err := entity.NewDevice(input.SensorId, input.PublicKey, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	saved, err := uc.Repository.SaveDevice(ctx, device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save device: %w", err)
+	}
+	return saved, nil
+}
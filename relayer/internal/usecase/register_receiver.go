@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type RegisterReceiverInputDTO struct {
+	Address  string                `json:"address"`
+	Status   entity.ReceiverStatus `json:"status"`
+	ENSName  string                `json:"ens_name"`
+	DailyCap string                `json:"daily_cap"`
+	Reason   string                `json:"reason"`
+}
+
+type RegisterReceiverUseCase struct {
+	Repository repository.Repository
+}
+
+func NewRegisterReceiverUseCase(repository repository.Repository) *RegisterReceiverUseCase {
+	return &RegisterReceiverUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute allows or denies rewards to a receiver, replacing the policy it had.
+func (uc *RegisterReceiverUseCase) Execute(ctx context.Context, input *RegisterReceiverInputDTO) (*entity.Receiver, error) {
+	receiver, err := entity.NewReceiver(input.Address, input.Status, input.ENSName, input.DailyCap, input.Reason, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	saved, err := uc.Repository.SaveReceiver(ctx, receiver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save receiver: %w", err)
+	}
+	return saved, nil
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type RegisterSensorInputDTO struct {
+	SensorId string `json:"sensor_id"`
+	Receiver string `json:"receiver"`
+	Rate     string `json:"rate"`
+}
+
+type RegisterSensorUseCase struct {
+	Repository repository.Repository
+}
+
+func NewRegisterSensorUseCase(repository repository.Repository) *RegisterSensorUseCase {
+	return &RegisterSensorUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute registers the receiver and rate of a sensor, replacing the ones it had.
+func (uc *RegisterSensorUseCase) Execute(ctx context.Context, input *RegisterSensorInputDTO) (*entity.Sensor, error) {
+	sensor, err := entity.NewSensor(input.SensorId, input.Receiver, input.Rate, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	saved, err := uc.Repository.SaveSensor(ctx, sensor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save sensor: %w", err)
+	}
+	return saved, nil
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type ReleaseQuarantinedReadingUseCase struct {
+	Repository repository.Repository
+}
+
+func NewReleaseQuarantinedReadingUseCase(repository repository.Repository) *ReleaseQuarantinedReadingUseCase {
+	return &ReleaseQuarantinedReadingUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute makes a quarantined reading due to be processed again at the time at, from the
+// check that held it. Readings held for their signature fail with ErrNotReleasable.
+func (uc *ReleaseQuarantinedReadingUseCase) Execute(ctx context.Context, readingId string, at time.Time) (*entity.QuarantinedReading, error) {
+	reading, err := uc.Repository.FindQuarantinedReading(ctx, readingId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quarantined reading: %w", err)
+	}
+	if !reading.Releasable() {
+		return nil, fmt.Errorf("%w: held by the %s check", entity.ErrNotReleasable, checkName(reading.Check))
+	}
+
+	reading, err = uc.Repository.ReleaseQuarantinedReading(ctx, readingId, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release quarantined reading: %w", err)
+	}
+	return reading, nil
+}
+
+func checkName(check entity.QuarantineCheck) string {
+	if check == "" {
+		return "unrecorded"
+	}
+	return string(check)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+)
+
+func TestReleaseQuarantinedReading(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	repo := newFakeRepository()
+	repo.held["held"] = &entity.QuarantinedReading{ReadingId: "held", Check: entity.QuarantineReceiver}
+	repo.held["unsigned"] = &entity.QuarantinedReading{ReadingId: "unsigned", Check: entity.QuarantineSignature}
+	repo.held["legacy"] = &entity.QuarantinedReading{ReadingId: "legacy"}
+	uc := NewReleaseQuarantinedReadingUseCase(repo)
+
+	released, err := uc.Execute(ctx, "held", now)
+	require.NoError(t, err)
+	assert.Equal(t, now, released.ReleaseAt)
+
+	_, err = uc.Execute(ctx, "unsigned", now)
+	assert.ErrorIs(t, err, entity.ErrNotReleasable)
+	assert.True(t, repo.held["unsigned"].ReleaseAt.IsZero())
+
+	_, err = uc.Execute(ctx, "legacy", now)
+	assert.ErrorIs(t, err, entity.ErrNotReleasable)
+
+	_, err = uc.Execute(ctx, "missing", now)
+	assert.ErrorIs(t, err, entity.ErrQuarantinedReadingNotFound)
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type ReleaseReceiverAmountUseCase struct {
+	Repository repository.Repository
+}
+
+func NewReleaseReceiverAmountUseCase(repository repository.Repository) *ReleaseReceiverAmountUseCase {
+	return &ReleaseReceiverAmountUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute gives back to the daily cap of a receiver an amount reserved on day that will not
+// be paid, because its reward was not saved or its mint failed.
+func (uc *ReleaseReceiverAmountUseCase) Execute(ctx context.Context, receiver string, day time.Time, amount string) error {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return fmt.Errorf("%w: invalid amount", entity.ErrInvalidReward)
+	}
+	if err := uc.Repository.ReleaseReceiverAmount(ctx, receiver, day, value); err != nil {
+		return fmt.Errorf("failed to release daily cap: %w", err)
+	}
+	return nil
+}
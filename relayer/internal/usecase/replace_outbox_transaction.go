@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type ReplaceOutboxTransactionUseCase struct {
+	Repository repository.Repository
+}
+
+func NewReplaceOutboxTransactionUseCase(repository repository.Repository) *ReplaceOutboxTransactionUseCase {
+	return &ReplaceOutboxTransactionUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute stores txHash and rawTx as the transaction of a submitted entry, in place of its
+// current one. It fails with ErrOutboxEntryChanged when another dispatcher replaced or
+// settled the transaction first.
+func (uc *ReplaceOutboxTransactionUseCase) Execute(ctx context.Context, entry *entity.OutboxEntry, txHash string, rawTx string) error {
+	replaced := *entry
+	replaced.TxHash = txHash
+	replaced.RawTx = rawTx
+	replaced.LastError = ""
+	replaced.SubmittedAt = time.Now()
+	replaced.UpdatedAt = replaced.SubmittedAt
+	replaced.ReplacedTxs = append(slices.Clone(entry.ReplacedTxs), entry.TxHash)
+	if err := uc.Repository.ReplaceOutboxTransaction(ctx, &replaced, entry.TxHash); err != nil {
+		return fmt.Errorf("failed to replace outbox transaction: %w", err)
+	}
+	*entry = replaced
+	return nil
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+// fakeRepository keeps what the use cases under test write in memory. The methods no test
+// needs are left to the nil embedded Repository, and panic.
+type fakeRepository struct {
+	repository.Repository
+
+	mu        sync.Mutex
+	outbox    map[string]*entity.OutboxEntry        // by reading id
+	records   map[string]*entity.RewardRecord       // by reading id
+	readings  map[string]*entity.Reading            // by reading id
+	sequence  map[string]*entity.SensorSequence     // by sensor id
+	receivers map[string]*entity.Receiver           // by address
+	reserved  map[string]*big.Int                   // by receiver and UTC day
+	held      map[string]*entity.QuarantinedReading // by reading id
+	devices   map[string]*entity.Device             // by sensor id
+	writes    []string                              // collections written to, in order
+
+	// Errors returned by the writes to the history and the readings, when set.
+	recordErr  error
+	readingErr error
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		outbox:    make(map[string]*entity.OutboxEntry),
+		records:   make(map[string]*entity.RewardRecord),
+		readings:  make(map[string]*entity.Reading),
+		sequence:  make(map[string]*entity.SensorSequence),
+		receivers: make(map[string]*entity.Receiver),
+		reserved:  make(map[string]*big.Int),
+		held:      make(map[string]*entity.QuarantinedReading),
+		devices:   make(map[string]*entity.Device),
+	}
+}
+
+func (f *fakeRepository) CreateOutboxEntry(ctx context.Context, entry *entity.OutboxEntry) (*entity.OutboxEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.outbox[entry.ReadingId]; ok {
+		return nil, entity.ErrDuplicateReading
+	}
+	stored := *entry
+	f.outbox[entry.ReadingId] = &stored
+	f.writes = append(f.writes, "outbox")
+	return &stored, nil
+}
+
+func (f *fakeRepository) FindOutboxEntryByReadingId(ctx context.Context, readingId string) (*entity.OutboxEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.outbox[readingId]
+	if !ok {
+		return nil, entity.ErrOutboxEntryNotFound
+	}
+	found := *entry
+	return &found, nil
+}
+
+func (f *fakeRepository) CreateRewardRecord(ctx context.Context, record *entity.RewardRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.recordErr != nil {
+		return f.recordErr
+	}
+	f.records[record.ReadingId] = record
+	f.writes = append(f.writes, "history")
+	return nil
+}
+
+func (f *fakeRepository) FindRewardRecordByReadingId(ctx context.Context, readingId string) (*entity.RewardRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	record, ok := f.records[readingId]
+	if !ok {
+		return nil, entity.ErrRewardRecordNotFound
+	}
+	return record, nil
+}
+
+func (f *fakeRepository) CreateReading(ctx context.Context, reading *entity.Reading) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.readingErr != nil {
+		return f.readingErr
+	}
+	f.readings[reading.ReadingId] = reading
+	f.writes = append(f.writes, "readings")
+	return nil
+}
+
+func (f *fakeRepository) FindReadingByReadingId(ctx context.Context, readingId string) (*entity.Reading, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reading, ok := f.readings[readingId]
+	if !ok {
+		return nil, entity.ErrReadingNotFound
+	}
+	return reading, nil
+}
+
+func (f *fakeRepository) AdvanceSensorSequence(ctx context.Context, sequence *entity.SensorSequence) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	last, ok := f.sequence[sequence.SensorId]
+	if ok && last.Sequence >= sequence.Sequence && (last.Sequence != sequence.Sequence || last.ReadingId != sequence.ReadingId) {
+		return entity.ErrStaleSequence
+	}
+	stored := *sequence
+	f.sequence[sequence.SensorId] = &stored
+	f.writes = append(f.writes, "sequences")
+	return nil
+}
+
+func (f *fakeRepository) FindReceiver(ctx context.Context, address string) (*entity.Receiver, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	receiver, ok := f.receivers[address]
+	if !ok {
+		return nil, entity.ErrReceiverNotFound
+	}
+	return receiver, nil
+}
+
+func (f *fakeRepository) ReserveReceiverAmount(ctx context.Context, receiver string, day time.Time, amount, limit *big.Int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := receiver + "|" + day.Format(time.DateOnly)
+	total := new(big.Int)
+	if reserved, ok := f.reserved[key]; ok {
+		total.Set(reserved)
+	}
+	if total.Add(total, amount).Cmp(limit) > 0 {
+		return entity.ErrDailyCapReached
+	}
+	f.reserved[key] = total
+	f.writes = append(f.writes, "receiver_days")
+	return nil
+}
+
+func (f *fakeRepository) ReleaseReceiverAmount(ctx context.Context, receiver string, day time.Time, amount *big.Int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := receiver + "|" + day.Format(time.DateOnly)
+	if reserved, ok := f.reserved[key]; ok {
+		reserved.Sub(reserved, amount)
+	}
+	return nil
+}
+
+func (f *fakeRepository) FindQuarantinedReading(ctx context.Context, readingId string) (*entity.QuarantinedReading, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reading, ok := f.held[readingId]
+	if !ok {
+		return nil, entity.ErrQuarantinedReadingNotFound
+	}
+	found := *reading
+	return &found, nil
+}
+
+func (f *fakeRepository) ReleaseQuarantinedReading(ctx context.Context, readingId string, at time.Time) (*entity.QuarantinedReading, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reading, ok := f.held[readingId]
+	if !ok {
+		return nil, entity.ErrQuarantinedReadingNotFound
+	}
+	reading.ReleaseAt = at
+	released := *reading
+	f.writes = append(f.writes, "quarantine")
+	return &released, nil
+}
+
+func (f *fakeRepository) CreateDevice(ctx context.Context, device *entity.Device) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.devices[device.SensorId]; ok {
+		return entity.ErrDeviceRegistered
+	}
+	stored := *device
+	f.devices[device.SensorId] = &stored
+	return nil
+}
+
+func (f *fakeRepository) FindDevice(ctx context.Context, sensorId string) (*entity.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	device, ok := f.devices[sensorId]
+	if !ok {
+		return nil, entity.ErrDeviceNotFound
+	}
+	stored := *device
+	return &stored, nil
+}
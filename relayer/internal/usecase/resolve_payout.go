@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/ethutil"
+)
+
+// PayoutMode is where the receiver and amount of a reward come from.
+type PayoutMode string
+
+const (
+	// PayoutsRegistry pays the registered receiver and rate, whatever the reading claims.
+	PayoutsRegistry PayoutMode = "registry"
+	// PayoutsCheck pays the registered receiver and rate, and only if the reading claims
+	// the same ones.
+	PayoutsCheck PayoutMode = "check"
+	// PayoutsPayload pays what the reading claims.
+	PayoutsPayload PayoutMode = "payload"
+)
+
+// ErrPayoutRejected is returned for a reading whose sensor is not registered, or whose claimed
+// payout disagrees with the registered one.
+var ErrPayoutRejected = errors.New("payout rejected")
+
+func ParsePayoutMode(s string) (PayoutMode, error) {
+	switch mode := PayoutMode(s); mode {
+	case PayoutsRegistry, PayoutsCheck, PayoutsPayload:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid payout mode %q, expected registry, check or payload", s)
+}
+
+type ResolvePayoutOutputDTO struct {
+	Receiver string `json:"receiver"`
+	Amount   string `json:"amount"`
+}
+
+type ResolvePayoutUseCase struct {
+	Repository repository.Repository
+	Mode       PayoutMode
+}
+
+func NewResolvePayoutUseCase(repository repository.Repository, mode PayoutMode) *ResolvePayoutUseCase {
+	return &ResolvePayoutUseCase{
+		Repository: repository,
+		Mode:       mode,
+	}
+}
+
+// Execute returns the receiver and amount to reward a reading with. Outside of the payload
+// mode they come from the sensor registry, and it fails with ErrPayoutRejected for readings
+// of unregistered sensors or, in the check mode, that claim another payout.
+func (uc *ResolvePayoutUseCase) Execute(ctx context.Context, input *CreateRewardInputDTO) (*ResolvePayoutOutputDTO, error) {
+	if uc.Mode == PayoutsPayload {
+		return &ResolvePayoutOutputDTO{Receiver: input.Receiver, Amount: input.Amount}, nil
+	}
+	if input.SensorId == "" {
+		return nil, fmt.Errorf("%w: missing sensor id", ErrPayoutRejected)
+	}
+
+	sensor, err := uc.Repository.FindSensor(ctx, input.SensorId)
+	if errors.Is(err, entity.ErrSensorNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrPayoutRejected, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find sensor: %w", err)
+	}
+
+	if uc.Mode == PayoutsCheck {
+		if claimed, err := ethutil.ParseAddress(input.Receiver); err != nil || claimed != common.HexToAddress(sensor.Receiver) {
+			return nil, fmt.Errorf("%w: receiver %q is not the registered %s", ErrPayoutRejected, input.Receiver, sensor.Receiver)
+		}
+		claimed, ok := new(big.Int).SetString(input.Amount, 10)
+		rate, _ := new(big.Int).SetString(sensor.Rate, 10)
+		if !ok || rate == nil || claimed.Cmp(rate) != 0 {
+			return nil, fmt.Errorf("%w: amount %q is not the registered rate %s", ErrPayoutRejected, input.Amount, sensor.Rate)
+		}
+	}
+
+	return &ResolvePayoutOutputDTO{Receiver: sensor.Receiver, Amount: sensor.Rate}, nil
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type SetHistoryRetentionUseCase struct {
+	Repository repository.Repository
+}
+
+func NewSetHistoryRetentionUseCase(repository repository.Repository) *SetHistoryRetentionUseCase {
+	return &SetHistoryRetentionUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute makes the reward records expire after retention, or never when it is zero.
+func (uc *SetHistoryRetentionUseCase) Execute(ctx context.Context, retention time.Duration) error {
+	if retention < 0 {
+		return fmt.Errorf("history retention must not be negative")
+	}
+	if err := uc.Repository.SetHistoryRetention(ctx, retention); err != nil {
+		return fmt.Errorf("failed to set history retention: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type SumReceiverSettlementsInputDTO struct {
+	Receiver string
+	From     time.Time
+	To       time.Time
+}
+
+type SettlementTotalDTO struct {
+	Count  int64  `json:"count"`
+	Amount string `json:"amount"`
+}
+
+type SumReceiverSettlementsOutputDTO struct {
+	Receiver string             `json:"receiver"`
+	Paid     SettlementTotalDTO `json:"paid"`    // confirmed mints
+	Pending  SettlementTotalDTO `json:"pending"` // not minted yet, or without a receipt
+	Failed   SettlementTotalDTO `json:"failed"`
+}
+
+type SumReceiverSettlementsUseCase struct {
+	Repository repository.Repository
+}
+
+func NewSumReceiverSettlementsUseCase(repository repository.Repository) *SumReceiverSettlementsUseCase {
+	return &SumReceiverSettlementsUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute returns the amounts paid, pending and failed for a receiver.
+func (uc *SumReceiverSettlementsUseCase) Execute(ctx context.Context, input *SumReceiverSettlementsInputDTO) (*SumReceiverSettlementsOutputDTO, error) {
+	if input.Receiver == "" {
+		return nil, fmt.Errorf("%w: missing receiver", ErrInvalidSettlementQuery)
+	}
+	filter, err := settlementFilter(input.Receiver, input.From, input.To)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := uc.Repository.SumSettlementsByState(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum settlements: %w", err)
+	}
+
+	return &SumReceiverSettlementsOutputDTO{
+		Receiver: filter.Receiver,
+		Paid:     sumTotals(totals, entity.OutboxStateConfirmed),
+		Pending:  sumTotals(totals, entity.OutboxStatePendingMint, entity.OutboxStateSubmitted),
+		Failed:   sumTotals(totals, entity.OutboxStateFailed),
+	}, nil
+}
+
+func sumTotals(totals map[entity.OutboxState]repository.SettlementTotal, states ...entity.OutboxState) SettlementTotalDTO {
+	var count int64
+	amount := new(big.Int)
+	for _, state := range states {
+		if total, ok := totals[state]; ok {
+			count += total.Count
+			amount.Add(amount, total.Amount)
+		}
+	}
+	return SettlementTotalDTO{Count: count, Amount: amount.String()}
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+)
+
+type UpdateOutboxEntryUseCase struct {
+	Repository repository.Repository
+}
+
+func NewUpdateOutboxEntryUseCase(repository repository.Repository) *UpdateOutboxEntryUseCase {
+	return &UpdateOutboxEntryUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute saves the entry together with the lifecycle events caused by the change, as long
+// as it still holds guard. It fails with ErrOutboxEntryChanged otherwise.
+func (uc *UpdateOutboxEntryUseCase) Execute(ctx context.Context, entry *entity.OutboxEntry, guard entity.OutboxGuard, events ...entity.RewardEventType) error {
+	entry.UpdatedAt = time.Now()
+	recorded := make([]entity.RewardEvent, 0, len(events))
+	for _, eventType := range events {
+		recorded = append(recorded, entity.NewRewardEvent(entry, eventType))
+	}
+	if err := uc.Repository.UpdateOutboxEntry(ctx, entry, guard, recorded...); err != nil {
+		return fmt.Errorf("failed to update outbox entry: %w", err)
+	}
+	entry.Events = append(entry.Events, recorded...)
+	return nil
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/quality"
+)
+
+// saveReputationAttempts bounds the retries of a reading whose sensor reputation was updated
+// concurrently.
+const saveReputationAttempts = 3
+
+// metersPerDegree is the length of a degree of latitude.
+const metersPerDegree = 111320.0
+
+type ValidateReadingOutputDTO struct {
+	SensorId string            `json:"sensor_id"` // the sensor id, or the location of producers that do not send one
+	Findings []quality.Finding `json:"findings,omitempty"`
+	Rejected bool              `json:"rejected"`
+	Withheld bool              `json:"withheld"`
+	Score    float64           `json:"score"`
+	Amount   string            `json:"amount"` // amount to reward, scaled by the score
+}
+
+type ValidateReadingUseCase struct {
+	Repository     repository.Repository
+	Config         quality.Config
+	NeighborRadius float64       // meters around the sensor where readings are cross-checked
+	NeighborWindow time.Duration // how old the readings of the nearby sensors may be
+}
+
+func NewValidateReadingUseCase(repository repository.Repository, config quality.Config) *ValidateReadingUseCase {
+	return &ValidateReadingUseCase{
+		Repository: repository,
+		Config:     config,
+	}
+}
+
+// Execute checks a reading received at at, updates the reputation of its sensor and returns
+// whether to reward it and how much. A reading that was already rewarded passes unchecked,
+// and the last reading of a sensor gets the same verdict when it is redelivered.
+func (uc *ValidateReadingUseCase) Execute(ctx context.Context, input *CreateRewardInputDTO, at time.Time) (*ValidateReadingOutputDTO, error) {
+	sensorId := input.SensorId
+	if sensorId == "" {
+		sensorId = fmt.Sprintf("%g,%g", input.Latitude, input.Longitude)
+	}
+
+	_, err := uc.Repository.FindOutboxEntryByReadingId(ctx, input.ReadingId)
+	if err == nil {
+		return &ValidateReadingOutputDTO{SensorId: sensorId, Score: 1, Amount: input.Amount}, nil
+	} else if err != entity.ErrOutboxEntryNotFound {
+		return nil, fmt.Errorf("failed to check reading id: %w", err)
+	}
+
+	// Data that is not an object of numbers leaves no values, which the checks reject.
+	var values map[string]float64
+	if err := json.Unmarshal([]byte(input.Data), &values); err != nil {
+		values = nil
+	}
+
+	neighbors, err := uc.neighbors(ctx, input, at)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		reputation, err := uc.Repository.FindSensorReputation(ctx, sensorId)
+		if errors.Is(err, entity.ErrReputationNotFound) {
+			reputation = entity.NewSensorReputation(sensorId)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to load sensor reputation: %w", err)
+		}
+
+		if reputation.LastReadingId == input.ReadingId {
+			last := reputation.LastVerdict
+			return uc.output(sensorId, input, quality.Verdict{Rejected: last.Rejected, Withheld: last.Withheld, Score: last.Score})
+		}
+
+		verdict := uc.Config.Check(&reputation.State, values, at, neighbors)
+		reputation.Record(input.ReadingId, verdict, at)
+
+		err = uc.Repository.SaveSensorReputation(ctx, reputation)
+		if errors.Is(err, entity.ErrReputationConflict) && attempt < saveReputationAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save sensor reputation: %w", err)
+		}
+		return uc.output(sensorId, input, verdict)
+	}
+}
+
+// neighbors returns the latest values of the other sensors within NeighborRadius.
+func (uc *ValidateReadingUseCase) neighbors(ctx context.Context, input *CreateRewardInputDTO, at time.Time) ([]map[string]float64, error) {
+	if uc.NeighborRadius <= 0 || uc.NeighborWindow <= 0 {
+		return nil, nil
+	}
+
+	latitudeDelta := uc.NeighborRadius / metersPerDegree
+	longitudeDelta := latitudeDelta / math.Max(math.Cos(input.Latitude*math.Pi/180), 0.01)
+	readings, err := uc.Repository.FindLatestReadings(ctx, repository.ReadingArea{
+		South:           input.Latitude - latitudeDelta,
+		West:            input.Longitude - longitudeDelta,
+		North:           input.Latitude + latitudeDelta,
+		East:            input.Longitude + longitudeDelta,
+		Since:           at.Add(-uc.NeighborWindow),
+		ExcludeSensorId: input.SensorId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nearby readings: %w", err)
+	}
+
+	var neighbors []map[string]float64
+	for _, reading := range readings {
+		if reading.SensorId == input.SensorId && reading.Latitude == input.Latitude && reading.Longitude == input.Longitude {
+			continue
+		}
+		if quality.Distance(input.Latitude, input.Longitude, reading.Latitude, reading.Longitude) <= uc.NeighborRadius {
+			neighbors = append(neighbors, reading.Values)
+		}
+	}
+	return neighbors, nil
+}
+
+func (uc *ValidateReadingUseCase) output(sensorId string, input *CreateRewardInputDTO, verdict quality.Verdict) (*ValidateReadingOutputDTO, error) {
+	output := &ValidateReadingOutputDTO{
+		SensorId: sensorId,
+		Findings: verdict.Findings,
+		Rejected: verdict.Rejected,
+		Withheld: verdict.Withheld,
+		Score:    verdict.Score,
+		Amount:   input.Amount,
+	}
+	if verdict.Withheld || verdict.Score >= 1 {
+		return output, nil
+	}
+
+	amount, ok := new(big.Int).SetString(input.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid amount", entity.ErrInvalidReward)
+	}
+	// The score is applied with six decimals, which keeps the amount an integer.
+	share := big.NewInt(int64(math.Round(verdict.Score * 1e6)))
+	amount.Mul(amount, share).Quo(amount, big.NewInt(1e6))
+	output.Amount = amount.String()
+	return output, nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/relayer/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/relayer/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/device"
+)
+
+// ErrUnverifiedReading is returned for a reading that is not signed by the registered key of
+// its sensor.
+var ErrUnverifiedReading = errors.New("reading not verified")
+
+type VerifyReadingUseCase struct {
+	Repository repository.Repository
+}
+
+func NewVerifyReadingUseCase(repository repository.Repository) *VerifyReadingUseCase {
+	return &VerifyReadingUseCase{
+		Repository: repository,
+	}
+}
+
+// Execute checks that a reading is signed by the device registered for its sensor. It fails
+// with ErrUnverifiedReading when the reading has no sensor id, the sensor is not registered,
+// or the signature is missing or does not match the payload.
+func (uc *VerifyReadingUseCase) Execute(ctx context.Context, input *CreateRewardInputDTO) error {
+	if input.SensorId == "" {
+		return fmt.Errorf("%w: missing sensor id", ErrUnverifiedReading)
+	}
+
+	registered, err := uc.Repository.FindDevice(ctx, input.SensorId)
+	if errors.Is(err, entity.ErrDeviceNotFound) {
+		return fmt.Errorf("%w: %w", ErrUnverifiedReading, err)
+	} else if err != nil {
+		return fmt.Errorf("failed to find device: %w", err)
+	}
+
+	publicKey, err := device.ParsePublicKey(registered.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnverifiedReading, err)
+	}
+	payload := device.Payload{
+		ReadingId: input.ReadingId,
+		SensorId:  input.SensorId,
+		Receiver:  input.Receiver,
+		Amount:    input.Amount,
+		Latitude:  input.Latitude,
+		Longitude: input.Longitude,
+		EmittedAt: input.EmittedAt,
+		Sequence:  input.Sequence,
+		Data:      input.Data,
+	}
+	if err := device.Verify(publicKey, payload, input.Signature); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnverifiedReading, err)
+	}
+	return nil
+}
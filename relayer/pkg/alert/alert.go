@@ -0,0 +1,219 @@
+// Package alert raises and clears alerts when the air quality index of a zone stays beyond a
+// threshold.
+package alert
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventType string
+
+const (
+	EventRaised  EventType = "aqi.alert.raised"
+	EventCleared EventType = "aqi.alert.cleared"
+)
+
+// staleAfter is how long the last index of a source counts for its zone.
+const staleAfter = time.Hour
+
+// Rule raises an alert when the index of a zone stays at or above Raise for Sustain, and
+// clears it when the index stays at or below Clear for Sustain. Clear is below Raise, so an
+// index wavering around Raise does not flap.
+type Rule struct {
+	Name    string
+	Raise   int
+	Clear   int
+	Sustain time.Duration
+}
+
+// ParseRules parses comma separated rules written as name:raise:clear:sustain, such as
+// unhealthy:151:101:30m.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		parts := strings.Split(field, ":")
+		if len(parts) != 4 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid alert rule %q, expected name:raise:clear:sustain", field)
+		}
+		raise, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid raise level of alert rule %q: %w", parts[0], err)
+		}
+		clearLevel, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid clear level of alert rule %q: %w", parts[0], err)
+		}
+		if clearLevel >= raise {
+			return nil, fmt.Errorf("alert rule %q must clear below its raise level", parts[0])
+		}
+		sustain, err := time.ParseDuration(parts[3])
+		if err != nil || sustain < 0 {
+			return nil, fmt.Errorf("invalid sustain period of alert rule %q", parts[0])
+		}
+		rules = append(rules, Rule{Name: parts[0], Raise: raise, Clear: clearLevel, Sustain: sustain})
+	}
+	return rules, nil
+}
+
+// Zone is a cell of a grid over the map. Latitude and Longitude are its south-west corner.
+type Zone struct {
+	Id        string  `json:"id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Size      float64 `json:"size"`
+}
+
+// ZoneOf returns the zone of a location in a grid of cells of size degrees.
+func ZoneOf(latitude, longitude, size float64) Zone {
+	row := math.Floor(latitude / size)
+	col := math.Floor(longitude / size)
+	return Zone{
+		Id:        fmt.Sprintf("%g:%g:%g", size, row, col),
+		Latitude:  row * size,
+		Longitude: col * size,
+		Size:      size,
+	}
+}
+
+// Sample is the index of a reading of Source, such as a sensor, in a zone.
+type Sample struct {
+	Zone     Zone
+	Source   string
+	Standard string
+	Index    int
+	Category string
+	At       time.Time
+}
+
+// Event is a change of the alert of a rule in a zone.
+type Event struct {
+	Id         string    `json:"id"`
+	Type       EventType `json:"type"`
+	Rule       string    `json:"rule"`
+	Zone       Zone      `json:"zone"`
+	Standard   string    `json:"standard"`
+	Index      int       `json:"index"`
+	Category   string    `json:"category"`
+	Threshold  int       `json:"threshold"`
+	Since      time.Time `json:"since"` // when the index crossed the threshold
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type sourceSample struct {
+	index    int
+	category string
+	at       time.Time
+}
+
+type ruleState struct {
+	active bool
+	since  time.Time // when the index started to call for a change, zero when it does not
+}
+
+type zoneState struct {
+	sources map[string]sourceSample
+	rules   []ruleState
+}
+
+// Evaluator keeps the state of the rules in every zone. It is safe for concurrent use.
+type Evaluator struct {
+	mu    sync.Mutex
+	rules []Rule
+	zones map[string]*zoneState
+}
+
+func NewEvaluator(rules []Rule) *Evaluator {
+	return &Evaluator{
+		rules: rules,
+		zones: make(map[string]*zoneState),
+	}
+}
+
+// Observe records a sample and returns the alerts it raises or clears. The index of a zone is
+// the highest of the last indexes of its sources in the past hour.
+func (e *Evaluator) Observe(sample Sample) []Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.rules) == 0 {
+		return nil
+	}
+
+	zone, ok := e.zones[sample.Zone.Id]
+	if !ok {
+		zone = &zoneState{
+			sources: make(map[string]sourceSample),
+			rules:   make([]ruleState, len(e.rules)),
+		}
+		e.zones[sample.Zone.Id] = zone
+	}
+	zone.sources[sample.Source] = sourceSample{index: sample.Index, category: sample.Category, at: sample.At}
+
+	index, category := -1, ""
+	for source, s := range zone.sources {
+		if sample.At.Sub(s.at) > staleAfter {
+			delete(zone.sources, source)
+			continue
+		}
+		if s.index > index {
+			index, category = s.index, s.category
+		}
+	}
+
+	var events []Event
+	for i, rule := range e.rules {
+		state := &zone.rules[i]
+
+		crossing := index >= rule.Raise
+		if state.active {
+			crossing = index <= rule.Clear
+		}
+		if !crossing {
+			state.since = time.Time{}
+			continue
+		}
+		if state.since.IsZero() {
+			state.since = sample.At
+		}
+		if sample.At.Sub(state.since) < rule.Sustain {
+			continue
+		}
+
+		event := Event{
+			Id:         primitive.NewObjectID().Hex(),
+			Type:       EventRaised,
+			Rule:       rule.Name,
+			Zone:       sample.Zone,
+			Standard:   sample.Standard,
+			Index:      index,
+			Category:   category,
+			Threshold:  rule.Raise,
+			Since:      state.since,
+			OccurredAt: sample.At,
+		}
+		if state.active {
+			event.Type = EventCleared
+			event.Threshold = rule.Clear
+		}
+		events = append(events, event)
+		state.active = !state.active
+		state.since = time.Time{}
+	}
+	return events
+}
+
+// Key returns the key of the messages of an event, so that the alerts of a zone keep their order.
+func (e Event) Key() string {
+	return e.Zone.Id
+}
@@ -0,0 +1,138 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("unhealthy:151:101:30m, hazardous:301:201:0s,")
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Name: "unhealthy", Raise: 151, Clear: 101, Sustain: 30 * time.Minute},
+		{Name: "hazardous", Raise: 301, Clear: 201},
+	}, rules)
+
+	rules, err = ParseRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, invalid := range []string{
+		"unhealthy:151:101",
+		":151:101:30m",
+		"unhealthy:151:151:30m",
+		"unhealthy:high:101:30m",
+		"unhealthy:151:101:soon",
+	} {
+		_, err := ParseRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestZoneOf(t *testing.T) {
+	a := ZoneOf(-34.6037, -58.3816, 0.01)
+	b := ZoneOf(-34.6001, -58.3899, 0.01)
+	c := ZoneOf(-34.5999, -58.3816, 0.01)
+	assert.Equal(t, a.Id, b.Id)
+	assert.NotEqual(t, a.Id, c.Id)
+	assert.InDelta(t, -34.61, a.Latitude, 1e-9)
+	assert.InDelta(t, -58.39, a.Longitude, 1e-9)
+}
+
+func sample(zone Zone, source string, index int, at time.Time) Sample {
+	return Sample{Zone: zone, Source: source, Standard: "us-epa", Index: index, At: at}
+}
+
+func TestEvaluator_Sustain(t *testing.T) {
+	e := NewEvaluator([]Rule{{Name: "unhealthy", Raise: 151, Clear: 101, Sustain: 10 * time.Minute}})
+	zone := ZoneOf(-34.6, -58.4, 0.01)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Empty(t, e.Observe(sample(zone, "s1", 160, start)))
+	assert.Empty(t, e.Observe(sample(zone, "s1", 170, start.Add(5*time.Minute))))
+	// Dropping below the raise level restarts the period.
+	assert.Empty(t, e.Observe(sample(zone, "s1", 120, start.Add(6*time.Minute))))
+	assert.Empty(t, e.Observe(sample(zone, "s1", 160, start.Add(7*time.Minute))))
+	assert.Empty(t, e.Observe(sample(zone, "s1", 160, start.Add(16*time.Minute))))
+
+	events := e.Observe(sample(zone, "s1", 155, start.Add(17*time.Minute)))
+	require.Len(t, events, 1)
+	assert.Equal(t, EventRaised, events[0].Type)
+	assert.Equal(t, "unhealthy", events[0].Rule)
+	assert.Equal(t, 155, events[0].Index)
+	assert.Equal(t, 151, events[0].Threshold)
+	assert.Equal(t, start.Add(7*time.Minute), events[0].Since)
+	assert.Equal(t, zone.Id, events[0].Key())
+
+	// Raised once only.
+	assert.Empty(t, e.Observe(sample(zone, "s1", 200, start.Add(30*time.Minute))))
+}
+
+func TestEvaluator_Hysteresis(t *testing.T) {
+	e := NewEvaluator([]Rule{{Name: "unhealthy", Raise: 151, Clear: 101}})
+	zone := ZoneOf(-34.6, -58.4, 0.01)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	events := e.Observe(sample(zone, "s1", 151, start))
+	require.Len(t, events, 1)
+	assert.Equal(t, EventRaised, events[0].Type)
+
+	// Wavering between the levels does not clear the alert.
+	for i, index := range []int{140, 152, 102, 149} {
+		assert.Empty(t, e.Observe(sample(zone, "s1", index, start.Add(time.Duration(i+1)*time.Minute))))
+	}
+
+	events = e.Observe(sample(zone, "s1", 101, start.Add(10*time.Minute)))
+	require.Len(t, events, 1)
+	assert.Equal(t, EventCleared, events[0].Type)
+	assert.Equal(t, 101, events[0].Threshold)
+}
+
+func TestEvaluator_ZoneIndexIsTheHighestSource(t *testing.T) {
+	e := NewEvaluator([]Rule{{Name: "unhealthy", Raise: 151, Clear: 101}})
+	zone := ZoneOf(-34.6, -58.4, 0.01)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.Len(t, e.Observe(sample(zone, "s1", 180, start)), 1)
+	// Another sensor of the zone reading clean air does not clear the alert of s1.
+	assert.Empty(t, e.Observe(sample(zone, "s2", 20, start.Add(time.Minute))))
+
+	// Once s1 is stale, the zone index is the one of s2.
+	events := e.Observe(sample(zone, "s2", 20, start.Add(2*time.Hour)))
+	require.Len(t, events, 1)
+	assert.Equal(t, EventCleared, events[0].Type)
+	assert.Equal(t, 20, events[0].Index)
+
+	// Other zones are independent.
+	other := ZoneOf(-34.7, -58.4, 0.01)
+	assert.Empty(t, e.Observe(sample(other, "s3", 100, start.Add(2*time.Hour))))
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, string(EventRaised), r.Header.Get("X-Event-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := Event{Id: "1", Type: EventRaised, Rule: "unhealthy", Index: 160}
+	require.NoError(t, NewWebhookPublisher(server.URL).Publish(context.Background(), event))
+	assert.Equal(t, "unhealthy", received.Rule)
+	assert.Equal(t, 160, received.Index)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewWebhookPublisher(failing.URL).Publish(context.Background(), event))
+}
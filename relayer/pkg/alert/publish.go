@@ -0,0 +1,151 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/sink"
+)
+
+// Publisher delivers alert events to a destination.
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, event Event) error
+}
+
+// SinkPublisher publishes events as JSON to a topic of the sink of the relayer, keyed by zone
+// so that the alerts of a zone keep their order.
+type SinkPublisher struct {
+	sink  sink.Sink
+	topic string
+}
+
+func NewSinkPublisher(sink sink.Sink, topic string) *SinkPublisher {
+	return &SinkPublisher{sink: sink, topic: topic}
+}
+
+func (p *SinkPublisher) Name() string { return "sink" }
+
+func (p *SinkPublisher) Publish(ctx context.Context, event Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		"event_id":   event.Id,
+		"event_type": string(event.Type),
+	}
+	return p.sink.Publish(ctx, p.topic, []byte(event.Key()), value, headers)
+}
+
+// MQTTPublisher publishes events as JSON to a topic of an MQTT broker, with QoS 1.
+type MQTTPublisher struct {
+	client MQTT.Client
+	topic  string
+}
+
+// NewMQTTPublisher connects to the broker of conn, written as
+// mqtt://[user:password@]host:1883[?client_id=id], or mqtts:// for TLS.
+func NewMQTTPublisher(ctx context.Context, conn, topic string) (*MQTTPublisher, error) {
+	u, err := url.Parse(conn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mqtt connection string: %w", err)
+	}
+	scheme := "tcp"
+	if strings.EqualFold(u.Scheme, "mqtts") {
+		scheme = "ssl"
+	}
+
+	options := MQTT.NewClientOptions()
+	options.AddBroker(scheme + "://" + u.Host)
+	clientId := u.Query().Get("client_id")
+	if clientId == "" {
+		clientId = "city-fun-relayer-alerts"
+	}
+	options.SetClientID(clientId)
+	if u.User != nil {
+		options.SetUsername(u.User.Username())
+		password, _ := u.User.Password()
+		options.SetPassword(password)
+	}
+	options.SetAutoReconnect(true)
+
+	client := MQTT.NewClient(options)
+	token := client.Connect()
+	select {
+	case <-token.Done():
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("failed to connect to mqtt broker: %w", err)
+	}
+	return &MQTTPublisher{client: client, topic: topic}, nil
+}
+
+func (p *MQTTPublisher) Name() string { return "mqtt" }
+
+func (p *MQTTPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	token := p.client.Publish(p.topic, 1, false, payload)
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *MQTTPublisher) Close() {
+	p.client.Disconnect(250)
+}
+
+// WebhookPublisher posts events as JSON to a URL. Any status other than 2xx is an error.
+type WebhookPublisher struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookPublisher(url string) *WebhookPublisher {
+	return &WebhookPublisher{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *WebhookPublisher) Name() string { return "webhook" }
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", string(event.Type))
+	req.Header.Set("X-Event-Id", event.Id)
+
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded %s", p.URL, res.Status)
+	}
+	return nil
+}
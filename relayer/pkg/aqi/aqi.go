@@ -0,0 +1,241 @@
+// Package aqi computes air quality indexes from pollutant concentrations.
+package aqi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	ErrNoPollutants    = errors.New("no pollutant with a known concentration")
+	ErrUnknownStandard = errors.New("unknown air quality standard")
+)
+
+type Pollutant string
+
+const (
+	PM25 Pollutant = "pm25" // fine particulate matter, in µg/m³
+	PM10 Pollutant = "pm10" // particulate matter, in µg/m³
+	CO   Pollutant = "co"   // carbon monoxide, in ppm
+	NO2  Pollutant = "no2"  // nitrogen dioxide, in ppb
+)
+
+// Breakpoint maps the concentrations from Low to High linearly to the indexes from IndexLow
+// to IndexHigh.
+type Breakpoint struct {
+	Low       float64
+	High      float64
+	IndexLow  int
+	IndexHigh int
+}
+
+type Category struct {
+	Name     string
+	MaxIndex int
+}
+
+type pollutantScale struct {
+	period      time.Duration // averaging period of the concentration
+	factor      float64       // converts the unit of the pollutant to the one of the breakpoints
+	decimals    int           // concentrations are truncated to this precision
+	breakpoints []Breakpoint
+}
+
+// Standard is a set of breakpoints and categories. The index is the highest of the
+// sub-indexes of the pollutants.
+type Standard struct {
+	Name       string
+	pollutants map[Pollutant]pollutantScale
+	categories []Category
+}
+
+// USEPA is the AQI of the United States Environmental Protection Agency, with the PM2.5
+// breakpoints revised in 2024.
+var USEPA = Standard{
+	Name: "us-epa",
+	pollutants: map[Pollutant]pollutantScale{
+		PM25: {period: 24 * time.Hour, decimals: 1, breakpoints: []Breakpoint{
+			{0.0, 9.0, 0, 50},
+			{9.1, 35.4, 51, 100},
+			{35.5, 55.4, 101, 150},
+			{55.5, 125.4, 151, 200},
+			{125.5, 225.4, 201, 300},
+			{225.5, 325.4, 301, 500},
+		}},
+		PM10: {period: 24 * time.Hour, decimals: 0, breakpoints: []Breakpoint{
+			{0, 54, 0, 50},
+			{55, 154, 51, 100},
+			{155, 254, 101, 150},
+			{255, 354, 151, 200},
+			{355, 424, 201, 300},
+			{425, 604, 301, 500},
+		}},
+		CO: {period: 8 * time.Hour, decimals: 1, breakpoints: []Breakpoint{
+			{0.0, 4.4, 0, 50},
+			{4.5, 9.4, 51, 100},
+			{9.5, 12.4, 101, 150},
+			{12.5, 15.4, 151, 200},
+			{15.5, 30.4, 201, 300},
+			{30.5, 50.4, 301, 500},
+		}},
+		NO2: {period: time.Hour, decimals: 0, breakpoints: []Breakpoint{
+			{0, 53, 0, 50},
+			{54, 100, 51, 100},
+			{101, 360, 101, 150},
+			{361, 649, 151, 200},
+			{650, 1249, 201, 300},
+			{1250, 2049, 301, 500},
+		}},
+	},
+	categories: []Category{
+		{"good", 50},
+		{"moderate", 100},
+		{"unhealthy_for_sensitive_groups", 150},
+		{"unhealthy", 200},
+		{"very_unhealthy", 300},
+		{"hazardous", math.MaxInt},
+	},
+}
+
+// EU is the European Air Quality Index of the European Environment Agency, which Argentine
+// cities report as well. Its index is a band from 1, good, to 6, extremely poor. It does not
+// cover CO. NO2 is converted from ppb to µg/m³ at 25 °C.
+var EU = Standard{
+	Name: "eu",
+	pollutants: map[Pollutant]pollutantScale{
+		PM25: {period: 24 * time.Hour, decimals: 1, breakpoints: []Breakpoint{
+			{0, 10, 1, 1},
+			{10, 20, 2, 2},
+			{20, 25, 3, 3},
+			{25, 50, 4, 4},
+			{50, 75, 5, 5},
+			{75, 800, 6, 6},
+		}},
+		PM10: {period: 24 * time.Hour, decimals: 1, breakpoints: []Breakpoint{
+			{0, 20, 1, 1},
+			{20, 40, 2, 2},
+			{40, 50, 3, 3},
+			{50, 100, 4, 4},
+			{100, 150, 5, 5},
+			{150, 1200, 6, 6},
+		}},
+		NO2: {period: time.Hour, factor: 1.88, decimals: 1, breakpoints: []Breakpoint{
+			{0, 40, 1, 1},
+			{40, 90, 2, 2},
+			{90, 120, 3, 3},
+			{120, 230, 4, 4},
+			{230, 340, 5, 5},
+			{340, 1000, 6, 6},
+		}},
+	},
+	categories: []Category{
+		{"good", 1},
+		{"fair", 2},
+		{"moderate", 3},
+		{"poor", 4},
+		{"very_poor", 5},
+		{"extremely_poor", math.MaxInt},
+	},
+}
+
+// ParseStandard returns the standard with the given name: us-epa, or eu, also accepted as ar.
+func ParseStandard(name string) (Standard, error) {
+	switch name {
+	case USEPA.Name:
+		return USEPA, nil
+	case EU.Name, "ar":
+		return EU, nil
+	}
+	return Standard{}, fmt.Errorf("%w: %q", ErrUnknownStandard, name)
+}
+
+// Result is an index with the pollutant that set it.
+type Result struct {
+	Standard   string            `json:"standard"`
+	Index      int               `json:"index"`
+	Category   string            `json:"category"`
+	Dominant   Pollutant         `json:"dominant"`
+	SubIndexes map[Pollutant]int `json:"sub_indexes"`
+}
+
+// Pollutants returns the pollutants the standard has breakpoints for.
+func (s Standard) Pollutants() []Pollutant {
+	pollutants := make([]Pollutant, 0, len(s.pollutants))
+	for _, p := range []Pollutant{PM25, PM10, CO, NO2} {
+		if _, ok := s.pollutants[p]; ok {
+			pollutants = append(pollutants, p)
+		}
+	}
+	return pollutants
+}
+
+// AveragingPeriod returns the period over which the concentration of p is averaged, or zero
+// when the standard does not cover p.
+func (s Standard) AveragingPeriod(p Pollutant) time.Duration {
+	return s.pollutants[p].period
+}
+
+// SubIndex returns the index of a concentration of p. Concentrations above the last
+// breakpoint get the highest index. It returns false when the standard does not cover p.
+func (s Standard) SubIndex(p Pollutant, concentration float64) (int, bool) {
+	scale, ok := s.pollutants[p]
+	if !ok || len(scale.breakpoints) == 0 {
+		return 0, false
+	}
+
+	if scale.factor != 0 {
+		concentration *= scale.factor
+	}
+	shift := math.Pow(10, float64(scale.decimals))
+	// The epsilon keeps values such as 35.4 from being truncated to 35.3 by rounding errors.
+	c := math.Floor(math.Max(concentration, 0)*shift+1e-9) / shift
+
+	for _, b := range scale.breakpoints {
+		if c <= b.High {
+			if c < b.Low {
+				// Between two breakpoints after truncation, which only happens with
+				// concentrations more precise than the standard: use the upper one.
+				c = b.Low
+			}
+			index := float64(b.IndexHigh-b.IndexLow)/(b.High-b.Low)*(c-b.Low) + float64(b.IndexLow)
+			return int(math.Round(index)), true
+		}
+	}
+	return scale.breakpoints[len(scale.breakpoints)-1].IndexHigh, true
+}
+
+// Category returns the name of the category of an index.
+func (s Standard) Category(index int) string {
+	for _, c := range s.categories {
+		if index <= c.MaxIndex {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// Compute returns the index of the concentrations. The standard expects them averaged over
+// AveragingPeriod; the index of a single reading is only an indication. Pollutants the
+// standard does not cover are ignored.
+func (s Standard) Compute(concentrations map[Pollutant]float64) (Result, error) {
+	result := Result{Standard: s.Name, Index: -1, SubIndexes: make(map[Pollutant]int)}
+	for _, p := range s.Pollutants() {
+		concentration, ok := concentrations[p]
+		if !ok {
+			continue
+		}
+		index, _ := s.SubIndex(p, concentration)
+		result.SubIndexes[p] = index
+		if index > result.Index {
+			result.Index = index
+			result.Dominant = p
+		}
+	}
+	if len(result.SubIndexes) == 0 {
+		return Result{}, ErrNoPollutants
+	}
+	result.Category = s.Category(result.Index)
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package aqi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUSEPA_SubIndex(t *testing.T) {
+	cases := []struct {
+		pollutant     Pollutant
+		concentration float64
+		index         int
+	}{
+		{PM25, 0, 0},
+		{PM25, 9.0, 50},
+		{PM25, 9.09, 50}, // truncated to 9.0
+		{PM25, 35.4, 100},
+		{PM25, 40.0, 112},
+		{PM25, 1000, 500},
+		{PM10, 54.9, 50},
+		{PM10, 155, 101},
+		{CO, 9.4, 100},
+		{CO, 10.0, 109},
+		{NO2, 100, 100},
+		{NO2, 1130, 280},
+	}
+	for _, c := range cases {
+		index, ok := USEPA.SubIndex(c.pollutant, c.concentration)
+		assert.True(t, ok)
+		assert.Equal(t, c.index, index, "%s at %v", c.pollutant, c.concentration)
+	}
+
+	_, ok := USEPA.SubIndex("rad", 10)
+	assert.False(t, ok)
+}
+
+func TestUSEPA_Compute(t *testing.T) {
+	result, err := USEPA.Compute(map[Pollutant]float64{
+		PM25:  40.0,
+		PM10:  100,
+		CO:    2.0,
+		"co2": 900, // not covered, ignored
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 112, result.Index)
+	assert.Equal(t, PM25, result.Dominant)
+	assert.Equal(t, "unhealthy_for_sensitive_groups", result.Category)
+	assert.Len(t, result.SubIndexes, 3)
+
+	_, err = USEPA.Compute(map[Pollutant]float64{"co2": 900})
+	assert.ErrorIs(t, err, ErrNoPollutants)
+}
+
+func TestUSEPA_Category(t *testing.T) {
+	assert.Equal(t, "good", USEPA.Category(0))
+	assert.Equal(t, "moderate", USEPA.Category(51))
+	assert.Equal(t, "hazardous", USEPA.Category(500))
+}
+
+func TestEU_Compute(t *testing.T) {
+	result, err := EU.Compute(map[Pollutant]float64{
+		PM25: 22,
+		PM10: 15,
+		NO2:  30, // 56.4 µg/m³
+		CO:   10, // not covered, ignored
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Index)
+	assert.Equal(t, PM25, result.Dominant)
+	assert.Equal(t, "moderate", result.Category)
+	assert.Equal(t, map[Pollutant]int{PM25: 3, PM10: 1, NO2: 2}, result.SubIndexes)
+
+	index, _ := EU.SubIndex(PM25, 2000)
+	assert.Equal(t, 6, index)
+}
+
+func TestParseStandard(t *testing.T) {
+	standard, err := ParseStandard("us-epa")
+	require.NoError(t, err)
+	assert.Equal(t, USEPA.Name, standard.Name)
+
+	standard, err = ParseStandard("ar")
+	require.NoError(t, err)
+	assert.Equal(t, EU.Name, standard.Name)
+
+	_, err = ParseStandard("uk")
+	assert.ErrorIs(t, err, ErrUnknownStandard)
+}
@@ -0,0 +1,79 @@
+// Package device verifies that a reading was signed by the device that claims to have taken
+// it. Devices sign the canonical form of their payload with an ed25519 key, and keys and
+// signatures travel hex encoded.
+package device
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// canonicalVersion starts the canonical form, so that it can change without a signature of
+// one form verifying as another.
+const canonicalVersion = "city.fun/reading/v2"
+
+var (
+	ErrInvalidPublicKey = errors.New("invalid device public key")
+	ErrUnsigned         = errors.New("reading is not signed")
+	ErrInvalidSignature = errors.New("invalid reading signature")
+)
+
+// Payload is the signed part of a reading.
+type Payload struct {
+	ReadingId string
+	SensorId  string
+	Receiver  string
+	Amount    string
+	Latitude  float64
+	Longitude float64
+	EmittedAt time.Time
+	Sequence  int64
+	Data      string
+}
+
+// Canonical returns the bytes a device signs: the version and the fields of the payload, one
+// per line. Data is the last field, so that the lines it may hold cannot be mistaken for
+// other fields.
+func (p Payload) Canonical() []byte {
+	return []byte(strings.Join([]string{
+		canonicalVersion,
+		p.ReadingId,
+		p.SensorId,
+		p.Receiver,
+		p.Amount,
+		strconv.FormatFloat(p.Latitude, 'g', -1, 64),
+		strconv.FormatFloat(p.Longitude, 'g', -1, 64),
+		p.EmittedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatInt(p.Sequence, 10),
+		p.Data,
+	}, "\n"))
+}
+
+// ParsePublicKey decodes a hex encoded ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Verify checks the hex encoded signature of a payload against the public key of a device.
+func Verify(publicKey ed25519.PublicKey, payload Payload, signature string) error {
+	if signature == "" {
+		return ErrUnsigned
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed", ErrInvalidSignature)
+	}
+	if !ed25519.Verify(publicKey, payload.Canonical(), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package device
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var payload = Payload{
+	ReadingId: "6650b7c1e4b0a1a2b3c4d5e6",
+	SensorId:  "6650b7c1e4b0a1a2b3c4d5e7",
+	Receiver:  "0x8ba1f109551bD432803012645Ac136ddd64DBA72",
+	Amount:    "1000000000000000000",
+	Latitude:  -34.5775,
+	Longitude: -58.42,
+	EmittedAt: time.Date(2025, 1, 1, 12, 0, 0, 250000000, time.FixedZone("ART", -3*60*60)),
+	Sequence:  42,
+	Data:      `{"co2":512.5,"mp25":61}`,
+}
+
+func TestPayload_Canonical(t *testing.T) {
+	// The simulator signs the same form, any change must be made on both sides.
+	assert.Equal(t, "city.fun/reading/v2\n"+
+		"6650b7c1e4b0a1a2b3c4d5e6\n"+
+		"6650b7c1e4b0a1a2b3c4d5e7\n"+
+		"0x8ba1f109551bD432803012645Ac136ddd64DBA72\n"+
+		"1000000000000000000\n"+
+		"-34.5775\n"+
+		"-58.42\n"+
+		"2025-01-01T15:00:00.25Z\n"+
+		"42\n"+
+		`{"co2":512.5,"mp25":61}`, string(payload.Canonical()))
+}
+
+func TestVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signature := hex.EncodeToString(ed25519.Sign(privateKey, payload.Canonical()))
+
+	key, err := ParsePublicKey(hex.EncodeToString(publicKey))
+	require.NoError(t, err)
+	assert.NoError(t, Verify(key, payload, signature))
+	assert.NoError(t, Verify(key, payload, "0x"+signature))
+
+	tampered := payload
+	tampered.Receiver = "0x0000000000000000000000000000000000000001"
+	assert.ErrorIs(t, Verify(key, tampered, signature), ErrInvalidSignature)
+
+	otherKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, Verify(otherKey, payload, signature), ErrInvalidSignature)
+
+	assert.ErrorIs(t, Verify(key, payload, ""), ErrUnsigned)
+	assert.ErrorIs(t, Verify(key, payload, "zz"), ErrInvalidSignature)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	for _, invalid := range []string{"", "zz", "abcd", hex.EncodeToString(make([]byte, 33))} {
+		_, err := ParsePublicKey(invalid)
+		assert.ErrorIs(t, err, ErrInvalidPublicKey, invalid)
+	}
+}
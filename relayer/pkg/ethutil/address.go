@@ -0,0 +1,28 @@
+package ethutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidAddress is returned for a string that is not an address, or whose mixed case
+// does not match its EIP-55 checksum.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ParseAddress parses a 0x prefixed hex address. Unlike common.HexToAddress, it rejects
+// anything else instead of mapping it to some address. An address in mixed case must match
+// its EIP-55 checksum, while one in a single case carries no checksum and is accepted.
+func ParseAddress(s string) (common.Address, error) {
+	if !strings.HasPrefix(s, "0x") || !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("%w: %q", ErrInvalidAddress, s)
+	}
+	address := common.HexToAddress(s)
+	hex := s[2:]
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && address.Hex() != s {
+		return common.Address{}, fmt.Errorf("%w: %q does not match its checksum", ErrInvalidAddress, s)
+	}
+	return address, nil
+}
@@ -0,0 +1,35 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	const checksummed = "0x8ba1f109551bD432803012645Ac136ddd64DBA72"
+	for _, valid := range []string{
+		checksummed,
+		"0x8ba1f109551bd432803012645ac136ddd64dba72",
+		"0x8BA1F109551BD432803012645AC136DDD64DBA72",
+	} {
+		address, err := ParseAddress(valid)
+		require.NoError(t, err, valid)
+		assert.Equal(t, checksummed, address.Hex())
+	}
+
+	for _, invalid := range []string{
+		"",
+		"0x",
+		"garbage",
+		"8ba1f109551bD432803012645Ac136ddd64DBA72",
+		"0x8ba1f109551bD432803012645Ac136ddd64DBA7",
+		"0x8ba1f109551bD432803012645Ac136ddd64DBA72ff",
+		"0x8ba1f109551bd432803012645Ac136ddd64DBA72",
+		"0xzba1f109551bd432803012645ac136ddd64dba72",
+	} {
+		_, err := ParseAddress(invalid)
+		assert.ErrorIs(t, err, ErrInvalidAddress, invalid)
+	}
+}
@@ -0,0 +1,131 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Reservation is an amount of wei reserved on the budget of a UTC day for a transaction.
+type Reservation struct {
+	Day  time.Time
+	Cost *big.Int
+}
+
+// Budget keeps track of how much ETH was committed to gas during each UTC day, in a Ledger.
+// A transaction reserves its worst case cost when it is sent, and is settled at its real
+// cost once it has a receipt.
+type Budget struct {
+	limit  *big.Int
+	ledger Ledger
+	now    func() time.Time
+	mu     sync.Mutex
+	// day and spent are the last amount read from the ledger, for Spent.
+	day   time.Time
+	spent *big.Int
+}
+
+// NewBudget creates a daily budget kept in ledger, or in memory when ledger is nil. A nil
+// or zero limit disables the cap.
+func NewBudget(limit *big.Int, ledger Ledger) *Budget {
+	if ledger == nil {
+		ledger = NewMemoryLedger()
+	}
+	return &Budget{
+		limit:  limit,
+		ledger: ledger,
+		spent:  new(big.Int),
+		now:    time.Now,
+	}
+}
+
+// SetLimit changes the daily limit. A nil or zero limit disables the cap.
+func (b *Budget) SetLimit(limit *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.limit = limit
+}
+
+// Reserve commits cost to the current day, failing when it would go over the limit.
+func (b *Budget) Reserve(ctx context.Context, cost *big.Int) (*Reservation, error) {
+	b.mu.Lock()
+	limit := b.limit
+	b.mu.Unlock()
+
+	day := b.today()
+	spent, err := b.ledger.Reserve(ctx, day, cost, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve %s wei on %s: %w", cost, day.Format(time.DateOnly), err)
+	}
+	b.observe(day, spent)
+	return &Reservation{Day: day, Cost: new(big.Int).Set(cost)}, nil
+}
+
+// Release gives back a reservation for a transaction that was never sent.
+func (b *Budget) Release(ctx context.Context, reservation *Reservation) error {
+	spent, err := b.ledger.Adjust(ctx, reservation.Day, new(big.Int).Neg(reservation.Cost))
+	if err != nil {
+		return fmt.Errorf("failed to release %s wei on %s: %w", reservation.Cost, reservation.Day.Format(time.DateOnly), err)
+	}
+	b.observe(reservation.Day, spent)
+	return nil
+}
+
+// Settle replaces the reservations of a transaction, and of the transactions it replaced, by
+// what it actually cost. The cost is charged to the earliest reservations first, and what
+// is left of each reservation is given back to its day.
+func (b *Budget) Settle(ctx context.Context, reservations []Reservation, cost *big.Int) error {
+	remaining := new(big.Int).Set(cost)
+	for i, reservation := range reservations {
+		charged := new(big.Int).Set(reservation.Cost)
+		if remaining.Cmp(charged) < 0 {
+			charged.Set(remaining)
+		}
+		if i == len(reservations)-1 {
+			// A cost above the reservations, which the fee caps should prevent, is still
+			// counted.
+			charged.Set(remaining)
+		}
+		remaining.Sub(remaining, charged)
+
+		delta := new(big.Int).Sub(charged, reservation.Cost)
+		if delta.Sign() == 0 {
+			continue
+		}
+		spent, err := b.ledger.Adjust(ctx, reservation.Day, delta)
+		if err != nil {
+			return fmt.Errorf("failed to settle %s wei on %s: %w", delta, reservation.Day.Format(time.DateOnly), err)
+		}
+		b.observe(reservation.Day, spent)
+	}
+	return nil
+}
+
+// Spent returns the amount committed during the current day, as last read from the ledger.
+func (b *Budget) Spent() *big.Int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.day.Equal(b.today()) {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(b.spent)
+}
+
+func (b *Budget) today() time.Time {
+	return b.now().UTC().Truncate(24 * time.Hour)
+}
+
+func (b *Budget) observe(day time.Time, spent *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if day.Before(b.day) {
+		return
+	}
+	b.day = day
+	b.spent.Set(spent)
+}
@@ -0,0 +1,19 @@
+package gas
+
+import "errors"
+
+var (
+	ErrBaseFeeTooHigh = errors.New("base fee is above the configured maximum")
+
+	ErrDailyBudgetExceeded = errors.New("daily gas budget exceeded")
+
+	ErrNoFeeHistory = errors.New("fee history is empty")
+
+	ErrReplacementTooExpensive = errors.New("replacement fees are above the configured maximum")
+)
+
+// IsLimitExceeded reports whether err was caused by one of the spending caps,
+// meaning the transaction should be retried later instead of dropped.
+func IsLimitExceeded(err error) bool {
+	return errors.Is(err, ErrBaseFeeTooHigh) || errors.Is(err, ErrDailyBudgetExceeded)
+}
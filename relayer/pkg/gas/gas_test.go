@@ -0,0 +1,168 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackend struct {
+	history *ethereum.FeeHistory
+}
+
+func (f *fakeBackend) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return f.history, nil
+}
+
+func newHistory(baseFee int64, tips ...int64) *ethereum.FeeHistory {
+	history := &ethereum.FeeHistory{}
+	for _, tip := range tips {
+		history.Reward = append(history.Reward, []*big.Int{big.NewInt(tip)})
+		history.BaseFee = append(history.BaseFee, big.NewInt(baseFee))
+	}
+	history.BaseFee = append(history.BaseFee, big.NewInt(baseFee))
+	return history
+}
+
+func TestStrategy_Apply(t *testing.T) {
+	backend := &fakeBackend{history: newHistory(100, 3, 1, 2)}
+	strategy := NewStrategy(backend, Config{GasLimit: 1000, MaxBaseFee: big.NewInt(1000)}, nil)
+
+	opts := &bind.TransactOpts{}
+	reservation, err := strategy.Apply(context.Background(), opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, big.NewInt(2), opts.GasTipCap)
+	assert.Equal(t, big.NewInt(202), opts.GasFeeCap)
+	assert.Equal(t, uint64(1000), opts.GasLimit)
+	assert.Equal(t, big.NewInt(202_000), reservation.Cost)
+	assert.Equal(t, big.NewInt(202_000), strategy.Spent())
+
+	require.NoError(t, strategy.Refund(context.Background(), reservation))
+	assert.Equal(t, int64(0), strategy.Spent().Int64())
+}
+
+func TestStrategy_Apply_Caps(t *testing.T) {
+	backend := &fakeBackend{history: newHistory(100, 50)}
+	strategy := NewStrategy(backend, Config{
+		GasLimit:       1,
+		MaxBaseFee:     big.NewInt(150),
+		MaxPriorityFee: big.NewInt(10),
+	}, nil)
+
+	opts := &bind.TransactOpts{}
+	_, err := strategy.Apply(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), opts.GasTipCap)
+	assert.Equal(t, big.NewInt(160), opts.GasFeeCap)
+
+	backend.history = newHistory(151, 50)
+	_, err = strategy.Apply(context.Background(), opts)
+	assert.ErrorIs(t, err, ErrBaseFeeTooHigh)
+	assert.True(t, IsLimitExceeded(err))
+}
+
+func TestStrategy_Apply_DailyBudget(t *testing.T) {
+	backend := &fakeBackend{history: newHistory(10, 0)}
+	strategy := NewStrategy(backend, Config{GasLimit: 10, DailyBudget: big.NewInt(450)}, nil)
+
+	_, err := strategy.Apply(context.Background(), &bind.TransactOpts{})
+	require.NoError(t, err)
+	_, err = strategy.Apply(context.Background(), &bind.TransactOpts{})
+	require.NoError(t, err)
+	_, err = strategy.Apply(context.Background(), &bind.TransactOpts{})
+	assert.ErrorIs(t, err, ErrDailyBudgetExceeded)
+	assert.True(t, IsLimitExceeded(err))
+}
+
+func TestStrategy_SetConfig(t *testing.T) {
+	backend := &fakeBackend{history: newHistory(10, 0)}
+	strategy := NewStrategy(backend, Config{GasLimit: 10, DailyBudget: big.NewInt(250)}, nil)
+
+	_, err := strategy.Apply(context.Background(), &bind.TransactOpts{})
+	require.NoError(t, err)
+	_, err = strategy.Apply(context.Background(), &bind.TransactOpts{})
+	assert.ErrorIs(t, err, ErrDailyBudgetExceeded)
+
+	// The new caps apply right away, and what was spent today still counts.
+	strategy.SetConfig(Config{GasLimit: 20, DailyBudget: big.NewInt(600)})
+	opts := &bind.TransactOpts{}
+	_, err = strategy.Apply(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), opts.GasLimit)
+	assert.Equal(t, big.NewInt(600), strategy.Spent())
+}
+
+func TestBudget_Rollover(t *testing.T) {
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	budget := NewBudget(big.NewInt(100), nil)
+	budget.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	_, err := budget.Reserve(ctx, big.NewInt(100))
+	require.NoError(t, err)
+	_, err = budget.Reserve(ctx, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrDailyBudgetExceeded)
+
+	now = now.Add(2 * time.Hour)
+	_, err = budget.Reserve(ctx, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), budget.Spent())
+}
+
+func TestBudget_Settle(t *testing.T) {
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	ledger := NewMemoryLedger()
+	budget := NewBudget(big.NewInt(1000), ledger)
+	budget.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	first, err := budget.Reserve(ctx, big.NewInt(600))
+	require.NoError(t, err)
+	now = now.Add(2 * time.Hour)
+	second, err := budget.Reserve(ctx, big.NewInt(300))
+	require.NoError(t, err)
+
+	// The real cost is charged to the first day, and the second reservation is given back.
+	require.NoError(t, budget.Settle(ctx, []Reservation{*first, *second}, big.NewInt(450)))
+	spent, err := ledger.Adjust(ctx, first.Day, new(big.Int))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(450), spent)
+	assert.Equal(t, int64(0), budget.Spent().Int64())
+
+	// Once settled, the budget of the day has room again.
+	_, err = budget.Reserve(ctx, big.NewInt(1000))
+	assert.NoError(t, err)
+}
+
+func TestStrategy_Bump(t *testing.T) {
+	backend := &fakeBackend{history: newHistory(100, 2)}
+	strategy := NewStrategy(backend, Config{GasLimit: 1000, MaxBaseFee: big.NewInt(1000), MaxPriorityFee: big.NewInt(20)}, nil)
+	previous := types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(8), GasFeeCap: big.NewInt(160), Gas: 500})
+
+	// The current fee cap is above the bumped one, the current tip below it.
+	opts := &bind.TransactOpts{}
+	reservation, err := strategy.Bump(context.Background(), opts, previous)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(9), opts.GasTipCap)
+	assert.Equal(t, big.NewInt(202), opts.GasFeeCap)
+	assert.Equal(t, uint64(500), opts.GasLimit)
+	assert.Equal(t, big.NewInt(42*500), reservation.Cost)
+	assert.Equal(t, big.NewInt(42*500), strategy.Spent())
+
+	previous = types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(18), GasFeeCap: big.NewInt(300), Gas: 500})
+	_, err = strategy.Bump(context.Background(), opts, previous)
+	assert.ErrorIs(t, err, ErrReplacementTooExpensive)
+
+	previous = types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(8), GasFeeCap: big.NewInt(1000), Gas: 500})
+	_, err = strategy.Bump(context.Background(), opts, previous)
+	assert.ErrorIs(t, err, ErrReplacementTooExpensive)
+	assert.Equal(t, big.NewInt(42*500), strategy.Spent())
+}
@@ -0,0 +1,62 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Ledger stores the amount of wei committed to gas per UTC day. A shared ledger keeps the
+// daily budget across restarts and across the relayers of a deployment.
+type Ledger interface {
+	// Reserve adds cost to the amount of day and returns the new amount. With a positive
+	// limit, it fails with ErrDailyBudgetExceeded instead of going over it.
+	Reserve(ctx context.Context, day time.Time, cost, limit *big.Int) (*big.Int, error)
+	// Adjust adds delta, which may be negative, to the amount of day, and returns the new
+	// amount.
+	Adjust(ctx context.Context, day time.Time, delta *big.Int) (*big.Int, error)
+}
+
+// MemoryLedger is a Ledger that only lives as long as the process.
+type MemoryLedger struct {
+	mu    sync.Mutex
+	spent map[time.Time]*big.Int
+}
+
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{spent: make(map[time.Time]*big.Int)}
+}
+
+func (l *MemoryLedger) Reserve(ctx context.Context, day time.Time, cost, limit *big.Int) (*big.Int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	spent := l.day(day)
+	total := new(big.Int).Add(spent, cost)
+	if limit != nil && limit.Sign() > 0 && total.Cmp(limit) > 0 {
+		return nil, ErrDailyBudgetExceeded
+	}
+	spent.Set(total)
+	return new(big.Int).Set(spent), nil
+}
+
+func (l *MemoryLedger) Adjust(ctx context.Context, day time.Time, delta *big.Int) (*big.Int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	spent := l.day(day)
+	spent.Add(spent, delta)
+	return new(big.Int).Set(spent), nil
+}
+
+// day returns the amount of a day. It must be called with the lock held.
+func (l *MemoryLedger) day(day time.Time) *big.Int {
+	day = day.UTC().Truncate(24 * time.Hour)
+	spent, ok := l.spent[day]
+	if !ok {
+		spent = new(big.Int)
+		l.spent[day] = spent
+	}
+	return spent
+}
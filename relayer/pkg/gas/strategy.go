@@ -0,0 +1,226 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type Backend interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+type Config struct {
+	// Gas limit set on every transaction.
+	GasLimit uint64
+	// Transactions are not sent while the next block base fee is above this value (wei).
+	MaxBaseFee *big.Int
+	// Upper bound for the priority fee (wei).
+	MaxPriorityFee *big.Int
+	// Number of blocks sampled by eth_feeHistory.
+	FeeHistoryBlocks uint64
+	// Priority fee percentile sampled on each block, from 0 to 100.
+	FeeHistoryPercentile float64
+	// Maximum amount of wei that may be committed to gas per UTC day.
+	DailyBudget *big.Int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		GasLimit:             200_000,
+		FeeHistoryBlocks:     20,
+		FeeHistoryPercentile: 50,
+	}
+}
+
+// Strategy fills EIP-1559 fee fields on transact options and enforces the spending caps.
+type Strategy struct {
+	backend Backend
+	config  Config
+	budget  *Budget
+	mu      sync.RWMutex
+}
+
+// NewStrategy creates a strategy whose daily budget is kept in ledger, or in memory when
+// ledger is nil.
+func NewStrategy(backend Backend, config Config, ledger Ledger) *Strategy {
+	return &Strategy{
+		backend: backend,
+		config:  withDefaults(config),
+		budget:  NewBudget(config.DailyBudget, ledger),
+	}
+}
+
+// SetConfig replaces the caps of the strategy. The amount already spent today is kept, and
+// counts against the new daily budget.
+func (s *Strategy) SetConfig(config Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config = withDefaults(config)
+	s.budget.SetLimit(config.DailyBudget)
+}
+
+func withDefaults(config Config) Config {
+	if config.FeeHistoryBlocks == 0 {
+		config.FeeHistoryBlocks = DefaultConfig().FeeHistoryBlocks
+	}
+	if config.GasLimit == 0 {
+		config.GasLimit = DefaultConfig().GasLimit
+	}
+	return config
+}
+
+// Apply sets GasTipCap, GasFeeCap and GasLimit on opts and reserves the worst case
+// cost of the transaction on the daily budget. The returned reservation must be handed to
+// Refund if the transaction is not sent, and to Settle once it has a receipt.
+func (s *Strategy) Apply(ctx context.Context, opts *bind.TransactOpts) (*Reservation, error) {
+	s.mu.RLock()
+	config := s.config
+	s.mu.RUnlock()
+
+	tip, feeCap, err := s.fees(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := new(big.Int).Mul(feeCap, new(big.Int).SetUint64(config.GasLimit))
+	reservation, err := s.budget.Reserve(ctx, cost)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.GasPrice = nil
+	opts.GasTipCap = tip
+	opts.GasFeeCap = feeCap
+	opts.GasLimit = config.GasLimit
+	return reservation, nil
+}
+
+// Bump sets the fees of a replacement for previous on opts: the current fees, but at least
+// one eighth above the fees of previous, since nodes only accept a replacement that pays
+// more. The caps apply as in Apply. Only the increase is reserved on the daily budget,
+// because a single one of the transactions can be mined, and the returned reservation is
+// handled as the one of Apply.
+func (s *Strategy) Bump(ctx context.Context, opts *bind.TransactOpts, previous *types.Transaction) (*Reservation, error) {
+	s.mu.RLock()
+	config := s.config
+	s.mu.RUnlock()
+
+	tip, feeCap, err := s.fees(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	tip = maxInt(tip, raise(previous.GasTipCap()))
+	feeCap = maxInt(feeCap, raise(previous.GasFeeCap()))
+	if config.MaxPriorityFee != nil && config.MaxPriorityFee.Sign() > 0 && tip.Cmp(config.MaxPriorityFee) > 0 {
+		return nil, fmt.Errorf("%w: priority fee %s > %s wei", ErrReplacementTooExpensive, tip, config.MaxPriorityFee)
+	}
+	if config.MaxBaseFee != nil && config.MaxBaseFee.Sign() > 0 {
+		ceiling := new(big.Int).Add(config.MaxBaseFee, tip)
+		if feeCap.Cmp(ceiling) > 0 {
+			return nil, fmt.Errorf("%w: fee cap %s > %s wei", ErrReplacementTooExpensive, feeCap, ceiling)
+		}
+	}
+
+	increase := new(big.Int).Sub(feeCap, previous.GasFeeCap())
+	cost := increase.Mul(increase, new(big.Int).SetUint64(previous.Gas()))
+	reservation, err := s.budget.Reserve(ctx, cost)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.GasPrice = nil
+	opts.GasTipCap = tip
+	opts.GasFeeCap = feeCap
+	opts.GasLimit = previous.Gas()
+	return reservation, nil
+}
+
+// fees returns the priority fee and the fee cap for the next block, within the caps.
+func (s *Strategy) fees(ctx context.Context, config Config) (*big.Int, *big.Int, error) {
+	history, err := s.backend.FeeHistory(ctx, config.FeeHistoryBlocks, nil, []float64{config.FeeHistoryPercentile})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, nil, ErrNoFeeHistory
+	}
+
+	// The last entry is the base fee of the next block.
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	if config.MaxBaseFee != nil && config.MaxBaseFee.Sign() > 0 && baseFee.Cmp(config.MaxBaseFee) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s > %s wei", ErrBaseFeeTooHigh, baseFee, config.MaxBaseFee)
+	}
+
+	tip := medianReward(history.Reward)
+	if config.MaxPriorityFee != nil && config.MaxPriorityFee.Sign() > 0 && tip.Cmp(config.MaxPriorityFee) > 0 {
+		tip = new(big.Int).Set(config.MaxPriorityFee)
+	}
+
+	// Leave room for the base fee to double, but never above the configured ceiling.
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+	if config.MaxBaseFee != nil && config.MaxBaseFee.Sign() > 0 {
+		ceiling := new(big.Int).Add(config.MaxBaseFee, tip)
+		if feeCap.Cmp(ceiling) > 0 {
+			feeCap = ceiling
+		}
+	}
+	return tip, feeCap, nil
+}
+
+// Refund releases a reservation made by Apply or Bump for a transaction that was not sent.
+func (s *Strategy) Refund(ctx context.Context, reservation *Reservation) error {
+	if reservation == nil {
+		return nil
+	}
+	return s.budget.Release(ctx, reservation)
+}
+
+// Settle replaces the reservations made for a transaction and its replacements by cost, the
+// amount its receipt says it paid.
+func (s *Strategy) Settle(ctx context.Context, reservations []Reservation, cost *big.Int) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+	return s.budget.Settle(ctx, reservations, cost)
+}
+
+// Spent returns the amount of wei reserved on the budget during the current day.
+func (s *Strategy) Spent() *big.Int {
+	return s.budget.Spent()
+}
+
+func medianReward(rewards [][]*big.Int) *big.Int {
+	var values []*big.Int
+	for _, block := range rewards {
+		if len(block) > 0 && block[0] != nil {
+			values = append(values, block[0])
+		}
+	}
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+	return new(big.Int).Set(values[len(values)/2])
+}
+
+// raise returns value increased by one eighth, rounded up.
+func raise(value *big.Int) *big.Int {
+	increase := new(big.Int).Add(value, big.NewInt(7))
+	increase.Div(increase, big.NewInt(8))
+	return increase.Add(increase, value)
+}
+
+func maxInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return new(big.Int).Set(b)
+}
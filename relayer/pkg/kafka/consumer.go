@@ -1,47 +1,337 @@
 package kafka
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
 )
 
+// RebalanceFunc is called with the partitions that were assigned to or revoked from the
+// consumer. It runs on the polling goroutine, so no message is delivered while it runs.
+type RebalanceFunc func(partitions []ckafka.TopicPartition)
+
 type KafkaConsumer struct {
 	ConfigMap *ckafka.ConfigMap
-	Topics    []string
-	consumer  *ckafka.Consumer
+	// Topics are the topics subscribed by Consume. Use Subscribe to change them afterwards.
+	Topics []string
+	// DrainTimeout bounds how long a partition revocation waits for the messages of the
+	// revoked partitions that are still being processed. Zero waits indefinitely.
+	DrainTimeout time.Duration
+	// PollTimeout bounds every poll, and therefore how long Consume takes to notice that
+	// its context is done.
+	PollTimeout time.Duration
+	// StartAt, when set, moves every partition to the given position the first time it is
+	// assigned to this consumer, instead of resuming from the committed offset.
+	StartAt    *StartPosition
+	Logger     *slog.Logger
+	OnAssigned RebalanceFunc
+	OnRevoked  RebalanceFunc
+	consumer   *ckafka.Consumer
+	running    atomic.Bool
+	offsets    *OffsetTracker
+	started    map[partitionKey]bool
+	stats      atomic.Pointer[Stats]
+	topicsMu   sync.Mutex
+	rewindsMu  sync.Mutex
+	rewinds    map[partitionKey]ckafka.TopicPartition // by partition, the earliest message to read again
 }
 
 func NewKafkaConsumer(configMap *ckafka.ConfigMap, topics []string) *KafkaConsumer {
 	return &KafkaConsumer{
-		ConfigMap: configMap,
-		Topics:    topics,
+		ConfigMap:    configMap,
+		Topics:       topics,
+		DrainTimeout: 30 * time.Second,
+		PollTimeout:  100 * time.Millisecond,
+		Logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelInfo,
+		})),
+		offsets: NewOffsetTracker(),
+		started: make(map[partitionKey]bool),
+		rewinds: make(map[partitionKey]ckafka.TopicPartition),
 	}
 }
 
-func (c *KafkaConsumer) Consume(msgChan chan *ckafka.Message) error {
-	var err error
+// Consume subscribes to the topics and sends every message to msgChan until ctx is done or
+// the client reports a fatal error. Before returning it closes the consumer, which revokes
+// its partitions and so waits for the messages already delivered to be processed.
+func (c *KafkaConsumer) Consume(ctx context.Context, msgChan chan<- *ckafka.Message) (err error) {
 	c.consumer, err = ckafka.NewConsumer(c.ConfigMap)
 	if err != nil {
 		return fmt.Errorf("error creating kafka consumer: %w", err)
 	}
-	err = c.consumer.SubscribeTopics(c.Topics, nil)
+	c.running.Store(true)
+	defer func() {
+		// Closing revokes the partitions, so messages can still be marked as processed
+		// while it runs.
+		if closeErr := c.consumer.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("error closing kafka consumer: %w", closeErr)
+		}
+		c.running.Store(false)
+		c.Logger.Info("Kafka consumer closed")
+	}()
+
+	c.topicsMu.Lock()
+	topics := slices.Clone(c.Topics)
+	err = c.consumer.SubscribeTopics(topics, c.rebalance)
+	c.topicsMu.Unlock()
 	if err != nil {
 		return fmt.Errorf("error subscribing to topics: %w", err)
 	}
-	for {
-		msg, err := c.consumer.ReadMessage(-1)
-		if err == nil {
-			log.Printf("Message on %s: %s", msg.TopicPartition, string(msg.Value))
-			msgChan <- msg
-		} else {
-			log.Printf("Consumer error: %v\n", err)
+	c.Logger.Info("Kafka consumer subscribed", "topics", topics)
+
+	for ctx.Err() == nil {
+		c.rewind()
+		switch e := c.consumer.Poll(int(c.PollTimeout.Milliseconds())).(type) {
+		case *ckafka.Message:
+			if e.TopicPartition.Error != nil {
+				c.Logger.Error("Kafka message error",
+					"error", e.TopicPartition.Error,
+					"partition", e.TopicPartition.Partition,
+					"offset", e.TopicPartition.Offset)
+				continue
+			}
+
+			c.Logger.Debug("Kafka message received",
+				"topic", *e.TopicPartition.Topic,
+				"partition", e.TopicPartition.Partition,
+				"offset", e.TopicPartition.Offset)
+
+			c.offsets.Track(e.TopicPartition)
+			select {
+			case msgChan <- e:
+			case <-ctx.Done():
+				c.offsets.Untrack(e.TopicPartition)
+				return nil
+			}
+
+		case ckafka.Error:
+			if e.IsFatal() {
+				return fmt.Errorf("fatal kafka consumer error: %w", e)
+			}
+			c.Logger.Warn("Kafka consumer error", "error", e, "code", e.Code())
+
+		case *ckafka.Stats:
+			previous := c.stats.Load()
+			stats, err := ParseStats(e.String(), previous)
+			if err != nil {
+				c.Logger.Warn("Failed to parse Kafka statistics", "error", err)
+				continue
+			}
+			c.stats.Store(stats)
 		}
 	}
+	return nil
 }
 
-func (c *KafkaConsumer) CommitMessage(msg *ckafka.Message) error {
-	_, err := c.consumer.CommitMessage(msg)
+// MarkProcessed records that msg has been fully handled and commits the highest offset of its
+// partition below which every message has been handled. Offsets are never committed past a
+// message that is still being processed, so a crash can only cause redelivery, not loss.
+func (c *KafkaConsumer) MarkProcessed(msg *ckafka.Message) error {
+	if !c.running.Load() {
+		return ErrConsumerNotRunning
+	}
+	commit, ok := c.offsets.Done(msg.TopicPartition)
+	if !ok {
+		return nil
+	}
+	_, err := c.consumer.CommitOffsets([]ckafka.TopicPartition{commit})
 	return err
 }
+
+// Redeliver reads the partition of msg again from msg, for a message that could not be
+// handled. The messages of the partition read after it are delivered again too, and the
+// offsets committed stay below msg until it is marked as processed. The partition is moved
+// by the polling goroutine, before its next poll.
+func (c *KafkaConsumer) Redeliver(msg *ckafka.Message) error {
+	if !c.running.Load() {
+		return ErrConsumerNotRunning
+	}
+
+	c.rewindsMu.Lock()
+	defer c.rewindsMu.Unlock()
+
+	key := keyOf(msg.TopicPartition)
+	if pending, ok := c.rewinds[key]; !ok || msg.TopicPartition.Offset < pending.Offset {
+		c.rewinds[key] = msg.TopicPartition
+	}
+	return nil
+}
+
+// rewind moves the partitions of the messages to redeliver back to them. It runs on the
+// polling goroutine, so that no message read before the seek is tracked after it.
+func (c *KafkaConsumer) rewind() {
+	c.rewindsMu.Lock()
+	rewinds := c.rewinds
+	if len(rewinds) > 0 {
+		c.rewinds = make(map[partitionKey]ckafka.TopicPartition)
+	}
+	c.rewindsMu.Unlock()
+
+	for _, tp := range rewinds {
+		if !c.offsets.Rewind(tp) {
+			// Already processed, or its partition was revoked in the meantime.
+			continue
+		}
+		if err := c.consumer.Seek(tp, 0); err != nil {
+			c.Logger.Warn("Failed to seek Kafka partition back, the message is delivered again after the next rebalance",
+				"error", err,
+				"topic", *tp.Topic,
+				"partition", tp.Partition,
+				"offset", tp.Offset)
+			continue
+		}
+		c.Logger.Info("Kafka partition moved back to redeliver a message",
+			"topic", *tp.Topic,
+			"partition", tp.Partition,
+			"offset", tp.Offset)
+	}
+}
+
+// Subscribe replaces the subscribed topics. A running consumer rejoins its group, and the
+// partitions of the dropped topics are revoked as in any other rebalance.
+func (c *KafkaConsumer) Subscribe(topics []string) error {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+
+	if c.running.Load() {
+		if err := c.consumer.SubscribeTopics(topics, c.rebalance); err != nil {
+			return fmt.Errorf("error subscribing to topics: %w", err)
+		}
+		c.Logger.Info("Kafka consumer subscribed", "topics", topics)
+	}
+	c.Topics = slices.Clone(topics)
+	return nil
+}
+
+// Assignment returns the partitions currently assigned to the consumer.
+func (c *KafkaConsumer) Assignment() ([]ckafka.TopicPartition, error) {
+	if !c.running.Load() {
+		return nil, ErrConsumerNotRunning
+	}
+	return c.consumer.Assignment()
+}
+
+// Stats returns the latest statistics reported by the client, or nil if none was reported
+// yet. Statistics are only reported when statistics.interval.ms is set on the ConfigMap.
+func (c *KafkaConsumer) Stats() *Stats {
+	return c.stats.Load()
+}
+
+// rebalance lets the messages of revoked partitions finish before the partitions are handed
+// over to another member of the group, so that their offsets are committed by this one.
+// The assignment itself is left to the client, which applies it after the callback returns.
+func (c *KafkaConsumer) rebalance(consumer *ckafka.Consumer, event ckafka.Event) error {
+	switch e := event.(type) {
+	case ckafka.AssignedPartitions:
+		c.Logger.Info("Kafka partitions assigned", "partitions", e.Partitions)
+		if c.StartAt != nil {
+			if err := c.assignStartPosition(consumer, e.Partitions); err != nil {
+				c.Logger.Error("Failed to move Kafka partitions to the start position, resuming from the committed offsets",
+					"error", err,
+					"start_at", c.StartAt.String())
+			}
+		}
+		if c.OnAssigned != nil {
+			c.OnAssigned(e.Partitions)
+		}
+
+	case ckafka.RevokedPartitions:
+		ctx := context.Background()
+		if c.DrainTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.DrainTimeout)
+			defer cancel()
+		}
+
+		if remaining := c.offsets.Drain(ctx, e.Partitions); remaining > 0 {
+			c.Logger.Warn("Kafka partitions revoked with messages still in flight",
+				"partitions", e.Partitions,
+				"in_flight", remaining)
+		} else {
+			c.Logger.Info("Kafka partitions revoked and drained", "partitions", e.Partitions)
+		}
+		c.offsets.Forget(e.Partitions)
+		c.rewindsMu.Lock()
+		for _, tp := range e.Partitions {
+			delete(c.rewinds, keyOf(tp))
+		}
+		c.rewindsMu.Unlock()
+
+		if c.OnRevoked != nil {
+			c.OnRevoked(e.Partitions)
+		}
+	}
+	return nil
+}
+
+// assignStartPosition assigns the partitions, moving the ones that were never assigned before
+// to the start position. Partitions assigned again after a rebalance resume from their
+// committed offset.
+func (c *KafkaConsumer) assignStartPosition(consumer *ckafka.Consumer, partitions []ckafka.TopicPartition) error {
+	var fresh []ckafka.TopicPartition
+	for _, tp := range partitions {
+		if !c.started[keyOf(tp)] {
+			fresh = append(fresh, tp)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	moved, err := c.StartAt.offsets(consumer, fresh, 10*time.Second)
+	if err != nil {
+		return err
+	}
+
+	assignment := make([]ckafka.TopicPartition, 0, len(partitions))
+	byKey := make(map[partitionKey]ckafka.TopicPartition, len(moved))
+	for _, tp := range moved {
+		byKey[keyOf(tp)] = tp
+	}
+	for _, tp := range partitions {
+		if start, ok := byKey[keyOf(tp)]; ok {
+			tp = start
+		}
+		assignment = append(assignment, tp)
+	}
+
+	if consumer.GetRebalanceProtocol() == "COOPERATIVE" {
+		err = consumer.IncrementalAssign(assignment)
+	} else {
+		err = consumer.Assign(assignment)
+	}
+	if err != nil {
+		return fmt.Errorf("error assigning partitions: %w", err)
+	}
+
+	// Commit the start offsets right away, so that the group is moved even if a rebalance
+	// happens before any message is processed. Logical offsets cannot be committed.
+	var commit []ckafka.TopicPartition
+	for _, tp := range moved {
+		if tp.Offset >= 0 {
+			commit = append(commit, tp)
+		}
+	}
+	if len(commit) > 0 {
+		if _, err := consumer.CommitOffsets(commit); err != nil {
+			c.Logger.Warn("Failed to commit Kafka start offsets", "error", err)
+		}
+	}
+
+	for _, tp := range moved {
+		c.started[keyOf(tp)] = true
+		c.Logger.Info("Kafka partition moved to the start position",
+			"topic", *tp.Topic,
+			"partition", tp.Partition,
+			"offset", tp.Offset,
+			"start_at", c.StartAt.String())
+	}
+	return nil
+}
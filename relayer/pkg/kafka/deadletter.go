@@ -0,0 +1,193 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// DeadLetter is the value written to the dead-letter topic for a message that could
+// not be processed.
+type DeadLetter struct {
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Offset    int64     `json:"offset"`
+	Key       string    `json:"key,omitempty"`
+	Payload   string    `json:"payload"`
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// DeadLetterQueue reads and manages the dead-letter topic.
+type DeadLetterQueue struct {
+	ConfigMap *ckafka.ConfigMap
+	Topic     string
+	// Reading stops after this long without new messages.
+	IdleTimeout time.Duration
+}
+
+func NewDeadLetterQueue(configMap *ckafka.ConfigMap, topic string) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		ConfigMap:   configMap,
+		Topic:       topic,
+		IdleTimeout: 5 * time.Second,
+	}
+}
+
+// Inspect calls fn for every message in the dead-letter topic, from the beginning,
+// without committing anything. A limit of zero reads everything.
+func (q *DeadLetterQueue) Inspect(ctx context.Context, limit int, fn func(*ckafka.Message, *DeadLetter) error) (int, error) {
+	consumer, err := q.newConsumer(q.groupId("inspect"))
+	if err != nil {
+		return 0, err
+	}
+	defer consumer.Close()
+
+	partitions, err := q.partitions(consumer)
+	if err != nil {
+		return 0, err
+	}
+	for i := range partitions {
+		partitions[i].Offset = ckafka.OffsetBeginning
+	}
+	if err := consumer.Assign(partitions); err != nil {
+		return 0, fmt.Errorf("error assigning dead-letter partitions: %w", err)
+	}
+
+	return q.read(ctx, consumer, limit, fn)
+}
+
+// Replay calls fn for every dead letter not replayed yet and commits it once fn succeeds.
+// Progress is kept by a dedicated consumer group, so a message is replayed only once.
+func (q *DeadLetterQueue) Replay(ctx context.Context, limit int, fn func(*DeadLetter) error) (int, error) {
+	consumer, err := q.newConsumer(q.groupId("replay"))
+	if err != nil {
+		return 0, err
+	}
+	defer consumer.Close()
+
+	if err := consumer.Subscribe(q.Topic, nil); err != nil {
+		return 0, fmt.Errorf("error subscribing to dead-letter topic: %w", err)
+	}
+
+	return q.read(ctx, consumer, limit, func(msg *ckafka.Message, deadLetter *DeadLetter) error {
+		if err := fn(deadLetter); err != nil {
+			return err
+		}
+		if _, err := consumer.CommitMessage(msg); err != nil {
+			return fmt.Errorf("error committing dead-letter offset: %w", err)
+		}
+		return nil
+	})
+}
+
+// Purge deletes every message currently stored in the dead-letter topic and returns
+// how many were removed.
+func (q *DeadLetterQueue) Purge(ctx context.Context) (int64, error) {
+	consumer, err := q.newConsumer(q.groupId("purge"))
+	if err != nil {
+		return 0, err
+	}
+	defer consumer.Close()
+
+	partitions, err := q.partitions(consumer)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for i, partition := range partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(q.Topic, partition.Partition, 5000)
+		if err != nil {
+			return 0, fmt.Errorf("error querying watermarks for partition %d: %w", partition.Partition, err)
+		}
+		total += high - low
+		partitions[i].Offset = ckafka.Offset(high)
+	}
+
+	admin, err := ckafka.NewAdminClientFromConsumer(consumer)
+	if err != nil {
+		return 0, fmt.Errorf("error creating admin client: %w", err)
+	}
+	defer admin.Close()
+
+	results, err := admin.DeleteRecords(ctx, partitions)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting dead letters: %w", err)
+	}
+	for _, result := range results.DeleteRecordsResults {
+		if result.TopicPartition.Error != nil {
+			return 0, fmt.Errorf("error deleting dead letters on partition %d: %w",
+				result.TopicPartition.Partition, result.TopicPartition.Error)
+		}
+	}
+	return total, nil
+}
+
+func (q *DeadLetterQueue) read(ctx context.Context, consumer *ckafka.Consumer, limit int, fn func(*ckafka.Message, *DeadLetter) error) (int, error) {
+	count := 0
+	for limit <= 0 || count < limit {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		msg, err := consumer.ReadMessage(q.IdleTimeout)
+		if err != nil {
+			if kerr, ok := err.(ckafka.Error); ok && kerr.IsTimeout() {
+				return count, nil
+			}
+			return count, fmt.Errorf("error reading dead letter: %w", err)
+		}
+
+		var deadLetter DeadLetter
+		if err := json.Unmarshal(msg.Value, &deadLetter); err != nil {
+			return count, fmt.Errorf("invalid dead letter at %s: %w", msg.TopicPartition, err)
+		}
+		if err := fn(msg, &deadLetter); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (q *DeadLetterQueue) newConsumer(groupId string) (*ckafka.Consumer, error) {
+	configMap := ckafka.ConfigMap{}
+	for key, value := range *q.ConfigMap {
+		configMap[key] = value
+	}
+	configMap["group.id"] = groupId
+	configMap["enable.auto.commit"] = false
+	configMap["auto.offset.reset"] = "earliest"
+
+	consumer, err := ckafka.NewConsumer(&configMap)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kafka consumer: %w", err)
+	}
+	return consumer, nil
+}
+
+func (q *DeadLetterQueue) partitions(consumer *ckafka.Consumer) ([]ckafka.TopicPartition, error) {
+	metadata, err := consumer.GetMetadata(&q.Topic, false, 5000)
+	if err != nil {
+		return nil, fmt.Errorf("error getting dead-letter topic metadata: %w", err)
+	}
+	topic, ok := metadata.Topics[q.Topic]
+	if !ok || topic.Error.Code() != ckafka.ErrNoError {
+		return nil, fmt.Errorf("dead-letter topic %s not found", q.Topic)
+	}
+
+	partitions := make([]ckafka.TopicPartition, 0, len(topic.Partitions))
+	for _, partition := range topic.Partitions {
+		partitions = append(partitions, ckafka.TopicPartition{Topic: &q.Topic, Partition: partition.ID})
+	}
+	return partitions, nil
+}
+
+func (q *DeadLetterQueue) groupId(purpose string) string {
+	return q.Topic + "-" + purpose
+}
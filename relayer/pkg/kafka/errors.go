@@ -0,0 +1,7 @@
+package kafka
+
+import "errors"
+
+var (
+	ErrConsumerNotRunning = errors.New("kafka consumer is not running")
+)
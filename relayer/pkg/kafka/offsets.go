@@ -0,0 +1,194 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+type partitionOffsets struct {
+	// pending holds the offsets handed out for processing, in the order they were read.
+	pending []ckafka.Offset
+	done    map[ckafka.Offset]bool
+}
+
+// OffsetTracker keeps, per partition, the offsets that are being processed and reports the
+// highest offset that can be committed without skipping a message that is still in flight.
+type OffsetTracker struct {
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionOffsets
+	changed    chan struct{}
+}
+
+func NewOffsetTracker() *OffsetTracker {
+	return &OffsetTracker{
+		partitions: make(map[partitionKey]*partitionOffsets),
+		changed:    make(chan struct{}),
+	}
+}
+
+// Track registers a message that was read from Kafka and is about to be processed.
+// Messages must be tracked in the order they were read from their partition.
+func (t *OffsetTracker) Track(tp ckafka.TopicPartition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := keyOf(tp)
+	p, ok := t.partitions[key]
+	if !ok {
+		p = &partitionOffsets{done: make(map[ckafka.Offset]bool)}
+		t.partitions[key] = p
+	}
+	p.pending = append(p.pending, tp.Offset)
+}
+
+// Untrack removes the last message tracked for its partition, for a message that was read
+// but never handed out for processing.
+func (t *OffsetTracker) Untrack(tp ckafka.TopicPartition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[keyOf(tp)]
+	if !ok || len(p.pending) == 0 || p.pending[len(p.pending)-1] != tp.Offset {
+		return
+	}
+	p.pending = p.pending[:len(p.pending)-1]
+	t.notify()
+}
+
+// Done marks a message as processed. When it completes a contiguous run from the start of the
+// partition's pending offsets, the returned TopicPartition holds the offset to commit (the
+// next offset to read) and ok is true. Messages that are not tracked, for example because
+// their partition was revoked in the meantime, are ignored.
+func (t *OffsetTracker) Done(tp ckafka.TopicPartition) (commit ckafka.TopicPartition, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, found := t.partitions[keyOf(tp)]
+	if !found || !p.isPending(tp.Offset) {
+		return ckafka.TopicPartition{}, false
+	}
+	p.done[tp.Offset] = true
+
+	last := ckafka.Offset(-1)
+	for len(p.pending) > 0 && p.done[p.pending[0]] {
+		last = p.pending[0]
+		delete(p.done, last)
+		p.pending = p.pending[1:]
+	}
+	if last < 0 {
+		return ckafka.TopicPartition{}, false
+	}
+
+	t.notify()
+	return ckafka.TopicPartition{
+		Topic:     tp.Topic,
+		Partition: tp.Partition,
+		Offset:    last + 1,
+	}, true
+}
+
+// Rewind drops a message that is still in flight and every message of its partition tracked
+// after it, for a partition that is about to be read again from that message. It reports
+// whether the message was in flight: the ones read again are tracked anew, and the dropped
+// ones that finish in the meantime are ignored by Done.
+func (t *OffsetTracker) Rewind(tp ckafka.TopicPartition) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, found := t.partitions[keyOf(tp)]
+	if !found || !p.isPending(tp.Offset) {
+		return false
+	}
+	kept := p.pending[:0]
+	for _, offset := range p.pending {
+		if offset < tp.Offset {
+			kept = append(kept, offset)
+		} else {
+			delete(p.done, offset)
+		}
+	}
+	p.pending = kept
+	t.notify()
+	return true
+}
+
+// InFlight returns the number of messages of the given partitions that were tracked but are
+// not committable yet.
+func (t *OffsetTracker) InFlight(partitions []ckafka.TopicPartition) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for _, tp := range partitions {
+		if p, ok := t.partitions[keyOf(tp)]; ok {
+			count += len(p.pending)
+		}
+	}
+	return count
+}
+
+// Drain waits until every tracked message of the given partitions has been processed, or
+// until ctx is done. It returns the number of messages that were still in flight.
+func (t *OffsetTracker) Drain(ctx context.Context, partitions []ckafka.TopicPartition) int {
+	for {
+		t.mu.Lock()
+		changed := t.changed
+		t.mu.Unlock()
+
+		inFlight := t.InFlight(partitions)
+		if inFlight == 0 {
+			return 0
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return inFlight
+		}
+	}
+}
+
+// Forget drops the state of the given partitions. Messages of those partitions that finish
+// afterwards are ignored by Done.
+func (t *OffsetTracker) Forget(partitions []ckafka.TopicPartition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tp := range partitions {
+		delete(t.partitions, keyOf(tp))
+	}
+	t.notify()
+}
+
+// notify wakes up every Drain call. It must be called with the lock held.
+func (t *OffsetTracker) notify() {
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+func (p *partitionOffsets) isPending(offset ckafka.Offset) bool {
+	if p.done[offset] {
+		return false
+	}
+	for _, pending := range p.pending {
+		if pending == offset {
+			return true
+		}
+	}
+	return false
+}
+
+func keyOf(tp ckafka.TopicPartition) partitionKey {
+	key := partitionKey{partition: tp.Partition}
+	if tp.Topic != nil {
+		key.topic = *tp.Topic
+	}
+	return key
+}
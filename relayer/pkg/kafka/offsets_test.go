@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func topicPartition(topic string, partition int32, offset ckafka.Offset) ckafka.TopicPartition {
+	return ckafka.TopicPartition{Topic: &topic, Partition: partition, Offset: offset}
+}
+
+func TestOffsetTracker_CommitsInOrder(t *testing.T) {
+	tracker := NewOffsetTracker()
+	for offset := ckafka.Offset(10); offset < 13; offset++ {
+		tracker.Track(topicPartition("rewards", 0, offset))
+	}
+
+	_, ok := tracker.Done(topicPartition("rewards", 0, 12))
+	assert.False(t, ok)
+	_, ok = tracker.Done(topicPartition("rewards", 0, 11))
+	assert.False(t, ok)
+
+	commit, ok := tracker.Done(topicPartition("rewards", 0, 10))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(13), commit.Offset)
+	assert.Equal(t, "rewards", *commit.Topic)
+	assert.Equal(t, int32(0), commit.Partition)
+}
+
+func TestOffsetTracker_CommitsContiguousPrefix(t *testing.T) {
+	tracker := NewOffsetTracker()
+	for _, offset := range []ckafka.Offset{3, 4, 7, 8} {
+		tracker.Track(topicPartition("rewards", 0, offset))
+	}
+
+	commit, ok := tracker.Done(topicPartition("rewards", 0, 3))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(4), commit.Offset)
+
+	_, ok = tracker.Done(topicPartition("rewards", 0, 8))
+	assert.False(t, ok)
+
+	// Gaps between tracked offsets, such as compacted records, do not block the commit.
+	commit, ok = tracker.Done(topicPartition("rewards", 0, 4))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(5), commit.Offset)
+
+	commit, ok = tracker.Done(topicPartition("rewards", 0, 7))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(9), commit.Offset)
+}
+
+func TestOffsetTracker_PartitionsAreIndependent(t *testing.T) {
+	tracker := NewOffsetTracker()
+	tracker.Track(topicPartition("rewards", 0, 1))
+	tracker.Track(topicPartition("rewards", 1, 1))
+	tracker.Track(topicPartition("other", 0, 1))
+
+	commit, ok := tracker.Done(topicPartition("rewards", 1, 1))
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), commit.Partition)
+
+	assert.Equal(t, 2, tracker.InFlight([]ckafka.TopicPartition{
+		topicPartition("rewards", 0, 0),
+		topicPartition("rewards", 1, 0),
+		topicPartition("other", 0, 0),
+	}))
+}
+
+func TestOffsetTracker_IgnoresUntrackedAndRepeated(t *testing.T) {
+	tracker := NewOffsetTracker()
+	tracker.Track(topicPartition("rewards", 0, 1))
+	tracker.Track(topicPartition("rewards", 0, 2))
+
+	_, ok := tracker.Done(topicPartition("rewards", 0, 5))
+	assert.False(t, ok)
+	_, ok = tracker.Done(topicPartition("rewards", 3, 1))
+	assert.False(t, ok)
+
+	_, ok = tracker.Done(topicPartition("rewards", 0, 2))
+	assert.False(t, ok)
+	_, ok = tracker.Done(topicPartition("rewards", 0, 2))
+	assert.False(t, ok)
+
+	commit, ok := tracker.Done(topicPartition("rewards", 0, 1))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(3), commit.Offset)
+}
+
+func TestOffsetTracker_Drain(t *testing.T) {
+	tracker := NewOffsetTracker()
+	tracker.Track(topicPartition("rewards", 0, 1))
+	tracker.Track(topicPartition("rewards", 1, 1))
+	revoked := []ckafka.TopicPartition{topicPartition("rewards", 0, ckafka.OffsetInvalid)}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		tracker.Done(topicPartition("rewards", 0, 1))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, 0, tracker.Drain(ctx, revoked))
+}
+
+func TestOffsetTracker_DrainTimeout(t *testing.T) {
+	tracker := NewOffsetTracker()
+	tracker.Track(topicPartition("rewards", 0, 1))
+	revoked := []ckafka.TopicPartition{topicPartition("rewards", 0, ckafka.OffsetInvalid)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 1, tracker.Drain(ctx, revoked))
+
+	tracker.Forget(revoked)
+	assert.Equal(t, 0, tracker.InFlight(revoked))
+	_, ok := tracker.Done(topicPartition("rewards", 0, 1))
+	assert.False(t, ok)
+}
+
+func TestOffsetTracker_Untrack(t *testing.T) {
+	tracker := NewOffsetTracker()
+	tracker.Track(topicPartition("rewards", 0, 1))
+	tracker.Track(topicPartition("rewards", 0, 2))
+
+	// Only the last tracked offset of a partition can be untracked.
+	tracker.Untrack(topicPartition("rewards", 0, 1))
+	tracker.Untrack(topicPartition("rewards", 0, 2))
+
+	commit, ok := tracker.Done(topicPartition("rewards", 0, 1))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(2), commit.Offset)
+	assert.Equal(t, 0, tracker.InFlight([]ckafka.TopicPartition{topicPartition("rewards", 0, 0)}))
+}
+
+func TestOffsetTracker_Rewind(t *testing.T) {
+	tracker := NewOffsetTracker()
+	for offset := ckafka.Offset(10); offset < 14; offset++ {
+		tracker.Track(topicPartition("rewards", 0, offset))
+	}
+	_, ok := tracker.Done(topicPartition("rewards", 0, 12))
+	assert.False(t, ok)
+
+	// 11 is nacked: it and everything read after it is read again.
+	assert.True(t, tracker.Rewind(topicPartition("rewards", 0, 11)))
+	assert.Equal(t, 1, tracker.InFlight([]ckafka.TopicPartition{topicPartition("rewards", 0, 0)}))
+
+	// The dropped messages that finish before being read again are ignored.
+	_, ok = tracker.Done(topicPartition("rewards", 0, 13))
+	assert.False(t, ok)
+
+	commit, ok := tracker.Done(topicPartition("rewards", 0, 10))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(11), commit.Offset)
+
+	// The commits stay below 11 until it is processed again.
+	for offset := ckafka.Offset(11); offset < 14; offset++ {
+		tracker.Track(topicPartition("rewards", 0, offset))
+	}
+	_, ok = tracker.Done(topicPartition("rewards", 0, 12))
+	assert.False(t, ok)
+	commit, ok = tracker.Done(topicPartition("rewards", 0, 11))
+	assert.True(t, ok)
+	assert.Equal(t, ckafka.Offset(13), commit.Offset)
+
+	// Messages already processed, or of partitions not tracked, are not rewound.
+	assert.False(t, tracker.Rewind(topicPartition("rewards", 0, 11)))
+	assert.False(t, tracker.Rewind(topicPartition("rewards", 1, 11)))
+}
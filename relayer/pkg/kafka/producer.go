@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+type KafkaProducer struct {
+	ConfigMap *ckafka.ConfigMap
+	producer  *ckafka.Producer
+}
+
+func NewKafkaProducer(configMap *ckafka.ConfigMap) (*KafkaProducer, error) {
+	producer, err := ckafka.NewProducer(configMap)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kafka producer: %w", err)
+	}
+	return &KafkaProducer{
+		ConfigMap: configMap,
+		producer:  producer,
+	}, nil
+}
+
+// Publish sends a message and waits for the broker to acknowledge it.
+func (p *KafkaProducer) Publish(ctx context.Context, topic string, key, value []byte, headers []ckafka.Header) error {
+	deliveryChan := make(chan ckafka.Event, 1)
+	err := p.producer.Produce(&ckafka.Message{
+		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: ckafka.PartitionAny},
+		Key:            key,
+		Value:          value,
+		Headers:        headers,
+	}, deliveryChan)
+	if err != nil {
+		return fmt.Errorf("error producing message to %s: %w", topic, err)
+	}
+
+	select {
+	case e := <-deliveryChan:
+		msg, ok := e.(*ckafka.Message)
+		if !ok {
+			return fmt.Errorf("unexpected delivery event: %v", e)
+		}
+		if msg.TopicPartition.Error != nil {
+			return fmt.Errorf("error delivering message to %s: %w", topic, msg.TopicPartition.Error)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes outstanding messages, waiting at most timeoutMs, and closes the producer.
+func (p *KafkaProducer) Close(timeoutMs int) int {
+	remaining := p.producer.Flush(timeoutMs)
+	p.producer.Close()
+	return remaining
+}
@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// StartPosition is where a consumer starts reading a partition the first time it is assigned,
+// instead of the offset committed by its group. Either Offset or Timestamp is set.
+type StartPosition struct {
+	Offset    ckafka.Offset
+	Timestamp time.Time
+}
+
+// ParseStartPosition parses "earliest", "latest", "offset:<offset>" or
+// "timestamp:<RFC 3339 time>".
+func ParseStartPosition(s string) (*StartPosition, error) {
+	kind, value, _ := strings.Cut(strings.TrimSpace(s), ":")
+	switch strings.ToLower(kind) {
+	case "earliest":
+		return &StartPosition{Offset: ckafka.OffsetBeginning}, nil
+	case "latest":
+		return &StartPosition{Offset: ckafka.OffsetEnd}, nil
+	case "offset":
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid start offset '%s'", value)
+		}
+		return &StartPosition{Offset: ckafka.Offset(offset)}, nil
+	case "timestamp":
+		timestamp, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start timestamp '%s': %w", value, err)
+		}
+		return &StartPosition{Timestamp: timestamp}, nil
+	default:
+		return nil, fmt.Errorf("invalid start position '%s'", s)
+	}
+}
+
+func (p *StartPosition) String() string {
+	switch {
+	case !p.Timestamp.IsZero():
+		return "timestamp:" + p.Timestamp.Format(time.RFC3339)
+	case p.Offset == ckafka.OffsetBeginning:
+		return "earliest"
+	case p.Offset == ckafka.OffsetEnd:
+		return "latest"
+	default:
+		return fmt.Sprintf("offset:%d", p.Offset)
+	}
+}
+
+// offsets returns the partitions with the offset to start from. Partitions with no message
+// after the timestamp start at their end.
+func (p *StartPosition) offsets(consumer *ckafka.Consumer, partitions []ckafka.TopicPartition, timeout time.Duration) ([]ckafka.TopicPartition, error) {
+	result := make([]ckafka.TopicPartition, len(partitions))
+	for i, tp := range partitions {
+		result[i] = ckafka.TopicPartition{Topic: tp.Topic, Partition: tp.Partition, Offset: p.Offset}
+		if !p.Timestamp.IsZero() {
+			result[i].Offset = ckafka.Offset(p.Timestamp.UnixMilli())
+		}
+	}
+	if p.Timestamp.IsZero() {
+		return result, nil
+	}
+
+	result, err := consumer.OffsetsForTimes(result, int(timeout.Milliseconds()))
+	if err != nil {
+		return nil, fmt.Errorf("error getting offsets for %s: %w", p, err)
+	}
+	for i := range result {
+		if result[i].Error != nil {
+			return nil, fmt.Errorf("error getting offset for %s on %s: %w", p, result[i], result[i].Error)
+		}
+		if result[i].Offset < 0 {
+			result[i].Offset = ckafka.OffsetEnd
+		}
+	}
+	return result, nil
+}
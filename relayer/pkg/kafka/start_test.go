@@ -0,0 +1,38 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStartPosition(t *testing.T) {
+	position, err := ParseStartPosition("earliest")
+	assert.NoError(t, err)
+	assert.Equal(t, ckafka.OffsetBeginning, position.Offset)
+	assert.Equal(t, "earliest", position.String())
+
+	position, err = ParseStartPosition("LATEST")
+	assert.NoError(t, err)
+	assert.Equal(t, ckafka.OffsetEnd, position.Offset)
+
+	position, err = ParseStartPosition("offset:1200")
+	assert.NoError(t, err)
+	assert.Equal(t, ckafka.Offset(1200), position.Offset)
+	assert.True(t, position.Timestamp.IsZero())
+	assert.Equal(t, "offset:1200", position.String())
+
+	position, err = ParseStartPosition("timestamp:2024-05-01T12:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), position.Timestamp)
+	assert.Equal(t, "timestamp:2024-05-01T12:00:00Z", position.String())
+}
+
+func TestParseStartPosition_Invalid(t *testing.T) {
+	for _, s := range []string{"", "beginning", "offset:", "offset:-5", "offset:abc", "timestamp:yesterday"} {
+		_, err := ParseStartPosition(s)
+		assert.Error(t, err, s)
+	}
+}
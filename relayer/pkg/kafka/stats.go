@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Stats is the part of the librdkafka statistics the relayer reports, with the throughput
+// computed against the previous report.
+type Stats struct {
+	Time              time.Time        `json:"time"`
+	ReceivedMessages  int64            `json:"received_messages"`
+	ReceivedBytes     int64            `json:"received_bytes"`
+	MessagesPerSecond float64          `json:"messages_per_second"`
+	BytesPerSecond    float64          `json:"bytes_per_second"`
+	Lag               int64            `json:"lag"`
+	Partitions        []PartitionStats `json:"partitions"`
+	uptime            int64
+}
+
+type PartitionStats struct {
+	Topic            string `json:"topic"`
+	Partition        int32  `json:"partition"`
+	Lag              int64  `json:"lag"`
+	HighWatermark    int64  `json:"high_watermark"`
+	CommittedOffset  int64  `json:"committed_offset"`
+	ReceivedMessages int64  `json:"received_messages"`
+	QueuedMessages   int64  `json:"queued_messages"`
+}
+
+type rawStats struct {
+	Time       int64 `json:"time"`
+	Uptime     int64 `json:"ts"`
+	RxMsgs     int64 `json:"rxmsgs"`
+	RxMsgBytes int64 `json:"rxmsg_bytes"`
+	Topics     map[string]struct {
+		Partitions map[string]struct {
+			Partition       int32 `json:"partition"`
+			ConsumerLag     int64 `json:"consumer_lag"`
+			HiOffset        int64 `json:"hi_offset"`
+			CommittedOffset int64 `json:"committed_offset"`
+			RxMsgs          int64 `json:"rxmsgs"`
+			FetchqCnt       int64 `json:"fetchq_cnt"`
+		} `json:"partitions"`
+	} `json:"topics"`
+}
+
+// ParseStats decodes a statistics report of the client. The throughput is computed against
+// previous, which may be nil. The lag of a partition is -1 while it is unknown, such as
+// before the first fetch, and the total lag only sums the known ones.
+func ParseStats(data string, previous *Stats) (*Stats, error) {
+	var raw rawStats
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode kafka statistics: %w", err)
+	}
+
+	stats := &Stats{
+		Time:             time.Unix(raw.Time, 0),
+		ReceivedMessages: raw.RxMsgs,
+		ReceivedBytes:    raw.RxMsgBytes,
+		uptime:           raw.Uptime,
+	}
+
+	for topic, topicStats := range raw.Topics {
+		for _, p := range topicStats.Partitions {
+			// librdkafka reports unassigned messages under the internal partition -1.
+			if p.Partition < 0 {
+				continue
+			}
+			stats.Partitions = append(stats.Partitions, PartitionStats{
+				Topic:            topic,
+				Partition:        p.Partition,
+				Lag:              p.ConsumerLag,
+				HighWatermark:    p.HiOffset,
+				CommittedOffset:  p.CommittedOffset,
+				ReceivedMessages: p.RxMsgs,
+				QueuedMessages:   p.FetchqCnt,
+			})
+			if p.ConsumerLag > 0 {
+				stats.Lag += p.ConsumerLag
+			}
+		}
+	}
+	sort.Slice(stats.Partitions, func(i, j int) bool {
+		if stats.Partitions[i].Topic != stats.Partitions[j].Topic {
+			return stats.Partitions[i].Topic < stats.Partitions[j].Topic
+		}
+		return stats.Partitions[i].Partition < stats.Partitions[j].Partition
+	})
+
+	// The uptime is in microseconds and, unlike the wall clock time, never goes backwards.
+	if previous != nil && stats.uptime > previous.uptime {
+		elapsed := float64(stats.uptime-previous.uptime) / float64(time.Second/time.Microsecond)
+		stats.MessagesPerSecond = float64(stats.ReceivedMessages-previous.ReceivedMessages) / elapsed
+		stats.BytesPerSecond = float64(stats.ReceivedBytes-previous.ReceivedBytes) / elapsed
+	}
+
+	return stats, nil
+}
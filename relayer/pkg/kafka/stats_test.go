@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const statsReport = `{
+	"name": "rdkafka#consumer-1",
+	"ts": 12000000,
+	"time": 1700000010,
+	"rxmsgs": 150,
+	"rxmsg_bytes": 30000,
+	"topics": {
+		"rewards": {
+			"topic": "rewards",
+			"partitions": {
+				"1": {"partition": 1, "consumer_lag": 7, "hi_offset": 40, "committed_offset": 33, "rxmsgs": 50, "fetchq_cnt": 2},
+				"0": {"partition": 0, "consumer_lag": 3, "hi_offset": 90, "committed_offset": 87, "rxmsgs": 100, "fetchq_cnt": 0},
+				"2": {"partition": 2, "consumer_lag": -1, "hi_offset": -1, "committed_offset": -1001, "rxmsgs": 0, "fetchq_cnt": 0},
+				"-1": {"partition": -1, "consumer_lag": -1, "hi_offset": -1, "committed_offset": -1001, "rxmsgs": 0, "fetchq_cnt": 0}
+			}
+		}
+	}
+}`
+
+func TestParseStats(t *testing.T) {
+	stats, err := ParseStats(statsReport, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(1700000010), stats.Time.Unix())
+	assert.Equal(t, int64(150), stats.ReceivedMessages)
+	assert.Equal(t, int64(30000), stats.ReceivedBytes)
+	assert.Equal(t, int64(10), stats.Lag)
+	assert.Zero(t, stats.MessagesPerSecond)
+
+	assert.Len(t, stats.Partitions, 3)
+	assert.Equal(t, int32(0), stats.Partitions[0].Partition)
+	assert.Equal(t, "rewards", stats.Partitions[0].Topic)
+	assert.Equal(t, int64(3), stats.Partitions[0].Lag)
+	assert.Equal(t, int64(87), stats.Partitions[0].CommittedOffset)
+	assert.Equal(t, int64(2), stats.Partitions[1].QueuedMessages)
+	assert.Equal(t, int64(-1), stats.Partitions[2].Lag)
+}
+
+func TestParseStats_Throughput(t *testing.T) {
+	previous := &Stats{ReceivedMessages: 100, ReceivedBytes: 20000, uptime: 7000000}
+
+	stats, err := ParseStats(statsReport, previous)
+	assert.NoError(t, err)
+
+	assert.InDelta(t, 10.0, stats.MessagesPerSecond, 0.001)
+	assert.InDelta(t, 2000.0, stats.BytesPerSecond, 0.001)
+}
+
+func TestParseStats_Invalid(t *testing.T) {
+	_, err := ParseStats("not json", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,265 @@
+// Package quality checks sensor readings for implausible values and keeps a reputation score
+// per sensor.
+package quality
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Reason string
+
+const (
+	// Readings with these findings are rejected.
+	ReasonMalformed   Reason = "malformed"
+	ReasonOutOfRange  Reason = "out_of_range"
+	ReasonTooFrequent Reason = "too_frequent"
+
+	// Readings with these findings are only flagged, and lower the score of the sensor.
+	ReasonStuck     Reason = "stuck"
+	ReasonSpike     Reason = "spike"
+	ReasonNeighbors Reason = "disagrees_with_neighbors"
+)
+
+const (
+	// scoreWeight is the weight of a reading in the score, an exponential moving average of
+	// 1 for clean readings, 0.5 for flagged ones and 0 for rejected ones.
+	scoreWeight = 0.1
+	// statsWeight is the weight of a value in the moving mean and variance of a parameter,
+	// once it has statsWarmup values. Before that, every value weighs the same.
+	statsWeight = 0.1
+	statsWarmup = 10
+	// minNeighbors is how many nearby sensors must measure a parameter to cross-check it.
+	minNeighbors = 2
+	earthRadius  = 6371000.0 // meters
+)
+
+// Range is the span of plausible values of a parameter.
+type Range struct {
+	Min float64
+	Max float64
+}
+
+// DefaultRanges are the physically possible values of the parameters the sensors are known
+// to measure, in the units they report.
+var DefaultRanges = map[string]Range{
+	"co2":         {0, 40000},
+	"co":          {0, 1000},
+	"no2":         {0, 10000},
+	"mp10":        {0, 2000},
+	"pm10":        {0, 2000},
+	"mp25":        {0, 1000},
+	"pm25":        {0, 1000},
+	"rad":         {0, 100000},
+	"temperature": {-60, 70},
+	"humidity":    {0, 100},
+}
+
+// ParseRanges parses comma separated ranges written as parameter:min:max, such as
+// co2:0:5000, over the default ones.
+func ParseRanges(s string) (map[string]Range, error) {
+	ranges := make(map[string]Range, len(DefaultRanges))
+	for parameter, r := range DefaultRanges {
+		ranges[parameter] = r
+	}
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		parts := strings.Split(field, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid range %q, expected parameter:min:max", field)
+		}
+		low, errLow := strconv.ParseFloat(parts[1], 64)
+		high, errHigh := strconv.ParseFloat(parts[2], 64)
+		if errLow != nil || errHigh != nil || low > high {
+			return nil, fmt.Errorf("invalid range of %q", parts[0])
+		}
+		ranges[parts[0]] = Range{Min: low, Max: high}
+	}
+	return ranges, nil
+}
+
+type Config struct {
+	Ranges            map[string]Range // parameters without a range are not checked
+	MinInterval       time.Duration    // shortest time between two readings of a sensor
+	StuckReadings     int              // identical readings in a row that make a sensor stuck
+	SpikeFactor       float64          // standard deviations from the moving mean that make a spike
+	NeighborTolerance float64          // ratio to the median of the nearby sensors that is tolerated
+	Withhold          float64          // score under which rewards are withheld
+}
+
+// ParameterStats is the moving mean and variance of a parameter.
+type ParameterStats struct {
+	Mean     float64 `bson:"mean" json:"mean"`
+	Variance float64 `bson:"variance" json:"variance"`
+	Count    int64   `bson:"count" json:"count"`
+}
+
+// State is what the checks remember of a sensor. The zero State is the one of a sensor
+// never seen before.
+type State struct {
+	Score      float64                   `bson:"score" json:"score"`
+	Readings   int64                     `bson:"readings" json:"readings"`
+	LastAt     time.Time                 `bson:"last_at" json:"last_at"`
+	LastValues map[string]float64        `bson:"last_values,omitempty" json:"last_values,omitempty"`
+	Repeats    int                       `bson:"repeats" json:"repeats"` // readings identical to the previous one
+	Stats      map[string]ParameterStats `bson:"stats,omitempty" json:"stats,omitempty"`
+}
+
+type Finding struct {
+	Reason    Reason `bson:"reason" json:"reason"`
+	Parameter string `bson:"parameter,omitempty" json:"parameter,omitempty"`
+	Detail    string `bson:"detail" json:"detail"`
+}
+
+type Verdict struct {
+	Findings []Finding
+	Rejected bool    // the reading itself is invalid
+	Withheld bool    // the reading must not be rewarded: it is rejected, or the score is too low
+	Score    float64 // score of the sensor after the reading, the share of the reward it gets
+}
+
+// Check runs the checks on the values of a reading of a sensor taken at at, and updates the
+// state of the sensor. A reading without values is malformed. neighbors are the latest
+// values of the nearby sensors.
+func (c Config) Check(state *State, values map[string]float64, at time.Time, neighbors []map[string]float64) Verdict {
+	if state.Readings == 0 {
+		state.Score = 1
+	}
+	state.Readings++
+
+	var verdict Verdict
+	add := func(reason Reason, parameter, format string, args ...any) {
+		verdict.Findings = append(verdict.Findings, Finding{reason, parameter, fmt.Sprintf(format, args...)})
+	}
+
+	parameters := make([]string, 0, len(values))
+	for parameter := range values {
+		parameters = append(parameters, parameter)
+	}
+	slices.Sort(parameters)
+
+	if len(values) == 0 {
+		add(ReasonMalformed, "", "no numeric values")
+	}
+	if !state.LastAt.IsZero() && at.Sub(state.LastAt) < c.MinInterval {
+		add(ReasonTooFrequent, "", "%s after the previous reading", at.Sub(state.LastAt))
+	}
+	for _, parameter := range parameters {
+		value := values[parameter]
+		r, ok := c.Ranges[parameter]
+		if math.IsNaN(value) || math.IsInf(value, 0) || ok && (value < r.Min || value > r.Max) {
+			add(ReasonOutOfRange, parameter, "%g outside [%g, %g]", value, r.Min, r.Max)
+		}
+	}
+
+	if len(verdict.Findings) > 0 {
+		// Rejected values are kept out of the state, so that they do not skew the checks of
+		// the next readings.
+		verdict.Rejected = true
+		state.Score += scoreWeight * (0 - state.Score)
+		verdict.Score = state.Score
+		verdict.Withheld = true
+		return verdict
+	}
+
+	if sameValues(values, state.LastValues) {
+		state.Repeats++
+	} else {
+		state.Repeats = 0
+	}
+	if c.StuckReadings > 1 && state.Repeats+1 >= c.StuckReadings {
+		add(ReasonStuck, "", "%d identical readings in a row", state.Repeats+1)
+	}
+
+	if state.Stats == nil {
+		state.Stats = make(map[string]ParameterStats)
+	}
+	for _, parameter := range parameters {
+		value := values[parameter]
+		stats := state.Stats[parameter]
+		if stats.Count >= statsWarmup && stats.Variance > 0 && c.SpikeFactor > 0 {
+			deviation := math.Abs(value-stats.Mean) / math.Sqrt(stats.Variance)
+			if deviation > c.SpikeFactor {
+				add(ReasonSpike, parameter, "%g is %.1f standard deviations from the mean %g", value, deviation, stats.Mean)
+			}
+		}
+		state.Stats[parameter] = stats.add(value)
+
+		if median, n := neighborMedian(neighbors, parameter); n >= minNeighbors && median > 0 && c.NeighborTolerance > 1 {
+			if value > median*c.NeighborTolerance || value < median/c.NeighborTolerance {
+				add(ReasonNeighbors, parameter, "%g against a median of %g over %d nearby sensors", value, median, n)
+			}
+		}
+	}
+
+	state.LastAt = at
+	state.LastValues = values
+
+	quality := 1.0
+	if len(verdict.Findings) > 0 {
+		quality = 0.5
+	}
+	state.Score += scoreWeight * (quality - state.Score)
+	verdict.Score = state.Score
+	verdict.Withheld = state.Score < c.Withhold
+	return verdict
+}
+
+func (s ParameterStats) add(value float64) ParameterStats {
+	weight := statsWeight
+	if s.Count < statsWarmup {
+		weight = 1 / float64(s.Count+1)
+	}
+	delta := value - s.Mean
+	s.Mean += weight * delta
+	s.Variance = (1 - weight) * (s.Variance + weight*delta*delta)
+	s.Count++
+	return s
+}
+
+func sameValues(a, b map[string]float64) bool {
+	if len(a) != len(b) || len(a) == 0 {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+func neighborMedian(neighbors []map[string]float64, parameter string) (float64, int) {
+	var values []float64
+	for _, neighbor := range neighbors {
+		if value, ok := neighbor[parameter]; ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return 0, 0
+	}
+	slices.Sort(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2, len(values)
+	}
+	return values[middle], len(values)
+}
+
+// Distance returns the distance in meters between two locations.
+func Distance(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLatitude := toRadians(latitude2 - latitude1)
+	dLongitude := toRadians(longitude2 - longitude1)
+	a := math.Sin(dLatitude/2)*math.Sin(dLatitude/2) +
+		math.Cos(toRadians(latitude1))*math.Cos(toRadians(latitude2))*math.Sin(dLongitude/2)*math.Sin(dLongitude/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
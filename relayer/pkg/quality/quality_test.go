@@ -0,0 +1,136 @@
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var config = Config{
+	Ranges:            DefaultRanges,
+	MinInterval:       5 * time.Second,
+	StuckReadings:     3,
+	SpikeFactor:       6,
+	NeighborTolerance: 4,
+	Withhold:          0.5,
+}
+
+var start = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func reasons(verdict Verdict) []Reason {
+	var reasons []Reason
+	for _, finding := range verdict.Findings {
+		reasons = append(reasons, finding.Reason)
+	}
+	return reasons
+}
+
+func TestParseRanges(t *testing.T) {
+	ranges, err := ParseRanges("co2:0:5000, ozone:0:600")
+	require.NoError(t, err)
+	assert.Equal(t, Range{0, 5000}, ranges["co2"])
+	assert.Equal(t, Range{0, 600}, ranges["ozone"])
+	assert.Equal(t, DefaultRanges["mp25"], ranges["mp25"])
+	assert.Equal(t, Range{0, 40000}, DefaultRanges["co2"], "defaults are not modified")
+
+	for _, invalid := range []string{"co2:0", "co2:high:10", "co2:10:0", ":0:1"} {
+		_, err := ParseRanges(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCheck_CleanReadingsKeepFullScore(t *testing.T) {
+	var state State
+	for i := 0; i < 20; i++ {
+		verdict := config.Check(&state, map[string]float64{"co2": float64(480 + i%7), "mp25": float64(60 + i%3)}, start.Add(time.Duration(i)*10*time.Second), nil)
+		assert.Empty(t, verdict.Findings)
+		assert.False(t, verdict.Withheld)
+		assert.Equal(t, 1.0, verdict.Score)
+	}
+	assert.Equal(t, int64(20), state.Readings)
+}
+
+func TestCheck_RejectsOutOfRangeAndTooFrequent(t *testing.T) {
+	var state State
+	verdict := config.Check(&state, map[string]float64{"co2": -5, "humidity": 140, "unknown": 1e9}, start, nil)
+	assert.True(t, verdict.Rejected)
+	assert.True(t, verdict.Withheld)
+	assert.Equal(t, []Reason{ReasonOutOfRange, ReasonOutOfRange}, reasons(verdict))
+	assert.InDelta(t, 0.9, verdict.Score, 1e-9)
+	assert.True(t, state.LastAt.IsZero(), "rejected readings are not remembered")
+
+	verdict = config.Check(&state, nil, start, nil)
+	assert.True(t, verdict.Rejected)
+	assert.Equal(t, []Reason{ReasonMalformed}, reasons(verdict))
+
+	config.Check(&state, map[string]float64{"co2": 500}, start, nil)
+	verdict = config.Check(&state, map[string]float64{"co2": 510}, start.Add(time.Second), nil)
+	assert.True(t, verdict.Rejected)
+	assert.Equal(t, []Reason{ReasonTooFrequent}, reasons(verdict))
+}
+
+func TestCheck_Stuck(t *testing.T) {
+	var state State
+	values := map[string]float64{"co2": 500, "mp25": 60}
+	assert.Empty(t, config.Check(&state, values, start, nil).Findings)
+	assert.Empty(t, config.Check(&state, values, start.Add(10*time.Second), nil).Findings)
+
+	verdict := config.Check(&state, values, start.Add(20*time.Second), nil)
+	assert.Equal(t, []Reason{ReasonStuck}, reasons(verdict))
+	assert.False(t, verdict.Rejected)
+	assert.InDelta(t, 0.95, verdict.Score, 1e-9)
+
+	verdict = config.Check(&state, map[string]float64{"co2": 501, "mp25": 60}, start.Add(30*time.Second), nil)
+	assert.Empty(t, verdict.Findings)
+}
+
+func TestCheck_Spike(t *testing.T) {
+	var state State
+	for i := 0; i < 20; i++ {
+		config.Check(&state, map[string]float64{"co2": float64(490 + i%20)}, start.Add(time.Duration(i)*10*time.Second), nil)
+	}
+	verdict := config.Check(&state, map[string]float64{"co2": 5000}, start.Add(time.Hour), nil)
+	assert.Equal(t, []Reason{ReasonSpike}, reasons(verdict))
+	assert.Equal(t, "co2", verdict.Findings[0].Parameter)
+}
+
+func TestCheck_Neighbors(t *testing.T) {
+	neighbors := []map[string]float64{{"co2": 480, "mp25": 50}, {"co2": 520}, {"co2": 500}}
+
+	var state State
+	verdict := config.Check(&state, map[string]float64{"co2": 3000, "mp25": 500}, start, neighbors)
+	// mp25 is measured by a single neighbor, not enough to cross-check it.
+	assert.Equal(t, []Reason{ReasonNeighbors}, reasons(verdict))
+	assert.Equal(t, "co2", verdict.Findings[0].Parameter)
+
+	verdict = config.Check(&state, map[string]float64{"co2": 1000, "mp25": 500}, start.Add(10*time.Second), neighbors)
+	assert.Empty(t, verdict.Findings)
+}
+
+func TestCheck_WithholdsBelowThreshold(t *testing.T) {
+	var state State
+	var verdict Verdict
+	for i := 0; i < 10; i++ {
+		verdict = config.Check(&state, map[string]float64{"co2": -1}, start.Add(time.Duration(i)*10*time.Second), nil)
+	}
+	require.Less(t, state.Score, config.Withhold)
+
+	// A clean reading is still withheld until the score recovers.
+	verdict = config.Check(&state, map[string]float64{"co2": 500}, start.Add(time.Hour), nil)
+	assert.Empty(t, verdict.Findings)
+	assert.True(t, verdict.Withheld)
+	assert.False(t, verdict.Rejected)
+
+	for i := 1; verdict.Withheld; i++ {
+		verdict = config.Check(&state, map[string]float64{"co2": float64(500 + i)}, start.Add(time.Hour+time.Duration(i)*10*time.Second), nil)
+	}
+	assert.GreaterOrEqual(t, verdict.Score, config.Withhold)
+}
+
+func TestDistance(t *testing.T) {
+	// About 167 m between two of the sample sensors.
+	assert.InDelta(t, 167, Distance(-34.5775, -58.4200, -34.5790, -58.4200), 1)
+	assert.Zero(t, Distance(-34.5775, -58.42, -34.5775, -58.42))
+}
@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Policy describes an exponential backoff: the first retry waits MinWait and every
+// following one waits twice as long, up to MaxWait.
+type Policy struct {
+	MaxAttempts int
+	MinWait     time.Duration
+	MaxWait     time.Duration
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 5,
+		MinWait:     time.Second,
+		MaxWait:     30 * time.Second,
+	}
+}
+
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Do returns it right away.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Backoff returns how long to wait after the given failed attempt, starting at 1.
+func (p Policy) Backoff(attempt int) time.Duration {
+	wait := p.MinWait
+	for i := 1; i < attempt && wait < p.MaxWait; i++ {
+		wait *= 2
+	}
+	if p.MaxWait > 0 && wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	return wait
+}
+
+// Do calls fn until it succeeds, returns a permanent error, the attempts run out or ctx
+// is done. It returns the number of attempts made and the last error.
+func (p Policy) Do(ctx context.Context, fn func(attempt int) error) (int, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(attempt)
+		if err == nil || IsPermanent(err) || attempt >= maxAttempts {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, errors.Join(err, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTest = errors.New("test error")
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{MinWait: time.Second, MaxWait: 5 * time.Second}
+
+	assert.Equal(t, time.Second, policy.Backoff(1))
+	assert.Equal(t, 2*time.Second, policy.Backoff(2))
+	assert.Equal(t, 4*time.Second, policy.Backoff(3))
+	assert.Equal(t, 5*time.Second, policy.Backoff(4))
+	assert.Equal(t, 5*time.Second, policy.Backoff(10))
+}
+
+func TestPolicy_Do_Success(t *testing.T) {
+	policy := Policy{MaxAttempts: 5, MinWait: time.Millisecond, MaxWait: time.Millisecond}
+
+	attempts, err := policy.Do(context.Background(), func(attempt int) error {
+		if attempt < 3 {
+			return errTest
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestPolicy_Do_Exhausted(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, MinWait: time.Millisecond, MaxWait: time.Millisecond}
+
+	attempts, err := policy.Do(context.Background(), func(attempt int) error {
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestPolicy_Do_Permanent(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, MinWait: time.Millisecond, MaxWait: time.Millisecond}
+
+	attempts, err := policy.Do(context.Background(), func(attempt int) error {
+		return Permanent(errTest)
+	})
+	assert.ErrorIs(t, err, errTest)
+	assert.True(t, IsPermanent(err))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestPolicy_Do_ContextCancellation(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, MinWait: time.Hour, MaxWait: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts, err := policy.Do(ctx, func(attempt int) error {
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CheckFunc probes a dependency and returns an error when it is not usable.
+type CheckFunc func(ctx context.Context) error
+
+// CheckStatus is the outcome of the last run of a health check.
+type CheckStatus struct {
+	Name        string     `json:"name"`
+	Healthy     bool       `json:"healthy"`
+	Latency     string     `json:"latency"`
+	CheckedAt   time.Time  `json:"checked_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+type healthCheck struct {
+	name   string
+	check  CheckFunc
+	status CheckStatus
+	ran    bool
+}
+
+// HealthRegistry runs the health checks of the dependencies of a service. Results are
+// cached for MaxAge, so that frequent probes do not overload the dependencies.
+type HealthRegistry struct {
+	// Timeout bounds each check.
+	Timeout time.Duration
+	// MaxAge is how long the results of a run are reused.
+	MaxAge time.Duration
+
+	mu      sync.Mutex
+	running sync.Mutex
+	checks  []*healthCheck
+	lastRun time.Time
+}
+
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		Timeout: 5 * time.Second,
+		MaxAge:  5 * time.Second,
+	}
+}
+
+// SetTimeout changes the time each check may take, from the next run on.
+func (r *HealthRegistry) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Timeout = timeout
+}
+
+// Register adds a check. Checks are reported in the order they were registered, and a
+// check registered again under the same name replaces the previous one.
+func (r *HealthRegistry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.checks {
+		if c.name == name {
+			c.check = check
+			return
+		}
+	}
+	r.checks = append(r.checks, &healthCheck{name: name, check: check})
+}
+
+// Run runs all checks concurrently, unless the last results are more recent than MaxAge,
+// and returns their status.
+func (r *HealthRegistry) Run(ctx context.Context) []CheckStatus {
+	r.running.Lock()
+	defer r.running.Unlock()
+
+	r.mu.Lock()
+	fresh := time.Since(r.lastRun) < r.MaxAge
+	checks := append([]*healthCheck(nil), r.checks...)
+	r.mu.Unlock()
+	if fresh {
+		return r.Statuses()
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c *healthCheck) {
+			defer wg.Done()
+			r.run(ctx, c)
+		}(c)
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	r.lastRun = time.Now()
+	r.mu.Unlock()
+	return r.Statuses()
+}
+
+// Statuses returns the results of the last run, without running the checks.
+func (r *HealthRegistry) Statuses() []CheckStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]CheckStatus, 0, len(r.checks))
+	for _, c := range r.checks {
+		status := c.status
+		status.Name = c.name
+		if !c.ran {
+			status.LastError = "not checked yet"
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Healthy runs the checks, if needed, and reports whether all of them passed.
+func (r *HealthRegistry) Healthy(ctx context.Context) bool {
+	for _, status := range r.Run(ctx) {
+		if !status.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *HealthRegistry) run(ctx context.Context, c *healthCheck) {
+	// Register may replace the check of c while it runs.
+	r.mu.Lock()
+	timeout := r.Timeout
+	check := c.check
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	elapsed := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.ran = true
+	c.status.Healthy = err == nil
+	c.status.Latency = elapsed.String()
+	c.status.CheckedAt = start
+	if err != nil {
+		c.status.LastError = err.Error()
+		c.status.LastErrorAt = &start
+	}
+}
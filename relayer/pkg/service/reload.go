@@ -0,0 +1,102 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrRestartRequired is reported by Reload for settings that changed but only take effect
+// when the service restarts.
+var ErrRestartRequired = errors.New("changed, restart required")
+
+// ReloadStatus is the outcome of the reloads of a service, served on `/reloadz`.
+type ReloadStatus struct {
+	Service  string     `json:"service"`
+	Reloads  int        `json:"reloads"`
+	Failures int        `json:"failures"`
+	Success  bool       `json:"success"`
+	LastAt   *time.Time `json:"last_at,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+	Errors   []string   `json:"errors,omitempty"`
+}
+
+type reloadHistory struct {
+	mu   sync.Mutex
+	last ReloadStatus
+}
+
+func (h *reloadHistory) record(start time.Time, elapsed time.Duration, errs []error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last.Reloads++
+	h.last.Success = len(errs) == 0
+	if !h.last.Success {
+		h.last.Failures++
+	}
+	h.last.LastAt = &start
+	h.last.Duration = elapsed.String()
+	h.last.Errors = make([]string, 0, len(errs))
+	for _, err := range errs {
+		h.last.Errors = append(h.last.Errors, err.Error())
+	}
+}
+
+func (h *reloadHistory) status() ReloadStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	status := h.last
+	status.Errors = append([]string(nil), h.last.Errors...)
+	return status
+}
+
+// ReloadStatus returns the outcome of the last reload.
+func (s *Service) ReloadStatus() ReloadStatus {
+	status := s.reloads.status()
+	status.Service = s.Name
+	return status
+}
+
+// HTTP handler for `/s.Name/reloadz` that exposes the outcome of the last reload as JSON. It
+// responds with status 500 when the last reload reported errors.
+func (s *Service) ReloadHandler(w http.ResponseWriter, r *http.Request) {
+	status := s.ReloadStatus()
+	w.Header().Set("Content-Type", "application/json")
+	if status.Reloads > 0 && !status.Success {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		s.Logger.Error("Failed to encode reload status", "error", err)
+	}
+}
+
+// ChangedSettings compares two configuration structs of the same type and returns the names
+// of the fields that differ, taken from their mapstructure tags when they have one.
+func ChangedSettings(current, next any) []string {
+	a, b := reflect.ValueOf(current), reflect.ValueOf(next)
+	if a.Kind() != reflect.Struct || a.Type() != b.Type() {
+		return nil
+	}
+
+	var changed []string
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			continue
+		}
+		name := field.Tag.Get("mapstructure")
+		if name == "" {
+			name = field.Name
+		}
+		changed = append(changed, name)
+	}
+	return changed
+}
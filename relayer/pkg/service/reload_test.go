@@ -0,0 +1,57 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedSettings(t *testing.T) {
+	type config struct {
+		Level    string        `mapstructure:"TEST_LEVEL"`
+		Interval time.Duration `mapstructure:"TEST_INTERVAL"`
+		Topics   []string
+	}
+
+	current := config{Level: "info", Interval: time.Second, Topics: []string{"a"}}
+	assert.Empty(t, ChangedSettings(current, current))
+
+	next := current
+	next.Interval = time.Minute
+	next.Topics = []string{"a", "b"}
+	assert.Equal(t, []string{"TEST_INTERVAL", "Topics"}, ChangedSettings(current, next))
+}
+
+func TestService_ReloadHandler(t *testing.T) {
+	s := &Service{Name: "test"}
+
+	recorder := httptest.NewRecorder()
+	s.ReloadHandler(recorder, httptest.NewRequest(http.MethodGet, "/reloadz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	s.reloads.record(time.Now(), time.Millisecond, []error{errors.New("TEST_URL: changed, restart required")})
+
+	recorder = httptest.NewRecorder()
+	s.ReloadHandler(recorder, httptest.NewRequest(http.MethodGet, "/reloadz", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	var status ReloadStatus
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&status))
+	assert.Equal(t, "test", status.Service)
+	assert.Equal(t, 1, status.Reloads)
+	assert.Equal(t, 1, status.Failures)
+	assert.False(t, status.Success)
+	assert.Equal(t, []string{"TEST_URL: changed, restart required"}, status.Errors)
+
+	s.reloads.record(time.Now(), time.Millisecond, nil)
+	status = s.ReloadStatus()
+	assert.Equal(t, 2, status.Reloads)
+	assert.True(t, status.Success)
+	assert.Empty(t, status.Errors)
+}
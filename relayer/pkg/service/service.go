@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -13,6 +14,9 @@ import (
 
 	"github.com/henriquemarlon/city.fun/relayer/internal/infra/version"
 	"github.com/lmittmann/tint"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -41,6 +45,7 @@ type IService interface {
 type CreateInfo struct {
 	Name                 string
 	LogLevel             slog.Level
+	LogLevelVar          *slog.LevelVar // shared with other loggers of the process, so that Reload changes their level too
 	LogColor             bool
 	EnableSignalHandling bool
 	TelemetryCreate      bool
@@ -48,6 +53,7 @@ type CreateInfo struct {
 	PollInterval         time.Duration
 	Impl                 ServiceImpl
 	ServeMux             *http.ServeMux
+	Registry             *prometheus.Registry
 	Context              context.Context
 }
 
@@ -57,6 +63,7 @@ type Service struct {
 	Name          string
 	Impl          ServiceImpl
 	Logger        *slog.Logger
+	LogLevel      *slog.LevelVar // level of Logger, which the implementation may change on Reload
 	Ticker        *time.Ticker
 	PollInterval  time.Duration
 	Context       context.Context
@@ -64,8 +71,11 @@ type Service struct {
 	Sighup        chan os.Signal // SIGHUP to reload
 	Sigint        chan os.Signal // SIGINT to exit gracefully
 	ServeMux      *http.ServeMux
+	Registry      *prometheus.Registry // metrics served on `/metrics`
+	Health        *HealthRegistry      // dependency checks that gate Ready()
 	Telemetry     *http.Server
 	TelemetryFunc func() error
+	reloads       reloadHistory
 }
 
 // Create a service by:
@@ -85,8 +95,15 @@ func Create(ctx context.Context, c *CreateInfo, s *Service) error {
 	s.Impl = c.Impl
 
 	// log
+	if s.LogLevel == nil {
+		if c.LogLevelVar == nil {
+			c.LogLevelVar = new(slog.LevelVar)
+			c.LogLevelVar.Set(c.LogLevel)
+		}
+		s.LogLevel = c.LogLevelVar
+	}
 	if s.Logger == nil {
-		s.Logger = NewLogger(c.LogLevel, c.LogColor).With("service", s.Name)
+		s.Logger = NewLogger(s.LogLevel, c.LogColor).With("service", s.Name)
 	}
 
 	// context and cancelation
@@ -121,6 +138,19 @@ func Create(ctx context.Context, c *CreateInfo, s *Service) error {
 		}
 	}
 
+	// health checks
+	if s.Health == nil {
+		s.Health = NewHealthRegistry()
+	}
+
+	// metrics
+	if s.Registry == nil {
+		if c.Registry == nil {
+			c.Registry = NewRegistry(s)
+		}
+		s.Registry = c.Registry
+	}
+
 	// telemetry
 	if c.TelemetryCreate {
 		if s.ServeMux == nil {
@@ -147,14 +177,16 @@ func (s *Service) Alive() bool {
 	return s.Impl.Alive()
 }
 
+// Ready reports whether the implementation is ready and all its health checks pass.
 func (s *Service) Ready() bool {
-	return s.Impl.Ready()
+	return s.Impl.Ready() && s.Health.Healthy(s.Context)
 }
 
 func (s *Service) Reload() []error {
 	start := time.Now()
 	errs := s.Impl.Reload()
 	elapsed := time.Since(start)
+	s.reloads.record(start, elapsed, errs)
 
 	if len(errs) > 0 {
 		s.Logger.Error("Reload",
@@ -227,10 +259,10 @@ func (s *Service) String() string {
 	return s.Name
 }
 
-func NewLogger(level slog.Level, color bool) *slog.Logger {
+func NewLogger(level slog.Leveler, color bool) *slog.Logger {
 	opts := &tint.Options{
 		Level:     level,
-		AddSource: level == slog.LevelDebug,
+		AddSource: level.Level() == slog.LevelDebug,
 		// RFC3339 with milliseconds and without timezone
 		TimeFormat: "2006-01-02T15:04:05.000",
 		NoColor:    !color,
@@ -239,6 +271,63 @@ func NewLogger(level slog.Level, color bool) *slog.Logger {
 	return slog.New(handler)
 }
 
+// NewRegistry creates a registry with the Go runtime and process collectors, plus the
+// build information and the liveness and readiness of the service.
+func NewRegistry(s *Service) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	labels := prometheus.Labels{"service": s.Name}
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "service_build_info",
+		Help:        "Build information of the service, always 1.",
+		ConstLabels: prometheus.Labels{"service": s.Name, "version": version.BuildVersion},
+	})
+	buildInfo.Set(1)
+	registry.MustRegister(
+		buildInfo,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "service_alive",
+			Help:        "Whether the service is alive (1) or not (0).",
+			ConstLabels: labels,
+		}, func() float64 { return boolToFloat(s.Alive()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "service_ready",
+			Help:        "Whether the service is ready (1) or not (0).",
+			ConstLabels: labels,
+		}, func() float64 { return boolToFloat(s.Ready()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "service_reloads_total",
+			Help:        "Configuration reloads triggered by SIGHUP.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(s.reloads.status().Reloads) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "service_reload_failures_total",
+			Help:        "Configuration reloads that reported at least one error.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(s.reloads.status().Failures) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "service_last_reload_success",
+			Help:        "Whether the last configuration reload applied every setting (1) or not (0).",
+			ConstLabels: labels,
+		}, func() float64 {
+			status := s.reloads.status()
+			return boolToFloat(status.Reloads == 0 || status.Success)
+		}),
+	)
+	return registry
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 // Telemetry
 func (s *Service) CreateDefaultTelemetry(
 	addr string,
@@ -247,6 +336,10 @@ func (s *Service) CreateDefaultTelemetry(
 ) (*http.Server, func() error) {
 	s.ServeMux.Handle("/readyz", http.HandlerFunc(s.ReadyHandler))
 	s.ServeMux.Handle("/livez", http.HandlerFunc(s.AliveHandler))
+	s.ServeMux.Handle("/reloadz", http.HandlerFunc(s.ReloadHandler))
+	s.ServeMux.Handle("/metrics", promhttp.HandlerFor(s.Registry, promhttp.HandlerOpts{
+		ErrorLog: slog.NewLogLogger(s.Logger.Handler(), slog.LevelError),
+	}))
 
 	server := &http.Server{
 		Addr:     addr,
@@ -272,8 +365,33 @@ func (s *Service) CreateDefaultTelemetry(
 	}
 }
 
-// HTTP handler for `/s.Name/readyz` that exposes the value of Ready()
+// ReadyStatus is the body of `/readyz?verbose`.
+type ReadyStatus struct {
+	Service string        `json:"service"`
+	Ready   bool          `json:"ready"`
+	Checks  []CheckStatus `json:"checks"`
+}
+
+// HTTP handler for `/s.Name/readyz` that exposes the value of Ready(). With the `verbose`
+// query parameter, it responds with the status of every health check as JSON.
 func (s *Service) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("verbose") {
+		ready := s.Ready()
+		status := ReadyStatus{
+			Service: s.Name,
+			Ready:   ready,
+			Checks:  s.Health.Statuses(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !ready {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			s.Logger.Error("Failed to encode ready status", "error", err)
+		}
+		return
+	}
+
 	if !s.Ready() {
 		http.Error(w, s.Name+": ready check failed",
 			http.StatusInternalServerError)
@@ -0,0 +1,112 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// KMSClient is the part of the AWS KMS API the signer uses.
+type KMSClient interface {
+	GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error)
+	Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error)
+}
+
+// KMS signs with an ECC_SECG_P256K1 key of AWS KMS, which never leaves it.
+type KMS struct {
+	client  KMSClient
+	keyId   string
+	address common.Address
+}
+
+// NewKMS returns a signer for the key keyId, which may be an id, an ARN or an alias. The
+// address is derived from the public key of the key.
+func NewKMS(ctx context.Context, client KMSClient, keyId string) (*KMS, error) {
+	output, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(keyId)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key of KMS key %s: %w", keyId, err)
+	}
+	if output.KeySpec != kmstypes.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("KMS key %s is %s, expected %s", keyId, output.KeySpec, kmstypes.KeySpecEccSecgP256k1)
+	}
+	publicKey, err := parsePublicKey(output.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of KMS key %s: %w", keyId, err)
+	}
+	return &KMS{
+		client:  client,
+		keyId:   keyId,
+		address: crypto.PubkeyToAddress(*publicKey),
+	}, nil
+}
+
+func (k *KMS) Address() common.Address {
+	return k.address
+}
+
+// SignTx has KMS sign the hash of tx, and turns the DER signature it returns into the
+// recoverable form of Ethereum.
+func (k *KMS) SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainId)
+	hash := signer.Hash(tx)
+	output, err := k.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(k.keyId),
+		Message:          hash[:],
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with KMS key %s: %w", k.keyId, err)
+	}
+
+	var der struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(output.Signature, &der); err != nil {
+		return nil, fmt.Errorf("invalid KMS signature: %w", err)
+	}
+	// Ethereum only accepts the signature with s in the lower half of the curve order, which
+	// is as valid as the other one.
+	if der.S.Cmp(secp256k1HalfN) > 0 {
+		der.S = new(big.Int).Sub(secp256k1N, der.S)
+	}
+
+	// KMS does not return the recovery id: it is the one that recovers the address.
+	signature := make([]byte, crypto.SignatureLength)
+	der.R.FillBytes(signature[:32])
+	der.S.FillBytes(signature[32:64])
+	for v := byte(0); v < 2; v++ {
+		signature[64] = v
+		publicKey, err := crypto.SigToPub(hash[:], signature)
+		if err == nil && crypto.PubkeyToAddress(*publicKey) == k.address {
+			return tx.WithSignature(signer, signature)
+		}
+	}
+	return nil, ErrAddressMismatch
+}
+
+// parsePublicKey decodes a DER SubjectPublicKeyInfo holding an uncompressed secp256k1 key,
+// which crypto/x509 does not support.
+func parsePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, err
+	}
+	return crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+}
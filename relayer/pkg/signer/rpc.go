@@ -0,0 +1,141 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Flavor is the JSON-RPC API of a remote signer.
+type Flavor string
+
+const (
+	// Web3Signer signs with eth_signTransaction and lists its keys with eth_accounts.
+	Web3Signer Flavor = "web3signer"
+	// Clef signs with account_signTransaction and lists its keys with account_list.
+	Clef Flavor = "clef"
+)
+
+// ErrNoAccounts is returned by a remote signer that holds no key.
+var ErrNoAccounts = errors.New("remote signer has no accounts")
+
+// RPC signs with a key held by a Web3Signer or Clef, over JSON-RPC.
+type RPC struct {
+	client  *rpc.Client
+	flavor  Flavor
+	address common.Address
+}
+
+// NewRPC returns a signer for the address, or for the first key of the remote signer when
+// address is zero.
+func NewRPC(ctx context.Context, url string, flavor Flavor, address common.Address) (*RPC, error) {
+	if flavor != Web3Signer && flavor != Clef {
+		return nil, fmt.Errorf("invalid remote signer %q, expected web3signer or clef", flavor)
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial remote signer: %w", err)
+	}
+	r := &RPC{client: client, flavor: flavor, address: address}
+	if address == (common.Address{}) {
+		var accounts []common.Address
+		if err := client.CallContext(ctx, &accounts, r.method("eth_accounts", "account_list")); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to list accounts of remote signer: %w", err)
+		}
+		if len(accounts) == 0 {
+			client.Close()
+			return nil, ErrNoAccounts
+		}
+		r.address = accounts[0]
+	}
+	return r, nil
+}
+
+func (r *RPC) Address() common.Address {
+	return r.address
+}
+
+func (r *RPC) Close() {
+	r.client.Close()
+}
+
+// SignTx has the remote signer sign tx, and checks that it signed tx as it was sent, with
+// the key of the address.
+func (r *RPC) SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	args := newTxArgs(r.address, tx, chainId)
+	var raw hexutil.Bytes
+	switch r.flavor {
+	case Web3Signer:
+		if err := r.client.CallContext(ctx, &raw, "eth_signTransaction", args); err != nil {
+			return nil, fmt.Errorf("failed to sign with remote signer: %w", err)
+		}
+	case Clef:
+		var result struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := r.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+			return nil, fmt.Errorf("failed to sign with remote signer: %w", err)
+		}
+		raw = result.Raw
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid transaction from remote signer: %w", err)
+	}
+	signer := types.LatestSignerForChainID(chainId)
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, fmt.Errorf("remote signer returned another transaction than %s", signer.Hash(tx))
+	}
+	if from, err := types.Sender(signer, signed); err != nil || from != r.address {
+		return nil, ErrAddressMismatch
+	}
+	return signed, nil
+}
+
+func (r *RPC) method(web3signer, clef string) string {
+	if r.flavor == Clef {
+		return clef
+	}
+	return web3signer
+}
+
+// txArgs is a transaction as both signers take it.
+type txArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainId              *hexutil.Big    `json:"chainId"`
+}
+
+func newTxArgs(from common.Address, tx *types.Transaction, chainId *big.Int) *txArgs {
+	args := &txArgs{
+		From:    from,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainId: (*hexutil.Big)(chainId),
+	}
+	if tx.Type() == types.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	}
+	return args
+}
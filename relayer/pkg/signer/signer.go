@@ -0,0 +1,42 @@
+// Package signer signs transactions with keys held outside of the relayer: in AWS KMS, or in
+// a Web3Signer or Clef reached over JSON-RPC. Signers plug into bind.TransactOpts, so that
+// contract bindings use them like a local key.
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrAddressMismatch is returned when a signature does not come from the address of the signer.
+var ErrAddressMismatch = errors.New("signature does not match the signer address")
+
+// Signer signs the transactions of a single address.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error)
+}
+
+// NewTransactOpts returns transact options that sign with signer, giving up on a signature
+// after timeout.
+func NewTransactOpts(signer Signer, chainId *big.Int, timeout time.Duration) *bind.TransactOpts {
+	address := signer.Address()
+	return &bind.TransactOpts{
+		From: address,
+		Signer: func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if from != address {
+				return nil, bind.ErrNotAuthorized
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			return signer.SignTx(ctx, tx, chainId)
+		},
+		Context: context.Background(),
+	}
+}
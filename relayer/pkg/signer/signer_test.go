@@ -0,0 +1,180 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var chainId = big.NewInt(31337)
+
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0x8ba1f109551bD432803012645Ac136ddd64DBA72")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       200_000,
+		To:        &to,
+		Data:      []byte{0x40, 0xc1, 0x0f, 0x19},
+	})
+}
+
+func requireSignedBy(t *testing.T, address common.Address, signed *types.Transaction) {
+	t.Helper()
+	from, err := types.Sender(types.LatestSignerForChainID(chainId), signed)
+	require.NoError(t, err)
+	assert.Equal(t, address, from)
+	assert.Equal(t, types.LatestSignerForChainID(chainId).Hash(testTx()), types.LatestSignerForChainID(chainId).Hash(signed))
+}
+
+// mockKMS answers like KMS does for an ECC_SECG_P256K1 key: a DER public key, and DER
+// signatures without recovery id, with s in either half of the curve order.
+type mockKMS struct {
+	key   *ecdsa.PrivateKey
+	highS bool
+}
+
+func (m *mockKMS) GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error) {
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&m.key.PublicKey), BitLength: 65 * 8},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &kms.GetPublicKeyOutput{KeyId: params.KeyId, KeySpec: kmstypes.KeySpecEccSecgP256k1, PublicKey: der}, nil
+}
+
+func (m *mockKMS) Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error) {
+	signature, err := crypto.Sign(params.Message, m.key)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	if m.highS {
+		s.Sub(secp256k1N, s)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{KeyId: params.KeyId, Signature: der}, nil
+}
+
+func TestKMS(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	for _, highS := range []bool{false, true} {
+		signer, err := NewKMS(context.Background(), &mockKMS{key: key, highS: highS}, "alias/relayer")
+		require.NoError(t, err)
+		assert.Equal(t, address, signer.Address())
+
+		opts := NewTransactOpts(signer, chainId, time.Second)
+		signed, err := opts.Signer(address, testTx())
+		require.NoError(t, err)
+		requireSignedBy(t, address, signed)
+
+		_, err = opts.Signer(common.Address{1}, testTx())
+		assert.ErrorIs(t, err, bind.ErrNotAuthorized)
+	}
+}
+
+// mockSigner serves the JSON-RPC API of Web3Signer and Clef with a local key.
+type mockSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (m *mockSigner) Accounts() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(m.key.PublicKey)}
+}
+
+func (m *mockSigner) List() []common.Address {
+	return m.Accounts()
+}
+
+func (m *mockSigner) sign(args txArgs) (hexutil.Bytes, error) {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   args.ChainId.ToInt(),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Data,
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainId.ToInt()), m.key)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+type mockWeb3Signer struct{ *mockSigner }
+
+func (m mockWeb3Signer) SignTransaction(args txArgs) (hexutil.Bytes, error) {
+	return m.sign(args)
+}
+
+type mockClef struct{ *mockSigner }
+
+func (m mockClef) SignTransaction(args txArgs) (map[string]hexutil.Bytes, error) {
+	raw, err := m.sign(args)
+	return map[string]hexutil.Bytes{"raw": raw}, err
+}
+
+func TestRPC(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", mockWeb3Signer{&mockSigner{key}}))
+	require.NoError(t, server.RegisterName("account", mockClef{&mockSigner{key}}))
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	for _, flavor := range []Flavor{Web3Signer, Clef} {
+		signer, err := NewRPC(context.Background(), httpServer.URL, flavor, common.Address{})
+		require.NoError(t, err, flavor)
+		assert.Equal(t, address, signer.Address())
+
+		signed, err := NewTransactOpts(signer, chainId, time.Second).Signer(address, testTx())
+		require.NoError(t, err, flavor)
+		requireSignedBy(t, address, signed)
+		signer.Close()
+	}
+
+	other, err := NewRPC(context.Background(), httpServer.URL, Clef, common.Address{1})
+	require.NoError(t, err)
+	defer other.Close()
+	_, err = other.SignTx(context.Background(), testTx(), chainId)
+	assert.ErrorIs(t, err, ErrAddressMismatch)
+
+	_, err = NewRPC(context.Background(), httpServer.URL, "metamask", common.Address{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
+	. "github.com/henriquemarlon/city.fun/relayer/pkg/sink"
+	kafkasink "github.com/henriquemarlon/city.fun/relayer/pkg/sink/kafka"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/sink/memory"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/sink/mqtt"
+	natssink "github.com/henriquemarlon/city.fun/relayer/pkg/sink/nats"
+)
+
+// Options holds the settings that are not part of the connection string.
+type Options struct {
+	// Name identifies the publisher: it is the MQTT client id, unless the connection string
+	// sets one.
+	Name string
+	// KafkaConfigMap is the configuration of Kafka producers. The brokers of the connection
+	// string, if any, replace its bootstrap.servers.
+	KafkaConfigMap *ckafka.ConfigMap
+}
+
+// NewSinkFromConnectionString creates a message sink from a connection string:
+//   - kafka://[broker1:9092,broker2:9092]
+//   - mqtt://[user:password@]host:1883?qos=1&client_id=relayer (mqtts:// for TLS)
+//   - nats://[user:password@]host:4222
+//   - memory://
+func NewSinkFromConnectionString(ctx context.Context, conn string, options Options) (Sink, error) {
+	lowerConn := strings.ToLower(conn)
+	switch {
+	case strings.HasPrefix(lowerConn, "kafka://"):
+		return newKafkaSink(conn, options)
+	case strings.HasPrefix(lowerConn, "mqtt://"), strings.HasPrefix(lowerConn, "mqtts://"):
+		return newMQTTSink(ctx, conn, options)
+	case strings.HasPrefix(lowerConn, "nats://"):
+		return natssink.New(ctx, conn)
+	case strings.HasPrefix(lowerConn, "memory://"):
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unrecognized connection string format: %s", conn)
+	}
+}
+
+func newKafkaSink(conn string, options Options) (Sink, error) {
+	configMap := ckafka.ConfigMap{}
+	if options.KafkaConfigMap != nil {
+		for key, value := range *options.KafkaConfigMap {
+			configMap[key] = value
+		}
+	}
+
+	brokers := strings.TrimSuffix(conn[len("kafka://"):], "/")
+	if brokers != "" {
+		configMap["bootstrap.servers"] = brokers
+	}
+	if _, ok := configMap["bootstrap.servers"]; !ok {
+		return nil, fmt.Errorf("kafka sink requires brokers")
+	}
+
+	producer, err := kafka.NewKafkaProducer(&configMap)
+	if err != nil {
+		return nil, err
+	}
+	return kafkasink.New(producer), nil
+}
+
+func newMQTTSink(ctx context.Context, conn string, options Options) (Sink, error) {
+	u, err := url.Parse(conn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mqtt connection string: %w", err)
+	}
+
+	scheme := "tcp"
+	if strings.EqualFold(u.Scheme, "mqtts") {
+		scheme = "ssl"
+	}
+
+	config := mqtt.Config{
+		Broker:   scheme + "://" + u.Host,
+		ClientId: options.Name,
+		QoS:      1,
+	}
+	if u.User != nil {
+		config.Username = u.User.Username()
+		config.Password, _ = u.User.Password()
+	}
+
+	query := u.Query()
+	if clientId := query.Get("client_id"); clientId != "" {
+		config.ClientId = clientId
+	}
+	if qos := query.Get("qos"); qos != "" {
+		value, err := strconv.ParseUint(qos, 10, 8)
+		if err != nil || value > 2 {
+			return nil, fmt.Errorf("invalid mqtt qos '%s'", qos)
+		}
+		config.QoS = byte(value)
+	}
+
+	return mqtt.New(ctx, config)
+}
@@ -0,0 +1,64 @@
+package factory
+
+import (
+	"context"
+	"testing"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	kafkasink "github.com/henriquemarlon/city.fun/relayer/pkg/sink/kafka"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/sink/memory"
+)
+
+func TestNewSinkFromConnectionString_Memory(t *testing.T) {
+	s, err := NewSinkFromConnectionString(context.Background(), "memory://", Options{})
+	require.NoError(t, err)
+	require.IsType(t, &memory.Sink{}, s)
+
+	ctx := context.Background()
+	require.NoError(t, s.Publish(ctx, "rewards-dlq", []byte("key"), []byte("value"), map[string]string{"event_id": "1"}))
+	messages := s.(*memory.Sink).Messages("rewards-dlq")
+	require.Len(t, messages, 1)
+	assert.Equal(t, "value", string(messages[0].Value))
+	assert.Equal(t, "1", messages[0].Headers["event_id"])
+
+	assert.NoError(t, s.Close())
+	assert.Error(t, s.Publish(ctx, "rewards-dlq", nil, []byte("value"), nil))
+}
+
+func TestNewSinkFromConnectionString_Kafka(t *testing.T) {
+	options := Options{
+		KafkaConfigMap: &ckafka.ConfigMap{"bootstrap.servers": "localhost:9092"},
+	}
+
+	s, err := NewSinkFromConnectionString(context.Background(), "kafka://", options)
+	require.NoError(t, err)
+	producer := s.(*kafkasink.Sink).Producer
+	assert.Equal(t, "localhost:9092", (*producer.ConfigMap)["bootstrap.servers"])
+	// Nothing was published, but the client may still hold its connection errors.
+	s.(*kafkasink.Sink).FlushTimeout = 0
+	s.Close()
+
+	s, err = NewSinkFromConnectionString(context.Background(), "KAFKA://broker-1:9092,broker-2:9092", options)
+	require.NoError(t, err)
+	producer = s.(*kafkasink.Sink).Producer
+	assert.Equal(t, "broker-1:9092,broker-2:9092", (*producer.ConfigMap)["bootstrap.servers"])
+	s.(*kafkasink.Sink).FlushTimeout = 0
+	s.Close()
+
+	// The options are not modified.
+	assert.Equal(t, "localhost:9092", (*options.KafkaConfigMap)["bootstrap.servers"])
+
+	_, err = NewSinkFromConnectionString(context.Background(), "kafka://", Options{})
+	assert.Error(t, err)
+}
+
+func TestNewSinkFromConnectionString_Invalid(t *testing.T) {
+	_, err := NewSinkFromConnectionString(context.Background(), "amqp://localhost", Options{})
+	assert.Error(t, err)
+
+	_, err = NewSinkFromConnectionString(context.Background(), "mqtt://localhost:1883?qos=3", Options{})
+	assert.Error(t, err)
+}
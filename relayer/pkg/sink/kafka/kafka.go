@@ -0,0 +1,50 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
+)
+
+// Sink is a Sink backed by a Kafka producer. Messages are keyed, so the messages of a key
+// keep their order, and their headers are sent as Kafka headers.
+type Sink struct {
+	Producer *kafka.KafkaProducer
+	// FlushTimeout bounds how long Close waits for the messages still being sent.
+	FlushTimeout time.Duration
+}
+
+func New(producer *kafka.KafkaProducer) *Sink {
+	return &Sink{
+		Producer:     producer,
+		FlushTimeout: 5 * time.Second,
+	}
+}
+
+func (s *Sink) Publish(ctx context.Context, topic string, key, value []byte, headers map[string]string) error {
+	var kafkaHeaders []ckafka.Header
+	if len(headers) > 0 {
+		keys := make([]string, 0, len(headers))
+		for name := range headers {
+			keys = append(keys, name)
+		}
+		slices.Sort(keys)
+		kafkaHeaders = make([]ckafka.Header, 0, len(keys))
+		for _, name := range keys {
+			kafkaHeaders = append(kafkaHeaders, ckafka.Header{Key: name, Value: []byte(headers[name])})
+		}
+	}
+	return s.Producer.Publish(ctx, topic, key, value, kafkaHeaders)
+}
+
+func (s *Sink) Close() error {
+	if remaining := s.Producer.Close(int(s.FlushTimeout.Milliseconds())); remaining > 0 {
+		return fmt.Errorf("kafka producer closed with %d undelivered messages", remaining)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/sink"
+)
+
+// Message is a message published to a Sink.
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Sink is an in-memory Sink, which keeps what it is given until it is closed. It is meant
+// for tests and development: what it holds is lost on restart.
+type Sink struct {
+	mu       sync.Mutex
+	messages []Message
+	closed   bool
+}
+
+func New() *Sink {
+	return &Sink{}
+}
+
+func (s *Sink) Publish(ctx context.Context, topic string, key, value []byte, headers map[string]string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return sink.ErrSinkClosed
+	}
+	s.messages = append(s.messages, Message{
+		Topic:   topic,
+		Key:     slices.Clone(key),
+		Value:   slices.Clone(value),
+		Headers: maps.Clone(headers),
+	})
+	return nil
+}
+
+// Messages returns the messages published to topic, in order.
+func (s *Sink) Messages(topic string) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var messages []Message
+	for _, msg := range s.messages {
+		if msg.Topic == topic {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
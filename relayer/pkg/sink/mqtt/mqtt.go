@@ -0,0 +1,65 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+)
+
+type Config struct {
+	// Broker is the broker URL, such as tcp://localhost:1883 or ssl://broker:8883.
+	Broker   string
+	ClientId string
+	Username string
+	Password string
+	QoS      byte
+}
+
+// Sink is a Sink that publishes to an MQTT broker. MQTT 3.1.1 has neither keys nor headers,
+// so only the value of a message is published.
+type Sink struct {
+	config Config
+	client MQTT.Client
+}
+
+// New connects to the broker.
+func New(ctx context.Context, config Config) (*Sink, error) {
+	options := MQTT.NewClientOptions()
+	options.AddBroker(config.Broker)
+	options.SetClientID(config.ClientId)
+	options.SetUsername(config.Username)
+	options.SetPassword(config.Password)
+	options.SetAutoReconnect(true)
+
+	client := MQTT.NewClient(options)
+	token := client.Connect()
+	select {
+	case <-token.Done():
+	case <-ctx.Done():
+		client.Disconnect(0)
+		return nil, ctx.Err()
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	return &Sink{config: config, client: client}, nil
+}
+
+func (s *Sink) Publish(ctx context.Context, topic string, key, value []byte, headers map[string]string) error {
+	token := s.client.Publish(topic, s.config.QoS, false, value)
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("error publishing message to %s: %w", topic, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Sink) Close() error {
+	s.client.Disconnect(250)
+	return nil
+}
@@ -0,0 +1,20 @@
+package sink
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrSinkClosed = errors.New("message sink is closed")
+
+// Sink publishes messages to a transport: the dead letters, the reward events and the alerts
+// of the relayer. It is the counterpart of a MessageSource, and need not use the same
+// transport.
+type Sink interface {
+	// Publish sends a message to topic and returns once the transport has accepted it.
+	// Transports without keys or headers drop them, so the value must be self-contained.
+	Publish(ctx context.Context, topic string, key, value []byte, headers map[string]string) error
+
+	// Close flushes the messages that are still being sent and releases the sink.
+	Close() error
+}
@@ -0,0 +1,152 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
+	. "github.com/henriquemarlon/city.fun/relayer/pkg/source"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source/jetstream"
+	kafkasource "github.com/henriquemarlon/city.fun/relayer/pkg/source/kafka"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source/memory"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source/mqtt"
+)
+
+// Options holds the settings that are not part of the connection string.
+type Options struct {
+	// Topics are the Kafka topics, MQTT topic filters or NATS subjects to consume.
+	Topics []string
+	// Name identifies the consumer: it is the MQTT client id and the JetStream durable
+	// consumer, unless the connection string sets them.
+	Name   string
+	Logger *slog.Logger
+	// DrainTimeout bounds how long closing the source waits for received messages to be
+	// acknowledged.
+	DrainTimeout time.Duration
+	// KafkaConfigMap is the configuration of Kafka consumers. The brokers of the connection
+	// string, if any, replace its bootstrap.servers.
+	KafkaConfigMap *ckafka.ConfigMap
+	KafkaStartAt   *kafka.StartPosition
+}
+
+// NewSourceFromConnectionString creates a message source from a connection string:
+//   - kafka://[broker1:9092,broker2:9092]
+//   - mqtt://[user:password@]host:1883?qos=1&client_id=relayer (mqtts:// for TLS)
+//   - nats://[user:password@]host:4222?stream=READINGS&consumer=relayer&ack_wait=30
+//   - memory://
+func NewSourceFromConnectionString(ctx context.Context, conn string, options Options) (MessageSource, error) {
+	lowerConn := strings.ToLower(conn)
+	switch {
+	case strings.HasPrefix(lowerConn, "kafka://"):
+		return newKafkaSource(conn, options)
+	case strings.HasPrefix(lowerConn, "mqtt://"), strings.HasPrefix(lowerConn, "mqtts://"):
+		return newMQTTSource(ctx, conn, options)
+	case strings.HasPrefix(lowerConn, "nats://"):
+		return newJetStreamSource(ctx, conn, options)
+	case strings.HasPrefix(lowerConn, "memory://"):
+		return memory.New(100), nil
+	default:
+		return nil, fmt.Errorf("unrecognized connection string format: %s", conn)
+	}
+}
+
+func newKafkaSource(conn string, options Options) (MessageSource, error) {
+	configMap := ckafka.ConfigMap{}
+	if options.KafkaConfigMap != nil {
+		for key, value := range *options.KafkaConfigMap {
+			configMap[key] = value
+		}
+	}
+
+	brokers := strings.TrimSuffix(conn[len("kafka://"):], "/")
+	if brokers != "" {
+		configMap["bootstrap.servers"] = brokers
+	}
+	if _, ok := configMap["bootstrap.servers"]; !ok {
+		return nil, fmt.Errorf("kafka source requires brokers")
+	}
+
+	consumer := kafka.NewKafkaConsumer(&configMap, options.Topics)
+	consumer.DrainTimeout = options.DrainTimeout
+	consumer.StartAt = options.KafkaStartAt
+	if options.Logger != nil {
+		consumer.Logger = options.Logger
+	}
+	return kafkasource.New(consumer), nil
+}
+
+func newMQTTSource(ctx context.Context, conn string, options Options) (MessageSource, error) {
+	u, err := url.Parse(conn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mqtt connection string: %w", err)
+	}
+
+	scheme := "tcp"
+	if strings.EqualFold(u.Scheme, "mqtts") {
+		scheme = "ssl"
+	}
+
+	config := mqtt.Config{
+		Broker:   scheme + "://" + u.Host,
+		ClientId: options.Name,
+		Topics:   options.Topics,
+		QoS:      1,
+		Buffer:   100,
+		Logger:   options.Logger,
+	}
+	if u.User != nil {
+		config.Username = u.User.Username()
+		config.Password, _ = u.User.Password()
+	}
+
+	query := u.Query()
+	if clientId := query.Get("client_id"); clientId != "" {
+		config.ClientId = clientId
+	}
+	if qos := query.Get("qos"); qos != "" {
+		value, err := strconv.ParseUint(qos, 10, 8)
+		if err != nil || value > 2 {
+			return nil, fmt.Errorf("invalid mqtt qos '%s'", qos)
+		}
+		config.QoS = byte(value)
+	}
+
+	return mqtt.New(ctx, config)
+}
+
+func newJetStreamSource(ctx context.Context, conn string, options Options) (MessageSource, error) {
+	u, err := url.Parse(conn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nats connection string: %w", err)
+	}
+
+	query := u.Query()
+	config := jetstream.Config{
+		Stream:       query.Get("stream"),
+		Consumer:     options.Name,
+		Subjects:     options.Topics,
+		DrainTimeout: options.DrainTimeout,
+	}
+	if consumer := query.Get("consumer"); consumer != "" {
+		config.Consumer = consumer
+	}
+	if ackWait := query.Get("ack_wait"); ackWait != "" {
+		seconds, err := strconv.ParseUint(ackWait, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nats ack_wait '%s'", ackWait)
+		}
+		config.AckWait = time.Duration(seconds) * time.Second
+	}
+
+	u.RawQuery = ""
+	config.Url = u.String()
+
+	return jetstream.New(ctx, config)
+}
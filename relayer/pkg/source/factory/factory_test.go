@@ -0,0 +1,56 @@
+package factory
+
+import (
+	"context"
+	"testing"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+
+	kafkasource "github.com/henriquemarlon/city.fun/relayer/pkg/source/kafka"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source/memory"
+)
+
+func TestNewSourceFromConnectionString_Memory(t *testing.T) {
+	s, err := NewSourceFromConnectionString(context.Background(), "memory://", Options{})
+	assert.NoError(t, err)
+	assert.IsType(t, &memory.Source{}, s)
+	assert.NoError(t, s.Close())
+}
+
+func TestNewSourceFromConnectionString_Kafka(t *testing.T) {
+	options := Options{
+		Topics:         []string{"rewards"},
+		KafkaConfigMap: &ckafka.ConfigMap{"bootstrap.servers": "localhost:9092", "group.id": "relayer"},
+	}
+
+	s, err := NewSourceFromConnectionString(context.Background(), "kafka://", options)
+	assert.NoError(t, err)
+	consumer := s.(*kafkasource.Source).Consumer
+	assert.Equal(t, "localhost:9092", (*consumer.ConfigMap)["bootstrap.servers"])
+	assert.Equal(t, []string{"rewards"}, consumer.Topics)
+	assert.NoError(t, s.Close())
+
+	s, err = NewSourceFromConnectionString(context.Background(), "KAFKA://broker-1:9092,broker-2:9092", options)
+	assert.NoError(t, err)
+	consumer = s.(*kafkasource.Source).Consumer
+	assert.Equal(t, "broker-1:9092,broker-2:9092", (*consumer.ConfigMap)["bootstrap.servers"])
+	assert.NoError(t, s.Close())
+
+	// The options are not modified.
+	assert.Equal(t, "localhost:9092", (*options.KafkaConfigMap)["bootstrap.servers"])
+
+	_, err = NewSourceFromConnectionString(context.Background(), "kafka://", Options{})
+	assert.Error(t, err)
+}
+
+func TestNewSourceFromConnectionString_Invalid(t *testing.T) {
+	_, err := NewSourceFromConnectionString(context.Background(), "amqp://localhost", Options{})
+	assert.Error(t, err)
+
+	_, err = NewSourceFromConnectionString(context.Background(), "mqtt://localhost:1883?qos=3", Options{Topics: []string{"sensors/#"}})
+	assert.Error(t, err)
+
+	_, err = NewSourceFromConnectionString(context.Background(), "nats://localhost:4222", Options{Name: "relayer"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/kafka"
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// Source is a MessageSource backed by a Kafka consumer group. Offsets are committed per
+// partition up to the last contiguous acknowledged message. A negatively acknowledged message
+// is delivered again by seeking its partition back to it, which also delivers again the
+// messages of the partition read after it.
+type Source struct {
+	Consumer *kafka.KafkaConsumer
+	messages chan *ckafka.Message
+	start    sync.Once
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	err      error
+}
+
+func New(consumer *kafka.KafkaConsumer) *Source {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Source{
+		Consumer: consumer,
+		// Unbuffered, so that no message is held by the source once Close is called.
+		messages: make(chan *ckafka.Message),
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+func (s *Source) Receive(ctx context.Context) (*source.Message, error) {
+	// The consumer joins its group on the first call, so that partitions are not assigned
+	// before there is someone to process them.
+	s.start.Do(func() { go s.consume() })
+	select {
+	case msg := <-s.messages:
+		return toMessage(msg), nil
+	case <-s.done:
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, source.ErrSourceClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Source) Ack(ctx context.Context, msg *source.Message) error {
+	raw, ok := msg.Raw.(*ckafka.Message)
+	if !ok {
+		return source.ErrUnknownMessage
+	}
+	return s.Consumer.MarkProcessed(raw)
+}
+
+func (s *Source) Nack(ctx context.Context, msg *source.Message) error {
+	raw, ok := msg.Raw.(*ckafka.Message)
+	if !ok {
+		return source.ErrUnknownMessage
+	}
+	return s.Consumer.Redeliver(raw)
+}
+
+// Close stops the consumer, waiting for the messages of its partitions to be acknowledged
+// for up to the consumer's DrainTimeout.
+func (s *Source) Close() error {
+	s.cancel()
+	s.start.Do(func() { close(s.done) })
+	<-s.done
+	return s.err
+}
+
+// Health fails when the consumer is not running or has no partition assigned, which also
+// happens while it joins its group.
+func (s *Source) Health(ctx context.Context) error {
+	assignment, err := s.Consumer.Assignment()
+	if err != nil {
+		return err
+	}
+	if len(assignment) == 0 {
+		return fmt.Errorf("no partitions assigned")
+	}
+	return nil
+}
+
+func (s *Source) Subscribe(ctx context.Context, topics []string) error {
+	return s.Consumer.Subscribe(topics)
+}
+
+// Stats returns the latest statistics of the Kafka client.
+func (s *Source) Stats() *kafka.Stats {
+	return s.Consumer.Stats()
+}
+
+func (s *Source) consume() {
+	defer close(s.done)
+	if err := s.Consumer.Consume(s.ctx, s.messages); err != nil {
+		s.err = fmt.Errorf("kafka source stopped: %w", err)
+	}
+}
+
+func toMessage(msg *ckafka.Message) *source.Message {
+	topic := ""
+	if msg.TopicPartition.Topic != nil {
+		topic = *msg.TopicPartition.Topic
+	}
+
+	var headers map[string]string
+	if len(msg.Headers) > 0 {
+		headers = make(map[string]string, len(msg.Headers))
+		for _, header := range msg.Headers {
+			headers[header.Key] = string(header.Value)
+		}
+	}
+
+	return &source.Message{
+		Id:         fmt.Sprintf("kafka:%s:%d:%d", topic, msg.TopicPartition.Partition, msg.TopicPartition.Offset),
+		Topic:      topic,
+		Partition:  msg.TopicPartition.Partition,
+		Offset:     int64(msg.TopicPartition.Offset),
+		Key:        msg.Key,
+		Value:      msg.Value,
+		Headers:    headers,
+		ReceivedAt: time.Now(),
+		Raw:        msg,
+	}
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// Source is an in-memory MessageSource. Negatively acknowledged messages are delivered again
+// after the messages already queued.
+type Source struct {
+	mu       sync.Mutex
+	queue    chan *source.Message
+	sequence int64
+	inFlight map[string]*source.Message
+	acked    []*source.Message
+	nacked   []*source.Message
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func New(buffer int) *Source {
+	return &Source{
+		queue:    make(chan *source.Message, buffer),
+		inFlight: make(map[string]*source.Message),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Publish queues a message on topic. It blocks while the buffer is full.
+func (s *Source) Publish(ctx context.Context, topic string, key, value []byte) (*source.Message, error) {
+	s.mu.Lock()
+	s.sequence++
+	msg := &source.Message{
+		Id:        fmt.Sprintf("memory:%s:%d", topic, s.sequence),
+		Topic:     topic,
+		Partition: source.PartitionOf(topic),
+		Offset:    s.sequence,
+		Key:       key,
+		Value:     value,
+	}
+	s.mu.Unlock()
+
+	return msg, s.enqueue(ctx, msg)
+}
+
+func (s *Source) Receive(ctx context.Context) (*source.Message, error) {
+	select {
+	case msg := <-s.queue:
+		s.mu.Lock()
+		s.inFlight[msg.Id] = msg
+		s.mu.Unlock()
+		msg.ReceivedAt = time.Now()
+		return msg, nil
+	case <-s.closed:
+		return nil, source.ErrSourceClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Source) Ack(ctx context.Context, msg *source.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inFlight[msg.Id]; !ok {
+		return source.ErrUnknownMessage
+	}
+	delete(s.inFlight, msg.Id)
+	s.acked = append(s.acked, msg)
+	return nil
+}
+
+func (s *Source) Nack(ctx context.Context, msg *source.Message) error {
+	s.mu.Lock()
+	if _, ok := s.inFlight[msg.Id]; !ok {
+		s.mu.Unlock()
+		return source.ErrUnknownMessage
+	}
+	delete(s.inFlight, msg.Id)
+	s.nacked = append(s.nacked, msg)
+	s.mu.Unlock()
+
+	return s.enqueue(ctx, msg)
+}
+
+func (s *Source) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+// Acked returns the acknowledged messages, in the order they were acknowledged.
+func (s *Source) Acked() []*source.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*source.Message(nil), s.acked...)
+}
+
+// Nacked returns the negatively acknowledged messages, in the order they were nacked.
+func (s *Source) Nacked() []*source.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*source.Message(nil), s.nacked...)
+}
+
+// InFlight returns the number of messages received but not acknowledged yet.
+func (s *Source) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.inFlight)
+}
+
+func (s *Source) enqueue(ctx context.Context, msg *source.Message) error {
+	select {
+	case <-s.closed:
+		return source.ErrSourceClosed
+	default:
+	}
+
+	select {
+	case s.queue <- msg:
+		return nil
+	case <-s.closed:
+		return source.ErrSourceClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
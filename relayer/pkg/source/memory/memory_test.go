@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+func TestSource_ReceiveAck(t *testing.T) {
+	s := New(10)
+	ctx := context.Background()
+
+	published, err := s.Publish(ctx, "rewards", []byte("key"), []byte("value"))
+	assert.NoError(t, err)
+
+	msg, err := s.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, published.Id, msg.Id)
+	assert.Equal(t, "rewards", msg.Topic)
+	assert.Equal(t, source.PartitionOf("rewards"), msg.Partition)
+	assert.Equal(t, []byte("value"), msg.Value)
+	assert.Equal(t, 1, s.InFlight())
+
+	assert.NoError(t, s.Ack(ctx, msg))
+	assert.Equal(t, 0, s.InFlight())
+	assert.Len(t, s.Acked(), 1)
+
+	assert.ErrorIs(t, s.Ack(ctx, msg), source.ErrUnknownMessage)
+}
+
+func TestSource_NackRedelivers(t *testing.T) {
+	s := New(10)
+	ctx := context.Background()
+
+	first, _ := s.Publish(ctx, "rewards", nil, []byte("first"))
+	second, _ := s.Publish(ctx, "rewards", nil, []byte("second"))
+
+	msg, err := s.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Id, msg.Id)
+	assert.NoError(t, s.Nack(ctx, msg))
+
+	msg, err = s.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, second.Id, msg.Id)
+
+	msg, err = s.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Id, msg.Id)
+	assert.Len(t, s.Nacked(), 1)
+}
+
+func TestSource_Close(t *testing.T) {
+	s := New(10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := s.Receive(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.NoError(t, s.Close())
+	assert.NoError(t, s.Close())
+
+	_, err = s.Receive(context.Background())
+	assert.ErrorIs(t, err, source.ErrSourceClosed)
+
+	_, err = s.Publish(context.Background(), "rewards", nil, nil)
+	assert.ErrorIs(t, err, source.ErrSourceClosed)
+}
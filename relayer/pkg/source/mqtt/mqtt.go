@@ -0,0 +1,224 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+type Config struct {
+	// Broker is the broker URL, such as tcp://localhost:1883 or ssl://broker:8883.
+	Broker   string
+	ClientId string
+	Username string
+	Password string
+	Topics   []string
+	QoS      byte
+	// Buffer is the number of messages held by the source until they are received.
+	Buffer int
+	Logger *slog.Logger
+}
+
+// Source is a MessageSource subscribed directly to an MQTT broker. It uses a persistent
+// session and acknowledges a message to the broker only when it is acknowledged here, so
+// messages that were not acknowledged are delivered again when the client reconnects. MQTT
+// has no negative acknowledgement: a nacked message is delivered again by the source itself,
+// after the messages already received, and stays unacknowledged to the broker until then.
+type Source struct {
+	config   Config
+	client   MQTT.Client
+	messages chan MQTT.Message
+	closed   chan struct{}
+	once     sync.Once
+	topicsMu sync.Mutex
+}
+
+// New connects to the broker and subscribes to the topics. The subscriptions are renewed
+// every time the client reconnects.
+func New(ctx context.Context, config Config) (*Source, error) {
+	if len(config.Topics) == 0 {
+		return nil, fmt.Errorf("mqtt source requires at least one topic")
+	}
+	if config.Logger == nil {
+		config.Logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelInfo,
+		}))
+	}
+
+	s := &Source{
+		config:   config,
+		messages: make(chan MQTT.Message, config.Buffer),
+		closed:   make(chan struct{}),
+	}
+
+	options := MQTT.NewClientOptions()
+	options.AddBroker(config.Broker)
+	options.SetClientID(config.ClientId)
+	options.SetUsername(config.Username)
+	options.SetPassword(config.Password)
+	options.SetCleanSession(false)
+	options.SetAutoAckDisabled(true)
+	options.SetAutoReconnect(true)
+	options.SetOnConnectHandler(s.subscribe)
+	options.SetConnectionLostHandler(func(client MQTT.Client, err error) {
+		config.Logger.Warn("MQTT connection lost", "error", err)
+	})
+
+	s.client = MQTT.NewClient(options)
+	token := s.client.Connect()
+	select {
+	case <-token.Done():
+	case <-ctx.Done():
+		s.client.Disconnect(0)
+		return nil, ctx.Err()
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Source) Receive(ctx context.Context) (*source.Message, error) {
+	select {
+	case msg := <-s.messages:
+		return toMessage(msg), nil
+	case <-s.closed:
+		return nil, source.ErrSourceClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *Source) Ack(ctx context.Context, msg *source.Message) error {
+	raw, ok := msg.Raw.(MQTT.Message)
+	if !ok {
+		return source.ErrUnknownMessage
+	}
+	raw.Ack()
+	return nil
+}
+
+func (s *Source) Nack(ctx context.Context, msg *source.Message) error {
+	raw, ok := msg.Raw.(MQTT.Message)
+	if !ok {
+		return source.ErrUnknownMessage
+	}
+	// Queued from another goroutine, since the buffer may be full of messages that only the
+	// caller can make room for.
+	go s.handle(s.client, raw)
+	return nil
+}
+
+func (s *Source) Close() error {
+	s.once.Do(func() {
+		close(s.closed)
+		s.client.Disconnect(250)
+	})
+	return nil
+}
+
+func (s *Source) Health(ctx context.Context) error {
+	if !s.client.IsConnectionOpen() {
+		return fmt.Errorf("not connected to MQTT broker")
+	}
+	return nil
+}
+
+// Subscribe subscribes to the topics that were added and unsubscribes from the ones that
+// were dropped. The new topics are also the ones renewed on reconnection.
+func (s *Source) Subscribe(ctx context.Context, topics []string) error {
+	if len(topics) == 0 {
+		return fmt.Errorf("mqtt source requires at least one topic")
+	}
+
+	s.topicsMu.Lock()
+	defer s.topicsMu.Unlock()
+
+	var added, dropped []string
+	for _, topic := range topics {
+		if !slices.Contains(s.config.Topics, topic) {
+			added = append(added, topic)
+		}
+	}
+	for _, topic := range s.config.Topics {
+		if !slices.Contains(topics, topic) {
+			dropped = append(dropped, topic)
+		}
+	}
+
+	if s.client.IsConnectionOpen() {
+		if len(added) > 0 {
+			if err := s.wait(ctx, s.client.SubscribeMultiple(s.filters(added), s.handle)); err != nil {
+				return fmt.Errorf("failed to subscribe to MQTT topics: %w", err)
+			}
+		}
+		if len(dropped) > 0 {
+			if err := s.wait(ctx, s.client.Unsubscribe(dropped...)); err != nil {
+				return fmt.Errorf("failed to unsubscribe from MQTT topics: %w", err)
+			}
+		}
+	}
+	s.config.Topics = slices.Clone(topics)
+	s.config.Logger.Info("MQTT source subscribed", "topics", s.config.Topics, "added", added, "dropped", dropped)
+	return nil
+}
+
+func (s *Source) subscribe(client MQTT.Client) {
+	s.topicsMu.Lock()
+	topics := slices.Clone(s.config.Topics)
+	s.topicsMu.Unlock()
+
+	token := client.SubscribeMultiple(s.filters(topics), s.handle)
+	if token.WaitTimeout(10*time.Second) && token.Error() != nil {
+		s.config.Logger.Error("Failed to subscribe to MQTT topics", "error", token.Error(), "topics", topics)
+		return
+	}
+	s.config.Logger.Info("MQTT source subscribed", "topics", topics, "qos", s.config.QoS)
+}
+
+func (s *Source) filters(topics []string) map[string]byte {
+	filters := make(map[string]byte, len(topics))
+	for _, topic := range topics {
+		filters[topic] = s.config.QoS
+	}
+	return filters
+}
+
+func (s *Source) wait(ctx context.Context, token MQTT.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Source) handle(client MQTT.Client, msg MQTT.Message) {
+	select {
+	case s.messages <- msg:
+	case <-s.closed:
+	}
+}
+
+func toMessage(msg MQTT.Message) *source.Message {
+	// MQTT message ids are reused, so messages are identified by their content.
+	sum := sha256.Sum256(append([]byte(msg.Topic()+"\x00"), msg.Payload()...))
+	return &source.Message{
+		Id:         "mqtt:" + hex.EncodeToString(sum[:]),
+		Topic:      msg.Topic(),
+		Partition:  source.PartitionOf(msg.Topic()),
+		Value:      msg.Payload(),
+		ReceivedAt: time.Now(),
+		Raw:        msg,
+	}
+}
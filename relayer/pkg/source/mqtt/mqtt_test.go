@@ -0,0 +1,62 @@
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/henriquemarlon/city.fun/relayer/pkg/source"
+)
+
+// message is an MQTT message that records whether it was acknowledged to the broker.
+type message struct {
+	topic   string
+	payload []byte
+	acks    int
+}
+
+func (m *message) Duplicate() bool   { return false }
+func (m *message) Qos() byte         { return 1 }
+func (m *message) Retained() bool    { return false }
+func (m *message) Topic() string     { return m.topic }
+func (m *message) MessageID() uint16 { return 1 }
+func (m *message) Payload() []byte   { return m.payload }
+func (m *message) Ack()              { m.acks++ }
+
+func TestSource_NackRedelivers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := &Source{
+		messages: make(chan MQTT.Message, 1),
+		closed:   make(chan struct{}),
+	}
+	first := &message{topic: "sensors/1", payload: []byte("first")}
+	second := &message{topic: "sensors/2", payload: []byte("second")}
+	s.handle(nil, first)
+
+	msg, err := s.Receive(ctx)
+	require.NoError(t, err)
+	s.handle(nil, second)
+
+	// The nacked message is delivered again after the one already received, and is not
+	// acknowledged to the broker until it is acknowledged here.
+	require.NoError(t, s.Nack(ctx, msg))
+	assert.Zero(t, first.acks)
+
+	next, err := s.Receive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(next.Value))
+
+	again, err := s.Receive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, msg.Id, again.Id)
+	require.NoError(t, s.Ack(ctx, again))
+	assert.Equal(t, 1, first.acks)
+
+	assert.ErrorIs(t, s.Nack(ctx, &source.Message{Raw: "other"}), source.ErrUnknownMessage)
+}
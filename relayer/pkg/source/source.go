@@ -0,0 +1,72 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+)
+
+var (
+	ErrSourceClosed = errors.New("message source is closed")
+
+	ErrUnknownMessage = errors.New("message does not belong to this source")
+)
+
+// Message is a message received from a MessageSource.
+type Message struct {
+	// Id identifies the message within its source and stays the same when the message is
+	// redelivered, so it can be used to deduplicate.
+	Id    string
+	Topic string
+	// Partition groups the messages that must be processed in order. Messages of different
+	// partitions may be processed concurrently.
+	Partition int32
+	// Offset is the position of the message in its partition, when the transport has one.
+	Offset     int64
+	Key        []byte
+	Value      []byte
+	Headers    map[string]string
+	ReceivedAt time.Time
+	// Raw is the message as received by the underlying client. Only the source that
+	// produced the message may use it.
+	Raw any
+}
+
+// MessageSource delivers messages with at-least-once semantics. Every message received must
+// eventually be acknowledged, once it is fully handled, or negatively acknowledged, so that
+// it is delivered again.
+type MessageSource interface {
+	// Receive blocks until a message is available. It returns ErrSourceClosed once the source
+	// is closed, the error of ctx when it is done, or the error that stopped the source.
+	Receive(ctx context.Context) (*Message, error)
+
+	Ack(ctx context.Context, msg *Message) error
+
+	Nack(ctx context.Context, msg *Message) error
+
+	// Close stops receiving and releases the source. Messages received before Close may still
+	// be acknowledged while it runs.
+	Close() error
+}
+
+// HealthChecker is implemented by sources that can tell whether they are able to deliver
+// messages.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// Subscriber is implemented by sources that can change their topics while running.
+type Subscriber interface {
+	// Subscribe replaces the topics of the source. Messages of the dropped topics that were
+	// already received must still be acknowledged.
+	Subscribe(ctx context.Context, topics []string) error
+}
+
+// PartitionOf maps a key, such as an MQTT topic or a NATS subject, to a partition, for
+// transports that do not partition their messages.
+func PartitionOf(key string) int32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int32(h.Sum32() & 0x7fffffff)
+}
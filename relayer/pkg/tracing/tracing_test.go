@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupInMemory(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown, err := Setup(context.Background(), Config{
+		ServiceName: "test",
+		SampleRatio: 1,
+		Exporter:    exporter,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { shutdown(context.Background()) })
+	return exporter
+}
+
+func TestInjectExtract(t *testing.T) {
+	exporter := setupInMemory(t)
+
+	ctx, publish := Tracer("test").Start(context.Background(), "publish")
+	properties := Inject(ctx)
+	require.Contains(t, properties, "traceparent")
+
+	// The properties cross the broker as MQTT user properties or Kafka headers.
+	remote := Extract(context.Background(), properties)
+	_, consume := Tracer("test").Start(remote, "consume")
+	consume.End()
+	publish.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "consume", spans[0].Name)
+	assert.Equal(t, spans[1].SpanContext.TraceID(), spans[0].SpanContext.TraceID())
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
+
+func TestInjectWithoutTrace(t *testing.T) {
+	setupInMemory(t)
+
+	assert.Nil(t, Inject(context.Background()))
+
+	ctx := Extract(context.Background(), nil)
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{ServiceName: "test"})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
@@ -19,6 +19,11 @@ type WorkerPool interface {
 	Stop() error
 
 	IsRunning() bool
+
+	// Resize changes the number of workers. Workers that are removed finish the job they are
+	// processing first. With a PartitionFunc, partitions are mapped to workers again only
+	// once every worker is idle, so jobs of a partition are never processed concurrently.
+	Resize(workerCount int) error
 }
 
 type State int
@@ -42,9 +47,15 @@ func (s State) String() string {
 	}
 }
 
+// PartitionFunc returns the partition of a job. Jobs of the same partition are processed by
+// the same worker, one at a time and in the order they were received.
+type PartitionFunc func(job Job) int
+
 type Config struct {
 	WorkerCount int
-	Logger *slog.Logger
+	Logger      *slog.Logger
+	// PartitionFunc enables partition-aware dispatch. When nil, any idle worker takes the next job.
+	PartitionFunc PartitionFunc
 }
 
 func DefaultConfig() Config {
@@ -57,13 +68,19 @@ func DefaultConfig() Config {
 }
 
 type workerPool struct {
-	processFunc ProcessFunc
-	workerCount int
-	stopCh      chan struct{}
-	stopWg      sync.WaitGroup
-	state       State
-	stateMutex  sync.Mutex
-	logger      *slog.Logger
+	processFunc   ProcessFunc
+	partitionFunc PartitionFunc
+	workerCount   int
+	stopCh        chan struct{}
+	resizeCh      chan int
+	quitChs       []chan struct{}
+	ctx           context.Context
+	inputCh       <-chan Job
+	resultCh      chan Result
+	stopWg        sync.WaitGroup
+	state         State
+	stateMutex    sync.Mutex
+	logger        *slog.Logger
 }
 
 func New(processFunc ProcessFunc, config Config) WorkerPool {
@@ -78,11 +95,13 @@ func New(processFunc ProcessFunc, config Config) WorkerPool {
 	}
 
 	return &workerPool{
-		processFunc: processFunc,
-		workerCount: config.WorkerCount,
-		stopCh:      make(chan struct{}),
-		state:       StateIdle,
-		logger:      config.Logger,
+		processFunc:   processFunc,
+		partitionFunc: config.PartitionFunc,
+		workerCount:   config.WorkerCount,
+		stopCh:        make(chan struct{}),
+		resizeCh:      make(chan int),
+		state:         StateIdle,
+		logger:        config.Logger,
 	}
 }
 
@@ -97,10 +116,16 @@ func (wp *workerPool) Start(ctx context.Context, inputCh <-chan Job) (<-chan Res
 	resultCh := make(chan Result)
 	wp.state = StateRunning
 	wp.stopCh = make(chan struct{})
+	wp.ctx = ctx
+	wp.inputCh = inputCh
+	wp.resultCh = resultCh
 
-	wp.stopWg.Add(wp.workerCount)
-	for i := 0; i < wp.workerCount; i++ {
-		go wp.worker(ctx, i, inputCh, resultCh)
+	if wp.partitionFunc == nil {
+		wp.quitChs = nil
+		wp.addWorkers(wp.workerCount)
+	} else {
+		wp.stopWg.Add(1)
+		go wp.dispatch(ctx, inputCh, resultCh, wp.workerCount)
 	}
 
 	go func() {
@@ -135,7 +160,121 @@ func (wp *workerPool) IsRunning() bool {
 	return wp.state == StateRunning
 }
 
-func (wp *workerPool) worker(ctx context.Context, id int, inputCh <-chan Job, resultCh chan<- Result) {
+func (wp *workerPool) Resize(workerCount int) error {
+	if workerCount <= 0 {
+		workerCount = 1
+	}
+
+	wp.stateMutex.Lock()
+	defer wp.stateMutex.Unlock()
+
+	switch wp.state {
+	case StateIdle:
+		wp.workerCount = workerCount
+		return nil
+	case StateStopping:
+		return ErrWorkerPoolNotRunning
+	}
+	if workerCount == wp.workerCount {
+		return nil
+	}
+
+	if wp.partitionFunc != nil {
+		select {
+		case wp.resizeCh <- workerCount:
+		case <-wp.stopCh:
+			return ErrWorkerPoolNotRunning
+		case <-wp.ctx.Done():
+			return wp.ctx.Err()
+		}
+	} else if workerCount > wp.workerCount {
+		wp.addWorkers(workerCount - wp.workerCount)
+	} else {
+		for _, quitCh := range wp.quitChs[workerCount:] {
+			close(quitCh)
+		}
+		wp.quitChs = wp.quitChs[:workerCount]
+	}
+
+	wp.logger.Info("Worker pool resized", "from", wp.workerCount, "to", workerCount)
+	wp.workerCount = workerCount
+	return nil
+}
+
+// addWorkers starts workers that take jobs from the input channel, each one stopped by its
+// own quit channel when the pool shrinks.
+func (wp *workerPool) addWorkers(count int) {
+	for i := 0; i < count; i++ {
+		quitCh := make(chan struct{})
+		wp.stopWg.Add(1)
+		go wp.worker(wp.ctx, len(wp.quitChs), wp.inputCh, wp.resultCh, quitCh)
+		wp.quitChs = append(wp.quitChs, quitCh)
+	}
+}
+
+// dispatch routes every job to the worker that owns its partition. Each worker receives its
+// jobs through an unbuffered channel, so a slow partition only holds back the partitions that
+// share its worker.
+func (wp *workerPool) dispatch(ctx context.Context, inputCh <-chan Job, resultCh chan<- Result, workerCount int) {
+	defer wp.stopWg.Done()
+
+	var workers sync.WaitGroup
+	workerChs := wp.startPartitionWorkers(ctx, workerCount, resultCh, &workers)
+	defer func() {
+		for _, workerCh := range workerChs {
+			close(workerCh)
+		}
+	}()
+
+	for {
+		select {
+		case <-wp.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case workerCount := <-wp.resizeCh:
+			// Let the current workers finish before partitions move to other workers.
+			for _, workerCh := range workerChs {
+				close(workerCh)
+			}
+			workers.Wait()
+			workerChs = wp.startPartitionWorkers(ctx, workerCount, resultCh, &workers)
+		case job, ok := <-inputCh:
+			if !ok {
+				return
+			}
+
+			partition := wp.partitionFunc(job) % len(workerChs)
+			if partition < 0 {
+				partition += len(workerChs)
+			}
+
+			select {
+			case workerChs[partition] <- job:
+			case <-wp.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (wp *workerPool) startPartitionWorkers(ctx context.Context, count int, resultCh chan<- Result, workers *sync.WaitGroup) []chan Job {
+	workerChs := make([]chan Job, count)
+	wp.stopWg.Add(count)
+	workers.Add(count)
+	for i := 0; i < count; i++ {
+		workerChs[i] = make(chan Job)
+		go func(id int) {
+			defer workers.Done()
+			wp.worker(ctx, id, workerChs[id], resultCh, nil)
+		}(i)
+	}
+	return workerChs
+}
+
+func (wp *workerPool) worker(ctx context.Context, id int, inputCh <-chan Job, resultCh chan<- Result, quitCh <-chan struct{}) {
 	defer wp.stopWg.Done()
 
 	wp.logger.Info("Starting worker", "worker_id", id)
@@ -145,6 +284,9 @@ func (wp *workerPool) worker(ctx context.Context, id int, inputCh <-chan Job, re
 		case <-wp.stopCh:
 			wp.logger.Info("Worker stopped", "worker_id", id)
 			return
+		case <-quitCh:
+			wp.logger.Info("Worker removed by resize", "worker_id", id)
+			return
 		case <-ctx.Done():
 			wp.logger.Info("Context cancelled, stopping worker", "worker_id", id)
 			return
@@ -166,4 +308,4 @@ func (wp *workerPool) worker(ctx context.Context, id int, inputCh <-chan Job, re
 			}
 		}
 	}
-}
\ No newline at end of file
+}
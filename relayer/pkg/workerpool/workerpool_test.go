@@ -3,6 +3,7 @@ package workerpool
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -235,3 +236,165 @@ collectResults:
 
 	assert.False(t, pool.IsRunning())
 }
+
+func TestWorkerPool_PartitionOrdering(t *testing.T) {
+	processFunc := func(ctx context.Context, job Job) Result {
+		testJob := job.(TestJob)
+		// Later jobs finish faster, so only per-partition dispatch keeps them in order.
+		time.Sleep(time.Duration(10-testJob.ID%10) * time.Millisecond)
+		return TestResult{
+			JobID:     testJob.ID,
+			Processed: testJob.Data,
+			Success:   true,
+		}
+	}
+
+	config := Config{
+		WorkerCount: 3,
+		PartitionFunc: func(job Job) int {
+			return job.(TestJob).ID % 2
+		},
+	}
+
+	pool := New(processFunc, config)
+
+	inputCh := make(chan Job)
+
+	resultCh, err := pool.Start(context.Background(), inputCh)
+	assert.NoError(t, err)
+
+	numJobs := 10
+
+	go func() {
+		for i := 0; i < numJobs; i++ {
+			inputCh <- TestJob{ID: i, Data: "Job " + string(rune('A'+i))}
+		}
+		close(inputCh)
+	}()
+
+	lastByPartition := map[int]int{0: -1, 1: -1}
+	count := 0
+	for result := range resultCh {
+		testResult := result.(TestResult)
+		partition := testResult.JobID % 2
+		assert.Greater(t, testResult.JobID, lastByPartition[partition])
+		lastByPartition[partition] = testResult.JobID
+		count++
+	}
+
+	assert.Equal(t, numJobs, count)
+	assert.False(t, pool.IsRunning())
+}
+
+func TestWorkerPool_PartitionStop(t *testing.T) {
+	processFunc := func(ctx context.Context, job Job) Result {
+		return TestResult{JobID: job.(TestJob).ID, Success: true}
+	}
+
+	config := Config{
+		WorkerCount: 2,
+		PartitionFunc: func(job Job) int {
+			return -job.(TestJob).ID
+		},
+	}
+
+	pool := New(processFunc, config)
+
+	inputCh := make(chan Job)
+
+	resultCh, err := pool.Start(context.Background(), inputCh)
+	assert.NoError(t, err)
+
+	inputCh <- TestJob{ID: 3}
+	result := <-resultCh
+	assert.Equal(t, 3, result.(TestResult).JobID)
+
+	assert.NoError(t, pool.Stop())
+	assert.False(t, pool.IsRunning())
+}
+
+func TestWorkerPool_Resize(t *testing.T) {
+	var active, maxActive atomic.Int32
+	release := make(chan struct{})
+	processFunc := func(ctx context.Context, job Job) Result {
+		n := active.Add(1)
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		<-release
+		active.Add(-1)
+		return TestResult{JobID: job.(TestJob).ID, Success: true}
+	}
+
+	pool := New(processFunc, Config{WorkerCount: 1})
+
+	inputCh := make(chan Job)
+	resultCh, err := pool.Start(context.Background(), inputCh)
+	assert.NoError(t, err)
+	assert.NoError(t, pool.Resize(3))
+
+	for i := 0; i < 3; i++ {
+		inputCh <- TestJob{ID: i}
+	}
+	assert.Eventually(t, func() bool { return active.Load() == 3 }, time.Second, time.Millisecond)
+	close(release)
+	for i := 0; i < 3; i++ {
+		<-resultCh
+	}
+	assert.Equal(t, int32(3), maxActive.Load())
+
+	assert.NoError(t, pool.Resize(1))
+	inputCh <- TestJob{ID: 3}
+	assert.Equal(t, 3, (<-resultCh).(TestResult).JobID)
+
+	assert.NoError(t, pool.Stop())
+	// A stopped pool starts with the new size.
+	assert.NoError(t, pool.Resize(2))
+}
+
+func TestWorkerPool_PartitionResize(t *testing.T) {
+	processFunc := func(ctx context.Context, job Job) Result {
+		testJob := job.(TestJob)
+		time.Sleep(time.Duration(5-testJob.ID%5) * time.Millisecond)
+		return TestResult{JobID: testJob.ID, Success: true}
+	}
+
+	config := Config{
+		WorkerCount: 1,
+		PartitionFunc: func(job Job) int {
+			return job.(TestJob).ID % 3
+		},
+	}
+
+	pool := New(processFunc, config)
+
+	inputCh := make(chan Job)
+	resultCh, err := pool.Start(context.Background(), inputCh)
+	assert.NoError(t, err)
+
+	numJobs := 30
+	go func() {
+		for i := 0; i < numJobs; i++ {
+			if i == numJobs/2 {
+				assert.NoError(t, pool.Resize(3))
+			}
+			inputCh <- TestJob{ID: i}
+		}
+		close(inputCh)
+	}()
+
+	lastByPartition := map[int]int{0: -1, 1: -1, 2: -1}
+	count := 0
+	for result := range resultCh {
+		testResult := result.(TestResult)
+		partition := testResult.JobID % 3
+		assert.Greater(t, testResult.JobID, lastByPartition[partition])
+		lastByPartition[partition] = testResult.JobID
+		count++
+	}
+
+	assert.Equal(t, numJobs, count)
+}
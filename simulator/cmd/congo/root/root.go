@@ -2,16 +2,20 @@ package root
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
-	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"github.com/henriquemarlon/city.fun/simulator/configs"
 	"github.com/henriquemarlon/city.fun/simulator/internal/infra/repository/factory"
 	"github.com/henriquemarlon/city.fun/simulator/internal/infra/service/simulation"
 	"github.com/henriquemarlon/city.fun/simulator/internal/infra/version"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/device"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/events"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/mqtt"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/service"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/tracing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -19,6 +23,7 @@ import (
 const serviceName = "simulator"
 
 var (
+	configFile          string
 	logColor            bool
 	logLevel            string
 	sensorServerAddress string
@@ -46,6 +51,8 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.Flags().StringVar(&configFile, "config", "", "Config file with the same keys as the environment variables, read again on SIGHUP. Environment variables take precedence over it")
+	cobra.CheckErr(viper.BindPFlag("config", Cmd.Flags().Lookup("config")))
 	Cmd.Flags().BoolVar(&logColor, "log-color", true, "Tint the logs (colored output)")
 	cobra.CheckErr(viper.BindPFlag(configs.LOG_COLOR, Cmd.Flags().Lookup("log-color")))
 	Cmd.Flags().StringVar(&logLevel, "log-level", "info", "Log level: debug, info, warn or error")
@@ -98,10 +105,15 @@ func run(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.MaxStartupTime)
 	defer cancel()
 
+	// Shared by every logger, so that a reload changes the level of all of them.
+	logLevelVar := new(slog.LevelVar)
+	logLevelVar.Set(cfg.LogLevel)
+
 	createInfo := simulation.CreateInfo{
 		CreateInfo: service.CreateInfo{
 			Name:                 serviceName,
 			LogLevel:             cfg.LogLevel,
+			LogLevelVar:          logLevelVar,
 			LogColor:             cfg.LogColor,
 			EnableSignalHandling: true,
 			TelemetryCreate:      true,
@@ -110,7 +122,20 @@ func run(cmd *cobra.Command, args []string) {
 		Config: *cfg,
 	}
 
-	var err error
+	tracingEndpoint, _ := configs.GetTracingEndpoint()
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		ServiceName:    serviceName,
+		ServiceVersion: version.BuildVersion,
+		Endpoint:       tracingEndpoint,
+		SampleRatio:    cfg.TracingSampleRatio,
+	})
+	cobra.CheckErr(err)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
+
 	createInfo.Repository, err = factory.NewRepositoryFromConnectionString(
 		ctx,
 		cfg.DatabaseUrl.String(),
@@ -123,26 +148,37 @@ func run(cmd *cobra.Command, args []string) {
 
 	createInfo.EventDispatcher = events.NewEventDispatcher()
 
-	mqttClientOptions := MQTT.NewClientOptions()
-	mqttClientOptions.AddBroker(cfg.HivemqUrl)
-	mqttClientOptions.SetUsername(cfg.HivemqUsername)
-	mqttClientOptions.SetPassword(cfg.HivemqPassword.Value)
-	mqttClientOptions.SetClientID("simulator")
-	mqttClientOptions.SetAutoReconnect(false)
-	mqttClientOptions.SetConnectTimeout(5 * time.Second)
-	mqttClientOptions.SetOrderMatters(false)
-
-	createInfo.MqttClient = MQTT.NewClient(mqttClientOptions)
-	if token := createInfo.MqttClient.Connect(); token.Wait() && token.Error() != nil {
-		cobra.CheckErr(fmt.Errorf("failed to connect to MQTT broker: %w", token.Error()))
-	}
+	createInfo.DeviceRegistry, err = deviceRegistry()
+	cobra.CheckErr(err)
 
-	if !createInfo.MqttClient.IsConnected() {
-		cobra.CheckErr(fmt.Errorf("failed to establish MQTT connection"))
-	}
+	createInfo.MqttClient, err = mqtt.Connect(ctx, mqtt.Config{
+		Broker:         cfg.HivemqUrl,
+		ClientId:       "simulator",
+		Username:       cfg.HivemqUsername,
+		Password:       cfg.HivemqPassword.Value,
+		ConnectTimeout: 5 * time.Second,
+		Logger:         service.NewLogger(logLevelVar, cfg.LogColor).With("service", serviceName),
+	})
+	cobra.CheckErr(err)
 
 	simulationService, err := simulation.Create(ctx, &createInfo)
 	cobra.CheckErr(err)
 
 	cobra.CheckErr(simulationService.Serve())
 }
+
+// deviceRegistry returns the client that enrolls the keys of the sensors with the relayer,
+// or nil when no relayer is configured.
+func deviceRegistry() (*device.Registry, error) {
+	url, err := configs.GetRelayerUrl()
+	if errors.Is(err, configs.ErrNotDefined) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	token, err := configs.GetRelayerEnrollmentToken()
+	if err != nil {
+		return nil, fmt.Errorf("%s requires %s: %w", configs.RELAYER_URL, configs.RELAYER_ENROLLMENT_TOKEN, err)
+	}
+	return device.NewRegistry(url, token.Value), nil
+}
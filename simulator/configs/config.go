@@ -44,6 +44,10 @@ func ToUint64FromDecimalOrHexString(s string) (uint64, error) {
 	return ToUint64FromString(s)
 }
 
+func ToFloat64FromString(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
 func ToStringFromString(s string) (string, error) {
 	return s, nil
 }
@@ -88,6 +92,7 @@ func ToURLFromString(s string) (URL, error) {
 var (
 	toBool           = strconv.ParseBool
 	toString         = ToStringFromString
+	toFloat64        = ToFloat64FromString
 	toDuration       = ToDurationFromSeconds
 	toLogLevel       = ToLogLevelFromString
 	toRedactedString = ToRedactedStringFromString
@@ -97,6 +102,7 @@ var (
 var (
 	notDefinedbool           = func() bool { return false }
 	notDefinedstring         = func() string { return "" }
+	notDefinedfloat64        = func() float64 { return 0 }
 	notDefinedDuration       = func() time.Duration { return 0 }
 	notDefinedLogLevel       = func() slog.Level { return slog.LevelInfo }
 	notDefinedRedactedString = func() RedactedString { return RedactedString{""} }
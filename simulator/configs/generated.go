@@ -18,19 +18,23 @@ func init() {
 }
 
 const (
-	DATABASE_COLLECTION   = "SIMULATOR_DATABASE_COLLECTION"
-	DATABASE_NAME         = "SIMULATOR_DATABASE_NAME"
-	DATABASE_URL          = "SIMULATOR_DATABASE_URL"
-	HIVEMQ_MQTT_TOPIC     = "SIMULATOR_HIVEMQ_MQTT_TOPIC"
-	HIVEMQ_PASSWORD       = "SIMULATOR_HIVEMQ_PASSWORD"
-	HIVEMQ_URL            = "SIMULATOR_HIVEMQ_URL"
-	HIVEMQ_USERNAME       = "SIMULATOR_HIVEMQ_USERNAME"
-	LOG_COLOR             = "SIMULATOR_LOG_COLOR"
-	LOG_LEVEL             = "SIMULATOR_LOG_LEVEL"
-	MAX_STARTUP_TIME      = "SIMULATOR_MAX_STARTUP_TIME"
-	PUSH_INTERVAL         = "SIMULATOR_PUSH_INTERVAL"
-	SENSOR_SERVER_ADDRESS = "SIMULATOR_SENSOR_SERVER_ADDRESS"
-	TELEMETRY_ADDRESS     = "SIMULATOR_TELEMETRY_ADDRESS"
+	DATABASE_COLLECTION      = "SIMULATOR_DATABASE_COLLECTION"
+	DATABASE_NAME            = "SIMULATOR_DATABASE_NAME"
+	DATABASE_URL             = "SIMULATOR_DATABASE_URL"
+	HIVEMQ_MQTT_TOPIC        = "SIMULATOR_HIVEMQ_MQTT_TOPIC"
+	HIVEMQ_PASSWORD          = "SIMULATOR_HIVEMQ_PASSWORD"
+	HIVEMQ_URL               = "SIMULATOR_HIVEMQ_URL"
+	HIVEMQ_USERNAME          = "SIMULATOR_HIVEMQ_USERNAME"
+	RELAYER_ENROLLMENT_TOKEN = "SIMULATOR_RELAYER_ENROLLMENT_TOKEN"
+	RELAYER_URL              = "SIMULATOR_RELAYER_URL"
+	LOG_COLOR                = "SIMULATOR_LOG_COLOR"
+	LOG_LEVEL                = "SIMULATOR_LOG_LEVEL"
+	MAX_STARTUP_TIME         = "SIMULATOR_MAX_STARTUP_TIME"
+	PUSH_INTERVAL            = "SIMULATOR_PUSH_INTERVAL"
+	SENSOR_SERVER_ADDRESS    = "SIMULATOR_SENSOR_SERVER_ADDRESS"
+	TELEMETRY_ADDRESS        = "SIMULATOR_TELEMETRY_ADDRESS"
+	TRACING_ENDPOINT         = "SIMULATOR_TRACING_ENDPOINT"
+	TRACING_SAMPLE_RATIO     = "SIMULATOR_TRACING_SAMPLE_RATIO"
 
 	// File variants
 
@@ -58,6 +62,10 @@ func SetDefaults() {
 
 	// no default for SIMULATOR_HIVEMQ_USERNAME
 
+	// no default for SIMULATOR_RELAYER_ENROLLMENT_TOKEN
+
+	// no default for SIMULATOR_RELAYER_URL
+
 	viper.SetDefault(LOG_COLOR, "true")
 
 	viper.SetDefault(LOG_LEVEL, "info")
@@ -70,6 +78,10 @@ func SetDefaults() {
 
 	// no default for SIMULATOR_TELEMETRY_ADDRESS
 
+	// no default for SIMULATOR_TRACING_ENDPOINT
+
+	viper.SetDefault(TRACING_SAMPLE_RATIO, "1")
+
 }
 
 // SimulatorConfig holds configuration values for the simulator service.
@@ -113,6 +125,9 @@ type SimulatorConfig struct {
 
 	// Telemetry address for the service
 	TelemetryAddress string `mapstructure:"SIMULATOR_TELEMETRY_ADDRESS"`
+
+	// Fraction of new traces that are recorded, from 0 to 1. Traces continued from a message follow the decision of its producer
+	TracingSampleRatio float64 `mapstructure:"SIMULATOR_TRACING_SAMPLE_RATIO"`
 }
 
 // LoadSimulatorConfig reads configuration from environment variables, a config file, and defaults.
@@ -222,6 +237,13 @@ func LoadSimulatorConfig() (*SimulatorConfig, error) {
 		return nil, fmt.Errorf("SIMULATOR_TELEMETRY_ADDRESS is required for the simulator service: %w", err)
 	}
 
+	cfg.TracingSampleRatio, err = GetTracingSampleRatio()
+	if err != nil && err != ErrNotDefined {
+		return nil, fmt.Errorf("failed to get SIMULATOR_TRACING_SAMPLE_RATIO: %w", err)
+	} else if err == ErrNotDefined {
+		return nil, fmt.Errorf("SIMULATOR_TRACING_SAMPLE_RATIO is required for the simulator service: %w", err)
+	}
+
 	return &cfg, nil
 }
 
@@ -348,6 +370,32 @@ func GetHivemqUsername() (string, error) {
 	return notDefinedstring(), fmt.Errorf("%s: %w", HIVEMQ_USERNAME, ErrNotDefined)
 }
 
+// GetRelayerEnrollmentToken returns the value for the environment variable SIMULATOR_RELAYER_ENROLLMENT_TOKEN.
+func GetRelayerEnrollmentToken() (RedactedString, error) {
+	s := viper.GetString(RELAYER_ENROLLMENT_TOKEN)
+	if s != "" {
+		v, err := toRedactedString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", RELAYER_ENROLLMENT_TOKEN, err)
+		}
+		return v, nil
+	}
+	return notDefinedRedactedString(), fmt.Errorf("%s: %w", RELAYER_ENROLLMENT_TOKEN, ErrNotDefined)
+}
+
+// GetRelayerUrl returns the value for the environment variable SIMULATOR_RELAYER_URL.
+func GetRelayerUrl() (string, error) {
+	s := viper.GetString(RELAYER_URL)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", RELAYER_URL, err)
+		}
+		return v, nil
+	}
+	return notDefinedstring(), fmt.Errorf("%s: %w", RELAYER_URL, ErrNotDefined)
+}
+
 // GetLogColor returns the value for the environment variable SIMULATOR_LOG_COLOR.
 func GetLogColor() (bool, error) {
 	s := viper.GetString(LOG_COLOR)
@@ -425,3 +473,29 @@ func GetTelemetryAddress() (string, error) {
 	}
 	return notDefinedstring(), fmt.Errorf("%s: %w", TELEMETRY_ADDRESS, ErrNotDefined)
 }
+
+// GetTracingEndpoint returns the value for the environment variable SIMULATOR_TRACING_ENDPOINT.
+func GetTracingEndpoint() (string, error) {
+	s := viper.GetString(TRACING_ENDPOINT)
+	if s != "" {
+		v, err := toString(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", TRACING_ENDPOINT, err)
+		}
+		return v, nil
+	}
+	return notDefinedstring(), fmt.Errorf("%s: %w", TRACING_ENDPOINT, ErrNotDefined)
+}
+
+// GetTracingSampleRatio returns the value for the environment variable SIMULATOR_TRACING_SAMPLE_RATIO.
+func GetTracingSampleRatio() (float64, error) {
+	s := viper.GetString(TRACING_SAMPLE_RATIO)
+	if s != "" {
+		v, err := toFloat64(s)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", TRACING_SAMPLE_RATIO, err)
+		}
+		return v, nil
+	}
+	return notDefinedfloat64(), fmt.Errorf("%s: %w", TRACING_SAMPLE_RATIO, ErrNotDefined)
+}
@@ -8,16 +8,19 @@ import (
 
 var (
 	ErrSensorNotFound = errors.New("sensor not found")
+	ErrInvalidSensor  = errors.New("invalid sensor")
 )
 
 type Sensor struct {
-	Id        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name      string             `bson:"name" json:"name"`
-	Latitude  float64            `bson:"latitude" json:"latitude"`
-	Longitude float64            `bson:"longitude" json:"longitude"`
-	Receiver  string             `bson:"receiver" json:"receiver"`
-	Amount    string             `bson:"amount" json:"amount"`
-	Params    map[string]Param   `bson:"params" json:"params"`
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name       string             `bson:"name" json:"name"`
+	Latitude   float64            `bson:"latitude" json:"latitude"`
+	Longitude  float64            `bson:"longitude" json:"longitude"`
+	Receiver   string             `bson:"receiver" json:"receiver"`
+	Amount     string             `bson:"amount" json:"amount"`
+	Params     map[string]Param   `bson:"params" json:"params"`
+	PublicKey  string             `bson:"public_key,omitempty" json:"public_key,omitempty"` // hex encoded key the relayer verifies the readings with
+	PrivateKey string             `bson:"private_key,omitempty" json:"-"`                   // hex encoded seed that signs the readings, never served
 }
 
 type Param struct {
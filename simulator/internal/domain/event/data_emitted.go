@@ -1,6 +1,7 @@
 package event
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -8,6 +9,7 @@ import (
 type DataEmitted struct {
 	Name    string
 	Payload interface{}
+	Context context.Context
 }
 
 func NewDataEmitted(sensorId string) *DataEmitted {
@@ -28,6 +30,14 @@ func (e *DataEmitted) SetPayload(payload interface{}) {
 	e.Payload = payload
 }
 
+func (e *DataEmitted) GetContext() context.Context {
+	return e.Context
+}
+
+func (e *DataEmitted) SetContext(ctx context.Context) {
+	e.Context = ctx
+}
+
 func (e *DataEmitted) GetDateTime() time.Time {
 	return time.Now()
 }
@@ -1,22 +1,29 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"sync"
 	"time"
 
-	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/events"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/mqtt"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type DataEmittedHandler struct {
-	Client    MQTT.Client
+	Client    *mqtt.Client
 	MqttTopic string
+	// OnPublish, when set, is called after every publish with its duration and error.
+	OnPublish func(elapsed time.Duration, err error)
 }
 
-func NewDataEmittedHandler(client MQTT.Client, mqttTopic string) *DataEmittedHandler {
+func NewDataEmittedHandler(client *mqtt.Client, mqttTopic string) *DataEmittedHandler {
 	return &DataEmittedHandler{
 		Client:    client,
 		MqttTopic: mqttTopic,
@@ -32,10 +39,28 @@ func (h *DataEmittedHandler) Handle(event events.EventInterface, wg *sync.WaitGr
 		slog.Error("Error serializing the payload", "error", err)
 	}
 
-	token := h.Client.Publish(h.MqttTopic, 1, false, bytesPayload)
-	token.WaitTimeout(2 * time.Second)
-	if token.Error() != nil {
-		slog.Error("Failed to publish the message", "error", token.Error())
+	ctx, span := tracing.Tracer("simulator").Start(events.EventContext(event), "mqtt.publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "mqtt"),
+			attribute.String("messaging.destination.name", h.MqttTopic),
+		))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	// The trace context travels as MQTT user properties, which the broker's Kafka extension
+	// forwards as Kafka headers.
+	start := time.Now()
+	err = h.Client.Publish(ctx, h.MqttTopic, 1, bytesPayload, tracing.Inject(ctx))
+	if h.OnPublish != nil {
+		h.OnPublish(time.Since(start), err)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "publish failed")
+		slog.Error("Failed to publish the message", "error", err)
 	}
 
 	var payload struct {
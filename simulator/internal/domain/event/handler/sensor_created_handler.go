@@ -5,18 +5,19 @@ import (
 	"log/slog"
 	"sync"
 
-	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"github.com/henriquemarlon/city.fun/simulator/internal/domain/entity"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/events"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/mqtt"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type SensorCreatedHandler struct {
-	Client        MQTT.Client
+	Client        *mqtt.Client
 	SensorChannel chan *entity.Sensor
 }
 
-func NewSensorCreatedHandler(client MQTT.Client, sensorChan chan *entity.Sensor) *SensorCreatedHandler {
+func NewSensorCreatedHandler(client *mqtt.Client, sensorChan chan *entity.Sensor) *SensorCreatedHandler {
 	return &SensorCreatedHandler{
 		Client:        client,
 		SensorChannel: sensorChan,
@@ -32,11 +33,10 @@ func (h *SensorCreatedHandler) Handle(event events.EventInterface, wg *sync.Wait
 		slog.Error("Error serializing the raw payload", "error", err)
 	}
 
-	token := h.Client.Publish("sensors/created", 1, false, bytesPayload)
-	if token.Error() != nil {
-		slog.Error("Failed to publish the message", "error", token.Error())
+	ctx := events.EventContext(event)
+	if err := h.Client.Publish(ctx, "sensors/created", 1, bytesPayload, tracing.Inject(ctx)); err != nil {
+		slog.Error("Failed to publish the message", "error", err)
 	}
-	token.Wait()
 
 	var payload struct {
 		Id        primitive.ObjectID      `json:"id"`
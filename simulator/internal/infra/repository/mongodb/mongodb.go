@@ -5,10 +5,12 @@ import (
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type MongoDBRepository struct {
 	Collection *mongo.Collection
+	Sequences  *mongo.Collection // last sequence number of every sensor, apart so that it does not restart the workers
 }
 
 func NewMongoDBRepository(conn, database, collection string) (*MongoDBRepository, error) {
@@ -22,13 +24,18 @@ func NewMongoDBRepository(conn, database, collection string) (*MongoDBRepository
 		return nil, err
 	}
 
-	coll := client.Database(database).Collection(collection)
+	db := client.Database(database)
 
 	return &MongoDBRepository{
-		Collection: coll,
+		Collection: db.Collection(collection),
+		Sequences:  db.Collection(collection + "_sequences"),
 	}, nil
 }
 
+func (m *MongoDBRepository) Ping(ctx context.Context) error {
+	return m.Collection.Database().Client().Ping(ctx, readpref.Primary())
+}
+
 func (m *MongoDBRepository) Close() error {
 	return m.Collection.Database().Client().Disconnect(context.Background())
 }
\ No newline at end of file
@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (s *MongoDBRepository) CreateSensor(ctx context.Context, input *entity.Sensor) (*entity.Sensor, error) {
@@ -35,6 +36,34 @@ func (s *MongoDBRepository) FindSensorById(ctx context.Context, id primitive.Obj
 	return &sensor, nil
 }
 
+// SetSensorKey only writes the key pair if the sensor has none, so that concurrent callers
+// all end up with the same one.
+func (s *MongoDBRepository) SetSensorKey(ctx context.Context, id primitive.ObjectID, publicKey, privateKey string) (*entity.Sensor, error) {
+	_, err := s.Collection.UpdateOne(ctx,
+		bson.M{"_id": id, "private_key": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"public_key": publicKey, "private_key": privateKey}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s.FindSensorById(ctx, id)
+}
+
+func (s *MongoDBRepository) NextSensorSequence(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	var counter struct {
+		Sequence int64 `bson:"sequence"`
+	}
+	err := s.Sequences.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"sequence": int64(1)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Sequence, nil
+}
+
 func (s *MongoDBRepository) FindAllSensors(ctx context.Context) ([]*entity.Sensor, error) {
 	cursor, err := s.Collection.Find(ctx, bson.M{})
 	if err != nil {
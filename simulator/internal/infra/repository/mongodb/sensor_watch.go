@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/simulator/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/simulator/internal/infra/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Server error codes of change streams that cannot be opened or resumed.
+const (
+	codeInvalidResumeToken      = 260
+	codeChangeStreamFatalError  = 280
+	codeChangeStreamHistoryLost = 286
+	codeChangeStreamNotReplSet  = 40573
+)
+
+type sensorChangeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		Id primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument *entity.Sensor `bson:"fullDocument"`
+}
+
+func (s *MongoDBRepository) WatchSensors(ctx context.Context, resumeToken bson.Raw, opened func(), handle func(repository.SensorChange) error) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete", "invalidate"}},
+		}}},
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if resumeToken != nil {
+		opts.SetResumeAfter(resumeToken)
+	}
+
+	stream, err := s.Collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return watchError(err)
+	}
+	defer stream.Close(context.Background())
+
+	if opened != nil {
+		opened()
+	}
+
+	for stream.Next(ctx) {
+		var event sensorChangeEvent
+		if err := stream.Decode(&event); err != nil {
+			return fmt.Errorf("failed to decode sensor change: %w", err)
+		}
+
+		change := repository.SensorChange{
+			Id:          event.DocumentKey.Id,
+			Sensor:      event.FullDocument,
+			ResumeToken: stream.ResumeToken(),
+		}
+		switch event.OperationType {
+		case "insert":
+			change.Op = repository.SensorInserted
+		case "update", "replace":
+			change.Op = repository.SensorUpdated
+		case "delete":
+			change.Op = repository.SensorDeleted
+		case "invalidate":
+			// The collection was dropped or renamed. The stream cannot be resumed past this.
+			return repository.ErrResumeTokenLost
+		default:
+			continue
+		}
+		// An update is looked up after the fact, so the sensor may be gone by now.
+		if change.Op == repository.SensorUpdated && change.Sensor == nil {
+			change.Op = repository.SensorDeleted
+		}
+
+		if err := handle(change); err != nil {
+			return err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return watchError(err)
+	}
+	return ctx.Err()
+}
+
+func watchError(err error) error {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return err
+	}
+	switch {
+	case serverErr.HasErrorCode(codeChangeStreamNotReplSet):
+		return fmt.Errorf("%w: %v", repository.ErrWatchNotSupported, err)
+	case serverErr.HasErrorCode(codeChangeStreamHistoryLost),
+		serverErr.HasErrorCode(codeChangeStreamFatalError),
+		serverErr.HasErrorCode(codeInvalidResumeToken):
+		return fmt.Errorf("%w: %v", repository.ErrResumeTokenLost, err)
+	}
+	return err
+}
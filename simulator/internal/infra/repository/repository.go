@@ -2,18 +2,58 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/henriquemarlon/city.fun/simulator/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	// ErrResumeTokenLost is returned by WatchSensors when the stream cannot continue from the
+	// given resume token, because the change history no longer holds it or the stream was
+	// invalidated. The caller has to reload the sensors and watch again without a token.
+	ErrResumeTokenLost = errors.New("sensor change stream cannot be resumed")
+	// ErrWatchNotSupported is returned by WatchSensors when the database cannot stream
+	// changes, such as a MongoDB server that is not part of a replica set.
+	ErrWatchNotSupported = errors.New("sensor change stream not supported by the database")
+)
+
+type SensorChangeOp string
+
+const (
+	SensorInserted SensorChangeOp = "insert"
+	SensorUpdated  SensorChangeOp = "update"
+	SensorDeleted  SensorChangeOp = "delete"
+)
+
+// SensorChange is a change to the sensor collection. Sensor holds the sensor after the change
+// and is nil for deletes. ResumeToken resumes the stream right after this change.
+type SensorChange struct {
+	Op          SensorChangeOp
+	Id          primitive.ObjectID
+	Sensor      *entity.Sensor
+	ResumeToken bson.Raw
+}
+
 type SensorRepository interface {
 	CreateSensor(ctx context.Context, sensor *entity.Sensor) (*entity.Sensor, error)
 	FindSensorById(ctx context.Context, id primitive.ObjectID) (*entity.Sensor, error)
 	FindAllSensors(ctx context.Context) ([]*entity.Sensor, error)
+	// SetSensorKey gives a key pair to a sensor that has none, and returns the sensor with
+	// the key pair it ends up with.
+	SetSensorKey(ctx context.Context, id primitive.ObjectID, publicKey, privateKey string) (*entity.Sensor, error)
+	// NextSensorSequence returns the sequence number of the next reading of a sensor, one
+	// more than the last one it returned, starting at 1.
+	NextSensorSequence(ctx context.Context, id primitive.ObjectID) (int64, error)
+	// WatchSensors calls handle with every change to the sensors after resumeToken, or after
+	// the stream is opened when resumeToken is nil. opened is called once the stream is open.
+	// It blocks until ctx is done, the stream fails or handle returns an error.
+	WatchSensors(ctx context.Context, resumeToken bson.Raw, opened func(), handle func(SensorChange) error) error
 }
 
 type Repository interface {
 	SensorRepository
+	Ping(ctx context.Context) error
 	Close() error
 }
@@ -0,0 +1,43 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/simulator/pkg/device"
+)
+
+const (
+	registerMinWait = time.Second
+	registerMaxWait = time.Minute
+)
+
+// registerDevice registers the public key of a sensor with the relayer, retrying
+// until it succeeds or workerCtx is done. The relayer holds back the readings of the sensor
+// until then. It gives up when the relayer has another key for the sensor, which an operator
+// has to replace.
+func (s *Service) registerDevice(workerCtx context.Context, registration device.Registration) {
+	id := registration.SensorId
+	wait := registerMinWait
+	for {
+		err := s.deviceRegistry.Register(workerCtx, registration)
+		if err == nil {
+			s.Logger.Info("Sensor registered with the relayer", "id", id)
+			return
+		}
+		s.metrics.registerFailures.Inc()
+		if errors.Is(err, device.ErrKeyRegistered) {
+			s.Logger.Error("Relayer has another key for the sensor, its readings will be held back", "id", id)
+			return
+		}
+		s.Logger.Warn("Failed to register sensor with the relayer", "id", id, "error", err, "retry_in", wait)
+
+		select {
+		case <-workerCtx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(2*wait, registerMaxWait)
+	}
+}
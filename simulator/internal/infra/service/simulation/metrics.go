@@ -0,0 +1,60 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	emissions        *prometheus.CounterVec
+	emitFailures     *prometheus.CounterVec
+	publishDuration  prometheus.Histogram
+	publishFailures  prometheus.Counter
+	activeWorkers    prometheus.Gauge
+	sensorChanges    *prometheus.CounterVec
+	registerFailures prometheus.Counter
+}
+
+func newMetrics(registry *prometheus.Registry) *metrics {
+	m := &metrics{
+		emissions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "simulator_emissions_total",
+			Help: "Readings emitted, by sensor.",
+		}, []string{"sensor_id"}),
+		emitFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "simulator_emission_failures_total",
+			Help: "Readings that could not be generated, by sensor.",
+		}, []string{"sensor_id"}),
+		publishDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "simulator_publish_duration_seconds",
+			Help:    "Time to publish a reading to the MQTT broker, until it is acknowledged.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 12),
+		}),
+		publishFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "simulator_publish_failures_total",
+			Help: "Readings that could not be published to the MQTT broker.",
+		}),
+		activeWorkers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "simulator_active_workers",
+			Help: "Sensor workers currently emitting readings.",
+		}),
+		sensorChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "simulator_sensor_changes_total",
+			Help: "Changes to the sensor collection received from its change stream, by operation.",
+		}, []string{"op"}),
+		registerFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "simulator_registration_failures_total",
+			Help: "Attempts to register the key of a sensor with the relayer that failed.",
+		}),
+	}
+	registry.MustRegister(m.emissions, m.emitFailures, m.publishDuration, m.publishFailures, m.activeWorkers, m.sensorChanges, m.registerFailures)
+	return m
+}
+
+func (m *metrics) observePublish(elapsed time.Duration, err error) {
+	m.publishDuration.Observe(elapsed.Seconds())
+	if err != nil {
+		m.publishFailures.Inc()
+	}
+}
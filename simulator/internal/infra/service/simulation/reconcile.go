@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henriquemarlon/city.fun/simulator/internal/domain/entity"
+	"github.com/henriquemarlon/city.fun/simulator/internal/usecase"
+)
+
+// reconcileSensors starts a worker for every sensor without one, such as sensors inserted
+// directly into the database, and stops the workers of sensors that no longer exist.
+func (s *Service) reconcileSensors(sensors []usecase.FindAllSensorsOutputDTO) {
+	known := make(map[string]bool, len(sensors))
+	for _, sensor := range sensors {
+		known[sensor.Id.Hex()] = true
+	}
+
+	var missing []*entity.Sensor
+	s.workersMu.Lock()
+	for id, w := range s.workers {
+		if !known[id] {
+			s.Logger.Info("Sensor removed from database, stopping worker", "id", id)
+			w.cancel()
+		}
+	}
+	for _, sensor := range sensors {
+		if _, running := s.workers[sensor.Id.Hex()]; !running {
+			missing = append(missing, toSensor(sensor))
+		}
+	}
+	s.workersMu.Unlock()
+
+	for _, sensor := range missing {
+		s.Logger.Info("Sensor without worker found in database, starting it", "id", sensor.Id.Hex(), "name", sensor.Name)
+		if !s.enqueueSensor(sensor) {
+			return
+		}
+	}
+}
+
+// enqueueSensor hands a sensor to the worker pool. It returns false when the pool is stopping.
+func (s *Service) enqueueSensor(sensor *entity.Sensor) bool {
+	select {
+	case s.sensorChannel <- sensor:
+		return true
+	case <-s.stopWorkerPool:
+		return false
+	case <-s.Context.Done():
+		return false
+	}
+}
+
+func toSensor(sensor usecase.FindAllSensorsOutputDTO) *entity.Sensor {
+	return &entity.Sensor{
+		Id:        sensor.Id,
+		Name:      sensor.Name,
+		Latitude:  sensor.Latitude,
+		Longitude: sensor.Longitude,
+		Receiver:  sensor.Receiver,
+		Amount:    sensor.Amount,
+		Params:    sensor.Params,
+	}
+}
+
+// loadSensors reads every sensor from the database and reconciles the workers with them.
+func (s *Service) loadSensors(ctx context.Context) error {
+	findAllSensors := usecase.NewFindAllSensorsUseCase(s.repository)
+	sensors, err := findAllSensors.Execute(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load sensors: %w", err)
+	}
+	s.reconcileSensors(sensors)
+	return nil
+}
@@ -0,0 +1,72 @@
+package simulation
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/simulator/configs"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/service"
+)
+
+// pushSchedule holds the push interval shared by the sensor workers. Changing it closes the
+// channel returned with the previous interval, so that every worker resets its ticker.
+type pushSchedule struct {
+	mu       sync.Mutex
+	interval time.Duration
+	changed  chan struct{}
+}
+
+func (p *pushSchedule) get() (time.Duration, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.interval, p.changed
+}
+
+func (p *pushSchedule) set(interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.interval = interval
+	if p.changed != nil {
+		close(p.changed)
+	}
+	p.changed = make(chan struct{})
+}
+
+// Reload reads the configuration again and applies the log level and the push interval.
+// Changes to any other setting are reported as errors, since they only take effect on restart.
+func (s *Service) Reload() []error {
+	config, err := configs.LoadSimulatorConfig()
+	if err != nil {
+		return []error{fmt.Errorf("failed to load config: %w", err)}
+	}
+
+	changed := service.ChangedSettings(s.config, *config)
+	if len(changed) == 0 {
+		return nil
+	}
+	s.Logger.Info("Configuration changed", "settings", changed)
+
+	var errs []error
+	applied := s.config
+	if config.LogLevel != applied.LogLevel {
+		s.LogLevel.Set(config.LogLevel)
+		applied.LogLevel = config.LogLevel
+	}
+	if config.PushInterval != applied.PushInterval {
+		if config.PushInterval <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be positive", configs.PUSH_INTERVAL))
+			config.PushInterval = applied.PushInterval
+		} else {
+			s.schedule.set(config.PushInterval)
+			applied.PushInterval = config.PushInterval
+		}
+	}
+
+	for _, name := range service.ChangedSettings(applied, *config) {
+		errs = append(errs, fmt.Errorf("%s: %w", name, service.ErrRestartRequired))
+	}
+	s.config = applied
+	return errs
+}
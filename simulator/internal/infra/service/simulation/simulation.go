@@ -2,6 +2,7 @@ package simulation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -12,36 +13,49 @@ import (
 	"github.com/henriquemarlon/city.fun/simulator/configs"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/service"
 
-	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"github.com/henriquemarlon/city.fun/simulator/internal/domain/entity"
 	"github.com/henriquemarlon/city.fun/simulator/internal/domain/event"
 	event_handler "github.com/henriquemarlon/city.fun/simulator/internal/domain/event/handler"
 	"github.com/henriquemarlon/city.fun/simulator/internal/infra/repository"
 	"github.com/henriquemarlon/city.fun/simulator/internal/infra/service/simulation/handler"
 	"github.com/henriquemarlon/city.fun/simulator/internal/usecase"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/device"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/events"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/mqtt"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Service struct {
 	service.Service
 	mqttTopic       string
-	mqttClient      MQTT.Client
+	mqttClient      *mqtt.Client
 	sensorServer    *http.Server
 	stopWorkerPool  chan struct{}
 	wg              sync.WaitGroup
 	sensorChannel   chan *entity.Sensor
+	workers         map[string]*sensorWorker // sensor workers by sensor id
+	workersMu       sync.Mutex
+	stopWatcher     context.CancelFunc
+	watcherDone     chan struct{}
 	repository      repository.Repository
 	eventDispatcher events.EventDispatcherInterface
-	pushInterval    time.Duration
+	config          configs.SimulatorConfig // configuration in effect, updated by Reload
+	schedule        pushSchedule
+	metrics         *metrics
+	deviceRegistry  *device.Registry // nil when the keys are registered by hand
 }
 
 type CreateInfo struct {
 	service.CreateInfo
-	MqttClient      MQTT.Client
+	MqttClient      *mqtt.Client
 	Repository      repository.Repository
 	Config          configs.SimulatorConfig
 	EventDispatcher events.EventDispatcherInterface
+	DeviceRegistry  *device.Registry
 }
 
 func Create(ctx context.Context, createInfo *CreateInfo) (*Service, error) {
@@ -73,10 +87,23 @@ func Create(ctx context.Context, createInfo *CreateInfo) (*Service, error) {
 		return nil, fmt.Errorf("event dispatcher on simulation service create is nil")
 	}
 
+	s.deviceRegistry = createInfo.DeviceRegistry
+
+	s.Health.Register("mongodb", s.repository.Ping)
+	s.Health.Register("mqtt", func(ctx context.Context) error {
+		if !s.mqttClient.IsConnected() {
+			return errors.New("not connected to the MQTT broker")
+		}
+		return nil
+	})
+
 	s.sensorChannel = make(chan *entity.Sensor)
+	s.workers = make(map[string]*sensorWorker)
 	s.stopWorkerPool = make(chan struct{})
-	s.pushInterval = createInfo.Config.PushInterval
+	s.config = createInfo.Config
+	s.schedule.set(createInfo.Config.PushInterval)
 	s.mqttTopic = createInfo.Config.HivemqMqttTopic
+	s.metrics = newMetrics(s.Registry)
 
 	go s.runWorkerPool()
 
@@ -90,15 +117,7 @@ func Create(ctx context.Context, createInfo *CreateInfo) (*Service, error) {
 
 	for _, sensor := range sensors {
 		s.Logger.Debug("Enqueuing sensor", "id", sensor.Id.Hex(), "name", sensor.Name)
-		s.sensorChannel <- &entity.Sensor{
-			Id:        sensor.Id,
-			Name:      sensor.Name,
-			Latitude:  sensor.Latitude,
-			Longitude: sensor.Longitude,
-			Receiver:  sensor.Receiver,
-			Amount:    sensor.Amount,
-			Params:    sensor.Params,
-		}
+		s.sensorChannel <- toSensor(sensor)
 		s.Logger.Debug("Sensor enqueued", "id", sensor.Id.Hex())
 	}
 
@@ -112,13 +131,22 @@ func Create(ctx context.Context, createInfo *CreateInfo) (*Service, error) {
 		Handler: cors.Default().Handler(mux),
 	}
 
+	var watchCtx context.Context
+	watchCtx, s.stopWatcher = context.WithCancel(s.Context)
+	s.watcherDone = make(chan struct{})
+	go s.watchSensors(watchCtx)
+
 	return s, nil
 }
 
-func (s *Service) Alive() bool     { return s.mqttClient != nil && s.mqttClient.IsConnected() }
-func (s *Service) Ready() bool     { return s.mqttClient != nil && s.mqttClient.IsConnected() }
-func (s *Service) Reload() []error { return nil }
+func (s *Service) Alive() bool { return s.mqttClient != nil && s.mqttClient.IsConnected() }
+func (s *Service) Ready() bool { return s.mqttClient != nil && s.mqttClient.IsConnected() }
+
+// Tick reconciles the sensor workers with the sensor collection. See reconcileSensors.
 func (s *Service) Tick() []error {
+	if err := s.loadSensors(s.Context); err != nil {
+		return []error{err}
+	}
 	return nil
 }
 
@@ -139,6 +167,11 @@ func (s *Service) String() string {
 func (s *Service) Stop(force bool) []error {
 	var errs []error
 
+	if s.stopWatcher != nil {
+		s.stopWatcher()
+		<-s.watcherDone
+	}
+
 	if s.stopWorkerPool != nil {
 		close(s.stopWorkerPool)
 	}
@@ -150,120 +183,171 @@ func (s *Service) Stop(force bool) []error {
 		errs = append(errs, err)
 	}
 
-	if s.mqttClient != nil && s.mqttClient.IsConnected() {
-		s.mqttClient.Disconnect(250)
+	if s.mqttClient != nil {
+		if err := s.mqttClient.Disconnect(ctx); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	return errs
 }
 
-func (s *Service) runWorkerPool() {
-	activeSensors := make(map[string]context.CancelFunc)
-	var mu sync.RWMutex
+type sensorWorker struct {
+	cancel context.CancelFunc
+}
 
+func (s *Service) runWorkerPool() {
 	for {
 		select {
 		case sensor := <-s.sensorChannel:
 			s.Logger.Debug("Received sensor from channel", "id", sensor.Id.Hex(), "name", sensor.Name)
 
-			mu.RLock()
-			_, exists := activeSensors[sensor.Id.Hex()]
-			mu.RUnlock()
-
-			if exists {
-				s.Logger.Debug("Sensor worker already running", "id", sensor.Id.Hex())
+			id := sensor.Id.Hex()
+			s.workersMu.Lock()
+			if _, exists := s.workers[id]; exists {
+				s.workersMu.Unlock()
+				s.Logger.Debug("Sensor worker already running", "id", id)
 				continue
 			}
+			// The worker is registered before it starts, so that a sensor enqueued twice in a
+			// row gets a single worker.
+			workerCtx, cancel := context.WithCancel(s.Context)
+			w := &sensorWorker{cancel: cancel}
+			s.workers[id] = w
+			s.workersMu.Unlock()
 
 			s.wg.Add(1)
-			go func(id string) {
+			go func() {
 				defer s.wg.Done()
-
-				workerCtx, cancel := context.WithCancel(s.Context)
-
-				mu.Lock()
-				activeSensors[id] = cancel
-				mu.Unlock()
-
 				defer func() {
-					mu.Lock()
-					delete(activeSensors, id)
-					mu.Unlock()
-				}()
-
-				objectID, err := primitive.ObjectIDFromHex(id)
-				if err != nil {
-					s.Logger.Error("Failed to parse sensor ID", "id", id, "error", err)
-					return
-				}
-
-				findSensorById := usecase.NewFindSensorByIdUseCase(s.repository)
-				sensorOutput, err := findSensorById.Execute(workerCtx, &usecase.FindSensorByIdInputDTO{
-					Id: objectID,
-				})
-				if err != nil {
-					s.Logger.Error("Failed to find sensor", "id", id, "error", err)
-					return
-				}
-
-				sensor := &entity.Sensor{
-					Id:        sensorOutput.Id,
-					Name:      sensorOutput.Name,
-					Latitude:  sensorOutput.Latitude,
-					Longitude: sensorOutput.Longitude,
-					Params:    sensorOutput.Params,
-				}
-
-				dataEmittedEvent := event.NewDataEmitted(sensor.Id.Hex())
-				dataEmittedHandler := event_handler.NewDataEmittedHandler(s.mqttClient, s.mqttTopic)
-				if err := s.eventDispatcher.Register(dataEmittedEvent.GetName(), dataEmittedHandler); err != nil {
-					s.Logger.Error("Failed to register event handler", "id", sensor.Id.Hex(), "error", err)
-					return
-				}
-
-				s.Logger.Info("Starting sensor worker", "id", sensor.Id.Hex(), "name", sensor.Name)
-
-				emitData := usecase.NewEmitDataUseCase(dataEmittedEvent, s.repository, s.eventDispatcher)
-
-				ticker := time.NewTicker(s.pushInterval)
-				defer ticker.Stop()
-
-				for {
-					select {
-					case <-workerCtx.Done():
-						s.Logger.Info("Stopping sensor worker", "id", sensor.Id.Hex())
-						return
-
-					case <-ticker.C:
-						res, err := emitData.Execute(workerCtx, &usecase.EmitDataInputDTO{
-							Id: sensor.Id,
-						})
-						if err != nil {
-							s.Logger.Error("Failed to emit data", "id", sensor.Id.Hex(), "error", err)
-							continue
-						}
-
-						s.Logger.Info(
-							"Data emitted",
-							"id", sensor.Id.Hex(),
-							"name", sensor.Name,
-							"latitude", sensor.Latitude,
-							"longitude", sensor.Longitude,
-							"data", string(res.Data),
-						)
+					// A restarted sensor already has a new worker registered in its place.
+					s.workersMu.Lock()
+					if s.workers[id] == w {
+						delete(s.workers, id)
 					}
-				}
-			}(sensor.Id.Hex())
+					s.workersMu.Unlock()
+					cancel()
+				}()
+				s.runSensor(workerCtx, id)
+			}()
 
 		case <-s.stopWorkerPool:
-			mu.Lock()
-			for _, cancel := range activeSensors {
-				cancel()
+			s.workersMu.Lock()
+			for _, w := range s.workers {
+				w.cancel()
 			}
-			mu.Unlock()
+			s.workersMu.Unlock()
 
 			s.wg.Wait()
 			return
 		}
 	}
 }
+
+// runSensor emits the readings of a sensor every push interval until workerCtx is done.
+func (s *Service) runSensor(workerCtx context.Context, id string) {
+	s.metrics.activeWorkers.Inc()
+	defer s.metrics.activeWorkers.Dec()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		s.Logger.Error("Failed to parse sensor ID", "id", id, "error", err)
+		return
+	}
+
+	findSensorById := usecase.NewFindSensorByIdUseCase(s.repository)
+	sensorOutput, err := findSensorById.Execute(workerCtx, &usecase.FindSensorByIdInputDTO{
+		Id: objectID,
+	})
+	if err != nil {
+		s.Logger.Error("Failed to find sensor", "id", id, "error", err)
+		return
+	}
+
+	// Sensors created without a key pair get one before their first reading. Storing it
+	// restarts the worker, which then signs with it.
+	ensureKey := usecase.NewEnsureSensorKeyUseCase(s.repository)
+	key, err := ensureKey.Execute(workerCtx, &usecase.EnsureSensorKeyInputDTO{Id: objectID})
+	if err != nil {
+		s.Logger.Error("Failed to ensure sensor key", "id", id, "error", err)
+		return
+	}
+	if key.Generated {
+		s.Logger.Info("Sensor key pair generated", "id", id, "public_key", key.PublicKey)
+	}
+	if s.deviceRegistry != nil {
+		go s.registerDevice(workerCtx, device.Registration{
+			SensorId:  id,
+			PublicKey: key.PublicKey,
+		})
+	}
+
+	sensor := &entity.Sensor{
+		Id:        sensorOutput.Id,
+		Name:      sensorOutput.Name,
+		Latitude:  sensorOutput.Latitude,
+		Longitude: sensorOutput.Longitude,
+		Params:    sensorOutput.Params,
+	}
+
+	dataEmittedEvent := event.NewDataEmitted(sensor.Id.Hex())
+	dataEmittedHandler := event_handler.NewDataEmittedHandler(s.mqttClient, s.mqttTopic)
+	dataEmittedHandler.OnPublish = s.metrics.observePublish
+	if err := s.eventDispatcher.Register(dataEmittedEvent.GetName(), dataEmittedHandler); err != nil {
+		s.Logger.Error("Failed to register event handler", "id", sensor.Id.Hex(), "error", err)
+		return
+	}
+	// A restarted worker registers a handler of its own, so every reading is published once.
+	defer s.eventDispatcher.Remove(dataEmittedEvent.GetName(), dataEmittedHandler)
+
+	s.Logger.Info("Starting sensor worker", "id", sensor.Id.Hex(), "name", sensor.Name)
+
+	emitData := usecase.NewEmitDataUseCase(dataEmittedEvent, s.repository, s.eventDispatcher)
+
+	pushInterval, changed := s.schedule.get()
+	ticker := time.NewTicker(pushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-workerCtx.Done():
+			s.Logger.Info("Stopping sensor worker", "id", sensor.Id.Hex())
+			return
+
+		case <-changed:
+			pushInterval, changed = s.schedule.get()
+			ticker.Reset(pushInterval)
+
+		case <-ticker.C:
+			// Every reading starts a trace, followed by the relayer up to its mint.
+			emitCtx, span := tracing.Tracer("simulator").Start(workerCtx, "sensor.emit",
+				trace.WithNewRoot(),
+				trace.WithAttributes(attribute.String("sensor.id", sensor.Id.Hex())))
+			res, err := emitData.Execute(emitCtx, &usecase.EmitDataInputDTO{
+				Id: sensor.Id,
+			})
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "emit failed")
+			} else {
+				span.SetAttributes(attribute.String("reading.id", res.ReadingId))
+			}
+			span.End()
+			if err != nil {
+				s.metrics.emitFailures.WithLabelValues(sensor.Id.Hex()).Inc()
+				s.Logger.Error("Failed to emit data", "id", sensor.Id.Hex(), "error", err)
+				continue
+			}
+			s.metrics.emissions.WithLabelValues(sensor.Id.Hex()).Inc()
+
+			s.Logger.Info(
+				"Data emitted",
+				"id", sensor.Id.Hex(),
+				"name", sensor.Name,
+				"latitude", sensor.Latitude,
+				"longitude", sensor.Longitude,
+				"data", string(res.Data),
+			)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/henriquemarlon/city.fun/simulator/internal/infra/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	watchMinBackoff = time.Second
+	watchMaxBackoff = time.Minute
+)
+
+// watchSensors follows the changes to the sensor collection until ctx is done: inserted
+// sensors start a worker, updated sensors restart theirs and deleted sensors stop it. The
+// stream is reopened after the last change it delivered, so that reconnecting loses none.
+// When it cannot be resumed, the sensors are loaded again before watching from scratch.
+// Tick keeps reconciling the workers either way, and is all that is left when the database
+// cannot stream changes.
+func (s *Service) watchSensors(ctx context.Context) {
+	defer close(s.watcherDone)
+
+	var resumeToken bson.Raw
+	backoff := watchMinBackoff
+	for {
+		fresh := resumeToken == nil
+		opened := func() {
+			s.Logger.Info("Watching sensor changes", "resumed", !fresh)
+			backoff = watchMinBackoff
+			// Changes made before a new stream was opened are not in it.
+			if fresh {
+				if err := s.loadSensors(ctx); err != nil {
+					s.Logger.Error("Failed to reconcile sensors", "error", err)
+				}
+			}
+		}
+		err := s.repository.WatchSensors(ctx, resumeToken, opened, func(change repository.SensorChange) error {
+			s.applySensorChange(change)
+			resumeToken = change.ResumeToken
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch {
+		case errors.Is(err, repository.ErrWatchNotSupported):
+			s.Logger.Warn("Sensor changes cannot be watched, relying on periodic reconciliation", "error", err)
+			return
+		case errors.Is(err, repository.ErrResumeTokenLost):
+			s.Logger.Warn("Sensor change stream cannot be resumed, reloading sensors", "error", err)
+			resumeToken = nil
+		default:
+			s.Logger.Error("Sensor change stream failed, reconnecting", "error", err, "backoff", backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, watchMaxBackoff)
+	}
+}
+
+func (s *Service) applySensorChange(change repository.SensorChange) {
+	id := change.Id.Hex()
+	s.metrics.sensorChanges.WithLabelValues(string(change.Op)).Inc()
+
+	switch change.Op {
+	case repository.SensorInserted:
+		s.Logger.Info("Sensor inserted, starting worker", "id", id, "name", change.Sensor.Name)
+		s.enqueueSensor(change.Sensor)
+
+	case repository.SensorUpdated:
+		s.Logger.Info("Sensor updated, restarting worker", "id", id, "name", change.Sensor.Name)
+		s.stopSensorWorker(id)
+		s.enqueueSensor(change.Sensor)
+
+	case repository.SensorDeleted:
+		if s.stopSensorWorker(id) {
+			s.Logger.Info("Sensor deleted, stopped worker", "id", id)
+		}
+	}
+}
+
+// stopSensorWorker cancels the worker of a sensor and unregisters it right away, so that the
+// sensor can be enqueued again before the worker has returned. It reports whether the sensor
+// had a worker.
+func (s *Service) stopSensorWorker(id string) bool {
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+
+	w, ok := s.workers[id]
+	if !ok {
+		return false
+	}
+	w.cancel()
+	delete(s.workers, id)
+	return true
+}
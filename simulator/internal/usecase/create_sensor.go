@@ -5,6 +5,7 @@ import (
 
 	"github.com/henriquemarlon/city.fun/simulator/internal/domain/entity"
 	"github.com/henriquemarlon/city.fun/simulator/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/device"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/events"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -32,6 +33,7 @@ type CreateSensorOutputDTO struct {
 	Receiver  string                  `json:"receiver"`
 	Amount    string                  `json:"amount"`
 	Params    map[string]entity.Param `json:"params"`
+	PublicKey string                  `json:"public_key"` // key the readings of the sensor are verified with
 }
 
 func NewCreateSensorUseCase(sensorCreated events.EventInterface, sensorRepository repository.SensorRepository, eventDispatcher events.EventDispatcherInterface) *CreateSensorUseCase {
@@ -44,6 +46,16 @@ func NewCreateSensorUseCase(sensorCreated events.EventInterface, sensorRepositor
 
 func (c *CreateSensorUseCase) Execute(ctx context.Context, input *CreateSensorInputDTO) (*CreateSensorOutputDTO, error) {
 	sensor := entity.NewSensor(input.Name, input.Latitude, input.Longitude, input.Receiver, input.Amount, input.Params)
+	if sensor == nil {
+		return nil, entity.ErrInvalidSensor
+	}
+
+	var err error
+	sensor.PublicKey, sensor.PrivateKey, err = device.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := c.SensorRepository.CreateSensor(ctx, sensor)
 	if err != nil {
 		return nil, err
@@ -57,6 +69,7 @@ func (c *CreateSensorUseCase) Execute(ctx context.Context, input *CreateSensorIn
 		Receiver:  res.Receiver,
 		Amount:    res.Amount,
 		Params:    res.Params,
+		PublicKey: res.PublicKey,
 	}
 
 	c.SensorCreated.SetPayload(dto)
@@ -3,13 +3,21 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/henriquemarlon/city.fun/simulator/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/device"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/events"
 	"github.com/henriquemarlon/city.fun/simulator/pkg/sampling"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/tracing"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// ErrSensorKeyMissing is returned for a sensor that has no key pair to sign its readings yet.
+var ErrSensorKeyMissing = errors.New("sensor has no signing key")
+
 type EmitDataUseCase struct {
 	DataEmitted      events.EventInterface
 	SensorRepository repository.SensorRepository
@@ -21,12 +29,17 @@ type EmitDataInputDTO struct {
 }
 
 type EmitDataOutputDTO struct {
-	Name      string  `json:"name"`
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Receiver  string  `json:"receiver"`
-	Amount    string  `json:"amount"`
-	Data      string  `json:"data"` // JSON string
+	ReadingId string             `json:"reading_id"` // unique per emission, used by the relayer to pay each reading once
+	SensorId  primitive.ObjectID `json:"sensor_id"`
+	Name      string             `json:"name"`
+	Latitude  float64            `json:"latitude"`
+	Longitude float64            `json:"longitude"`
+	Receiver  string             `json:"receiver"`
+	Amount    string             `json:"amount"`
+	EmittedAt time.Time          `json:"emitted_at"` // when the reading was taken, for the relayer to reject stale ones
+	Sequence  int64              `json:"sequence"`   // increases with every reading of the sensor, for the relayer to reject replays
+	Data      string             `json:"data"`       // JSON string
+	Signature string             `json:"signature"`  // signature of the device over the canonical payload, see pkg/device
 }
 
 func NewEmitDataUseCase(
@@ -47,28 +60,61 @@ func (e *EmitDataUseCase) Execute(ctx context.Context, input *EmitDataInputDTO)
 		return nil, err
 	}
 
+	_, span := tracing.Tracer("simulator").Start(ctx, "sensor.sample")
 	s := sampling.NewConfidenceIntervalGenerator()
 
 	data := make(map[string]float64, len(res.Params))
 	for key, interval := range res.Params {
 		data[key] = s.GenerateValue(interval.Min, interval.Max, interval.Factor)
 	}
+	span.SetAttributes(attribute.Int("sensor.params", len(data)))
+	span.End()
 
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
 
+	if res.PrivateKey == "" {
+		return nil, ErrSensorKeyMissing
+	}
+
+	sequence, err := e.SensorRepository.NextSensorSequence(ctx, res.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	dto := &EmitDataOutputDTO{
+		ReadingId: primitive.NewObjectID().Hex(),
+		SensorId:  res.Id,
 		Name:      res.Name,
 		Latitude:  res.Latitude,
 		Longitude: res.Longitude,
 		Receiver:  res.Receiver,
 		Amount:    res.Amount,
+		EmittedAt: time.Now().UTC().Truncate(time.Millisecond),
+		Sequence:  sequence,
 		Data:      string(dataBytes),
 	}
+	dto.Signature, err = device.Sign(res.PrivateKey, device.Payload{
+		ReadingId: dto.ReadingId,
+		SensorId:  dto.SensorId.Hex(),
+		Receiver:  dto.Receiver,
+		Amount:    dto.Amount,
+		Latitude:  dto.Latitude,
+		Longitude: dto.Longitude,
+		EmittedAt: dto.EmittedAt,
+		Sequence:  dto.Sequence,
+		Data:      dto.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	e.DataEmitted.SetPayload(dto)
+	if event, ok := e.DataEmitted.(events.ContextEvent); ok {
+		event.SetContext(ctx)
+	}
 	if err := e.EventDispatcher.Dispatch(e.DataEmitted); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/henriquemarlon/city.fun/simulator/internal/infra/repository"
+	"github.com/henriquemarlon/city.fun/simulator/pkg/device"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EnsureSensorKeyUseCase struct {
+	SensorRepository repository.SensorRepository
+}
+
+type EnsureSensorKeyInputDTO struct {
+	Id primitive.ObjectID `json:"id"`
+}
+
+type EnsureSensorKeyOutputDTO struct {
+	Id        primitive.ObjectID `json:"id"`
+	PublicKey string             `json:"public_key"`
+	Generated bool               `json:"generated"` // the sensor had no key pair and got a new one
+}
+
+func NewEnsureSensorKeyUseCase(sensorRepository repository.SensorRepository) *EnsureSensorKeyUseCase {
+	return &EnsureSensorKeyUseCase{SensorRepository: sensorRepository}
+}
+
+// Execute gives a key pair to a sensor created without one, such as the seeded ones, and
+// returns its public key.
+func (e *EnsureSensorKeyUseCase) Execute(ctx context.Context, input *EnsureSensorKeyInputDTO) (*EnsureSensorKeyOutputDTO, error) {
+	sensor, err := e.SensorRepository.FindSensorById(ctx, input.Id)
+	if err != nil {
+		return nil, err
+	}
+	if sensor.PrivateKey != "" {
+		return &EnsureSensorKeyOutputDTO{Id: sensor.Id, PublicKey: sensor.PublicKey}, nil
+	}
+
+	publicKey, privateKey, err := device.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	sensor, err = e.SensorRepository.SetSensorKey(ctx, input.Id, publicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EnsureSensorKeyOutputDTO{
+		Id:        sensor.Id,
+		PublicKey: sensor.PublicKey,
+		Generated: sensor.PublicKey == publicKey,
+	}, nil
+}
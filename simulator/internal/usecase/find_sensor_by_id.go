@@ -24,6 +24,7 @@ type FindSensorByIdOutputDTO struct {
 	Receiver  string                  `json:"receiver"`
 	Amount    string                  `json:"amount"`
 	Params    map[string]entity.Param `json:"params"`
+	PublicKey string                  `json:"public_key,omitempty"`
 }
 
 func NewFindSensorByIdUseCase(sensorRepository repository.SensorRepository) *FindSensorByIdUseCase {
@@ -43,5 +44,6 @@ func (f *FindSensorByIdUseCase) Execute(ctx context.Context, input *FindSensorBy
 		Receiver:  sensor.Receiver,
 		Amount:    sensor.Amount,
 		Params:    sensor.Params,
+		PublicKey: sensor.PublicKey,
 	}, nil
 }
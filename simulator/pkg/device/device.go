@@ -0,0 +1,68 @@
+// Package device gives the simulated sensors an identity: an ed25519 key pair whose private
+// key signs the canonical form of every reading, for the relayer to verify against the
+// registered public key. Keys and signatures are hex encoded.
+package device
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// canonicalVersion starts the canonical form, which must match the one the relayer verifies.
+const canonicalVersion = "city.fun/reading/v2"
+
+var ErrInvalidPrivateKey = errors.New("invalid device private key")
+
+// Payload is the signed part of a reading.
+type Payload struct {
+	ReadingId string
+	SensorId  string
+	Receiver  string
+	Amount    string
+	Latitude  float64
+	Longitude float64
+	EmittedAt time.Time
+	Sequence  int64
+	Data      string
+}
+
+// Canonical returns the bytes a device signs: the version and the fields of the payload, one
+// per line. Data is the last field, so that the lines it may hold cannot be mistaken for
+// other fields.
+func (p Payload) Canonical() []byte {
+	return []byte(strings.Join([]string{
+		canonicalVersion,
+		p.ReadingId,
+		p.SensorId,
+		p.Receiver,
+		p.Amount,
+		strconv.FormatFloat(p.Latitude, 'g', -1, 64),
+		strconv.FormatFloat(p.Longitude, 'g', -1, 64),
+		p.EmittedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatInt(p.Sequence, 10),
+		p.Data,
+	}, "\n"))
+}
+
+// GenerateKey returns a new public key and the seed of its private key.
+func GenerateKey() (publicKey, privateKey string, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(public), hex.EncodeToString(private.Seed()), nil
+}
+
+// Sign returns the signature of a payload with the private key seed of a device.
+func Sign(privateKey string, payload Payload) (string, error) {
+	seed, err := hex.DecodeString(privateKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", ErrInvalidPrivateKey
+	}
+	return hex.EncodeToString(ed25519.Sign(ed25519.NewKeyFromSeed(seed), payload.Canonical())), nil
+}
@@ -0,0 +1,95 @@
+package device
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var payload = Payload{
+	ReadingId: "6650b7c1e4b0a1a2b3c4d5e6",
+	SensorId:  "6650b7c1e4b0a1a2b3c4d5e7",
+	Receiver:  "0x8ba1f109551bD432803012645Ac136ddd64DBA72",
+	Amount:    "1000000000000000000",
+	Latitude:  -34.5775,
+	Longitude: -58.42,
+	EmittedAt: time.Date(2025, 1, 1, 12, 0, 0, 250000000, time.FixedZone("ART", -3*60*60)),
+	Sequence:  42,
+	Data:      `{"co2":512.5,"mp25":61}`,
+}
+
+func TestPayload_Canonical(t *testing.T) {
+	// The relayer verifies the same form, any change must be made on both sides.
+	assert.Equal(t, "city.fun/reading/v2\n"+
+		"6650b7c1e4b0a1a2b3c4d5e6\n"+
+		"6650b7c1e4b0a1a2b3c4d5e7\n"+
+		"0x8ba1f109551bD432803012645Ac136ddd64DBA72\n"+
+		"1000000000000000000\n"+
+		"-34.5775\n"+
+		"-58.42\n"+
+		"2025-01-01T15:00:00.25Z\n"+
+		"42\n"+
+		`{"co2":512.5,"mp25":61}`, string(payload.Canonical()))
+}
+
+func TestSign(t *testing.T) {
+	publicKey, privateKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	signature, err := Sign(privateKey, payload)
+	require.NoError(t, err)
+
+	key, err := hex.DecodeString(publicKey)
+	require.NoError(t, err)
+	sig, err := hex.DecodeString(signature)
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(key, payload.Canonical(), sig))
+
+	tampered := payload
+	tampered.Amount = "2000000000000000000"
+	assert.False(t, ed25519.Verify(key, tampered.Canonical(), sig))
+
+	_, err = Sign("", payload)
+	assert.ErrorIs(t, err, ErrInvalidPrivateKey)
+}
+
+func TestRegistry_Register(t *testing.T) {
+	bodies := map[string]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["public_key"] == "other" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		bodies[r.URL.Path] = body
+	}))
+	defer server.Close()
+
+	registration := Registration{SensorId: "sensor-1", PublicKey: "abcd"}
+	require.NoError(t, NewRegistry(server.URL+"/", "secret").Register(context.Background(), registration))
+	// Payouts are left to operators.
+	assert.Equal(t, map[string]map[string]string{
+		"/api/v1/enrollments/sensor-1": {"public_key": "abcd"},
+	}, bodies)
+
+	err := NewRegistry(server.URL, "wrong").Register(context.Background(), registration)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrKeyRegistered)
+
+	registration.PublicKey = "other"
+	err = NewRegistry(server.URL, "secret").Register(context.Background(), registration)
+	assert.ErrorIs(t, err, ErrKeyRegistered)
+}
@@ -0,0 +1,78 @@
+package device
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrKeyRegistered is returned when the relayer already has another public key for a sensor,
+// which only an operator can replace.
+var ErrKeyRegistered = errors.New("another key is registered for the device")
+
+// Registry enrolls the public keys of the devices with the enrollment API of the relayer. It
+// can only register the key of a sensor that has none: replacing a key, and who a sensor pays
+// and how much, are left to an operator, never to the simulator.
+type Registry struct {
+	URL    string // base URL of the relayer, such as http://relayer:8084
+	Token  string // enrollment token of the relayer
+	Client *http.Client
+}
+
+func NewRegistry(url, token string) *Registry {
+	return &Registry{
+		URL:    strings.TrimSuffix(url, "/"),
+		Token:  token,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Registration is what the relayer needs to know of a device.
+type Registration struct {
+	SensorId  string
+	PublicKey string // key the readings are verified with
+}
+
+// Register enrolls the public key of a sensor. Enrolling the same key again changes nothing,
+// and another key than the registered one fails with ErrKeyRegistered.
+func (r *Registry) Register(ctx context.Context, registration Registration) error {
+	id := url.PathEscape(registration.SensorId)
+	if err := r.post(ctx, "/api/v1/enrollments/"+id, map[string]string{"public_key": registration.PublicKey}); err != nil {
+		return fmt.Errorf("failed to register device: %w", err)
+	}
+	return nil
+}
+
+func (r *Registry) post(ctx context.Context, path string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.Token)
+
+	res, err := r.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusConflict {
+		return ErrKeyRegistered
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("relayer returned %s", res.Status)
+	}
+	return nil
+}
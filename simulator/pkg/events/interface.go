@@ -1,6 +1,7 @@
 package events
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -12,6 +13,22 @@ type EventInterface interface {
 	SetPayload(payload interface{})
 }
 
+// ContextEvent is implemented by events that carry the context they were dispatched in, so
+// that handlers can continue its trace.
+type ContextEvent interface {
+	EventInterface
+	GetContext() context.Context
+	SetContext(ctx context.Context)
+}
+
+// EventContext returns the context an event was dispatched in, or the background context.
+func EventContext(event EventInterface) context.Context {
+	if e, ok := event.(ContextEvent); ok && e.GetContext() != nil {
+		return e.GetContext()
+	}
+	return context.Background()
+}
+
 type EventHandlerInterface interface {
 	Handle(event EventInterface, wg *sync.WaitGroup)
 }
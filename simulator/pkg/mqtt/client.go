@@ -0,0 +1,107 @@
+// Package mqtt is a small MQTT v5 publisher. MQTT v5 is needed for user properties, which
+// carry the trace context of each reading to the broker and, through its Kafka extension,
+// to the Kafka headers seen by the relayer.
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+type Config struct {
+	// Broker is the broker URL, such as tcp://localhost:1883 or tls://broker:8883.
+	Broker         string
+	ClientId       string
+	Username       string
+	Password       string
+	ConnectTimeout time.Duration
+	Logger         *slog.Logger
+}
+
+// Client keeps a connection to the broker, reconnecting when it drops.
+type Client struct {
+	manager   *autopaho.ConnectionManager
+	connected atomic.Bool
+}
+
+// Connect connects to the broker and waits for the connection to be up.
+func Connect(ctx context.Context, config Config) (*Client, error) {
+	broker, err := url.Parse(config.Broker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT broker url: %w", err)
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	c := &Client{}
+	clientConfig := autopaho.ClientConfig{
+		ServerUrls:                    []*url.URL{broker},
+		KeepAlive:                     30,
+		CleanStartOnInitialConnection: true,
+		ConnectTimeout:                config.ConnectTimeout,
+		ConnectUsername:               config.Username,
+		ConnectPassword:               []byte(config.Password),
+		OnConnectionUp: func(*autopaho.ConnectionManager, *paho.Connack) {
+			c.connected.Store(true)
+			config.Logger.Info("MQTT connection up", "broker", broker.Host)
+		},
+		OnConnectionDown: func() bool {
+			c.connected.Store(false)
+			config.Logger.Warn("MQTT connection lost", "broker", broker.Host)
+			return true
+		},
+		OnConnectError: func(err error) {
+			config.Logger.Warn("MQTT connection attempt failed", "error", err)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: config.ClientId,
+		},
+	}
+
+	// The manager lives until Disconnect, not until ctx is done.
+	c.manager, err = autopaho.NewConnection(context.Background(), clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MQTT connection: %w", err)
+	}
+	if err := c.manager.AwaitConnection(ctx); err != nil {
+		c.manager.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	return c, nil
+}
+
+// Publish sends a message with the given user properties and waits for the broker to
+// acknowledge it when qos is above 0.
+func (c *Client) Publish(ctx context.Context, topic string, qos byte, payload []byte, properties map[string]string) error {
+	publish := &paho.Publish{
+		Topic:      topic,
+		QoS:        qos,
+		Payload:    payload,
+		Properties: &paho.PublishProperties{},
+	}
+	for key, value := range properties {
+		publish.Properties.User.Add(key, value)
+	}
+
+	if _, err := c.manager.Publish(ctx, publish); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	return nil
+}
+
+func (c *Client) IsConnected() bool {
+	return c.connected.Load()
+}
+
+func (c *Client) Disconnect(ctx context.Context) error {
+	c.connected.Store(false)
+	return c.manager.Disconnect(ctx)
+}
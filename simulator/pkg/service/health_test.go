@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthRegistry_Run(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("ok", func(ctx context.Context) error { return nil })
+	registry.Register("failing", func(ctx context.Context) error { return errors.New("unreachable") })
+
+	statuses := registry.Run(context.Background())
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "ok", statuses[0].Name)
+	assert.True(t, statuses[0].Healthy)
+	assert.Empty(t, statuses[0].LastError)
+	assert.Equal(t, "failing", statuses[1].Name)
+	assert.False(t, statuses[1].Healthy)
+	assert.Equal(t, "unreachable", statuses[1].LastError)
+	assert.NotNil(t, statuses[1].LastErrorAt)
+	assert.False(t, registry.Healthy(context.Background()))
+}
+
+func TestHealthRegistry_Cache(t *testing.T) {
+	var calls atomic.Int32
+	registry := NewHealthRegistry()
+	registry.MaxAge = time.Hour
+	registry.Register("counted", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	assert.True(t, registry.Healthy(context.Background()))
+	assert.True(t, registry.Healthy(context.Background()))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHealthRegistry_Timeout(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Timeout = 10 * time.Millisecond
+	registry.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	statuses := registry.Run(context.Background())
+	require.Len(t, statuses, 1)
+	assert.False(t, statuses[0].Healthy)
+	assert.Contains(t, statuses[0].LastError, "deadline exceeded")
+}
+
+func TestHealthRegistry_NotCheckedYet(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("pending", func(ctx context.Context) error { return nil })
+
+	statuses := registry.Statuses()
+	require.Len(t, statuses, 1)
+	assert.False(t, statuses[0].Healthy)
+	assert.Equal(t, "not checked yet", statuses[0].LastError)
+}
+
+func TestHealthRegistry_RegisterWhileRunning(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.MaxAge = 0
+	registry.Register("db", func(ctx context.Context) error { return nil })
+
+	// Register replaces the check while a run calls it.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			registry.Register("db", func(ctx context.Context) error {
+				time.Sleep(100 * time.Microsecond)
+				return errors.New("down")
+			})
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+	for range 100 {
+		registry.Run(context.Background())
+	}
+	<-done
+
+	statuses := registry.Run(context.Background())
+	require.Len(t, statuses, 1)
+	assert.False(t, statuses[0].Healthy)
+}
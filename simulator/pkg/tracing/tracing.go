@@ -0,0 +1,96 @@
+// Package tracing sets up OpenTelemetry tracing and carries trace contexts across messages.
+//
+// Trace contexts travel as W3C `traceparent` and `tracestate` entries, which map one to one
+// to MQTT v5 user properties, Kafka headers and NATS headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	// Endpoint is the OTLP/HTTP collector, such as http://localhost:4318. Spans are not
+	// exported when it is empty, but trace contexts are still propagated.
+	Endpoint string
+	// SampleRatio is the fraction of new traces that are recorded. Traces started by a
+	// parent follow the decision of the parent.
+	SampleRatio float64
+	// Exporter replaces the OTLP exporter and receives every span as soon as it ends. It is
+	// meant for tests, with an in-memory exporter.
+	Exporter sdktrace.SpanExporter
+}
+
+// Setup installs the global tracer provider and propagator. The returned function flushes
+// the pending spans and must be called on shutdown.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var processor sdktrace.SpanProcessor
+	switch {
+	case config.Exporter != nil:
+		processor = sdktrace.NewSimpleSpanProcessor(config.Exporter)
+	case config.Endpoint != "":
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(config.Endpoint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		processor = sdktrace.NewBatchSpanProcessor(exporter)
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(config.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns a tracer of the global provider.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// Inject returns the trace context of ctx as message properties. It returns nil when ctx
+// carries no trace.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx with the trace context found in the message properties, if any.
+func Extract(ctx context.Context, properties map[string]string) context.Context {
+	if len(properties) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(properties))
+}